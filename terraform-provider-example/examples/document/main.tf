@@ -0,0 +1,18 @@
+# content 可以是任意类型的值，后端以 JSON 保存
+resource "example_document" "app" {
+  content = {
+    name  = "web"
+    ports = [80, 443]
+    labels = {
+      env = "dev"
+    }
+  }
+}
+
+resource "example_document" "hosts" {
+  content = ["10.0.0.10", "10.0.0.20"]
+}
+
+resource "example_document" "motd" {
+  content = "hello"
+}
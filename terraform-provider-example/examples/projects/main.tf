@@ -0,0 +1,35 @@
+# 同一个后端上的多个项目。每个 provider 实例有自己的 project，
+# 资源 ID 形如 "region/project/vm-..."，别名 provider 创建的资源不会冲突。
+# 后端按区域部署时，endpoint 可以写成 "https://{region}.example.com"。
+provider "example" {
+  endpoint = var.endpoint
+  region   = "east"
+  project  = "web"
+}
+
+provider "example" {
+  alias    = "db"
+  endpoint = var.endpoint
+  region   = "east"
+  project  = "db"
+}
+
+# 虚机名称只在项目内唯一
+resource "example_regex" "web" {
+  name = "server01"
+}
+
+resource "example_regex" "db" {
+  provider = example.db
+  name     = "server01"
+}
+
+# 资源可以单独指定项目，修改 project 会重建资源
+resource "example_regex" "shared" {
+  name    = "shared01"
+  project = "shared"
+}
+
+output "ids" {
+  value = [example_regex.web.id, example_regex.db.id, example_regex.shared.id]
+}
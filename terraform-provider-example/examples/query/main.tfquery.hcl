@@ -0,0 +1,24 @@
+# terraform query 需要 Terraform 1.14 及以上版本：
+#   terraform query -generate-config-out=generated.tf
+list "example_regex" "prod" {
+  provider         = example
+  include_resource = true
+
+  config {
+    tags = {
+      env = "prod"
+    }
+  }
+}
+
+# 空的标签值只要求存在该标签
+list "example_set_nested" "managed" {
+  provider = example
+
+  config {
+    project = "p1"
+    tags = {
+      managed-by = ""
+    }
+  }
+}
@@ -0,0 +1,30 @@
+# example_set_nested 用属性形式描述网卡：
+#
+#   set_nested = [{ uuid = "net-a" }, { uuid = "net-b" }]
+#
+# set 不保证顺序，也无法表达主网卡。example_server_networks 改用块形式，
+# network 块按书写顺序保存，第一个为主网卡，调整顺序会触发更新而不是重建，
+# 已挂载网络的 port 和 mac 保持不变。
+resource "example_server_networks" "web" {
+  network {
+    uuid     = "net-a"
+    fixed_ip = "10.0.0.10"
+  }
+
+  network {
+    uuid = "net-b"
+  }
+
+  # security_group 块之间没有顺序
+  security_group {
+    id = "sg-web"
+  }
+
+  security_group {
+    id = "sg-ssh"
+  }
+}
+
+output "primary_port" {
+  value = example_server_networks.web.network[0].port
+}
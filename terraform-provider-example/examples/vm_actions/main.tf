@@ -0,0 +1,38 @@
+# action 需要 Terraform 1.14 及以上版本
+resource "example_regex" "vm" {
+  name = "web01"
+
+  # 修改 name 之后重启虚拟机
+  lifecycle {
+    action_trigger {
+      events  = [after_update]
+      actions = [action.example_vm_reboot.vm]
+    }
+  }
+}
+
+action "example_vm_reboot" "vm" {
+  config {
+    id = example_regex.vm.id
+  }
+}
+
+# 也可以单独执行：terraform apply -invoke=action.example_vm_resize.vm
+action "example_vm_resize" "vm" {
+  config {
+    id     = example_regex.vm.id
+    flavor = "large"
+  }
+}
+
+action "example_vm_stop" "vm" {
+  config {
+    id = example_regex.vm.id
+  }
+}
+
+action "example_vm_start" "vm" {
+  config {
+    id = example_regex.vm.id
+  }
+}
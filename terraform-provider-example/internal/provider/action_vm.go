@@ -0,0 +1,189 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ action.ActionWithConfigure = &ActionVM{}
+
+// The virtual machines of example_regex are rebooted, stopped, started and
+// resized by actions instead of by changing the resource: the backend runs
+// them as operations on /regex/{id}/actions/{action}, and they only change
+// the computed power_state and flavor. They are typically invoked from
+// lifecycle.action_trigger of the resource, or with terraform apply
+// -invoke.
+
+func NewActionVMReboot() action.Action {
+	return &ActionVM{
+		action:      "reboot",
+		description: "Reboots a running virtual machine of `example_regex`.",
+	}
+}
+
+func NewActionVMStop() action.Action {
+	return &ActionVM{
+		action:      "stop",
+		description: "Stops a running virtual machine of `example_regex`, its `power_state` becomes `stopped`.",
+	}
+}
+
+func NewActionVMStart() action.Action {
+	return &ActionVM{
+		action:      "start",
+		description: "Starts a stopped virtual machine of `example_regex`, its `power_state` becomes `running`.",
+	}
+}
+
+func NewActionVMResize() action.Action {
+	return &ActionVM{
+		action:      "resize",
+		description: "Changes the `flavor` of a virtual machine of `example_regex`. The virtual machine is restarted and running afterwards.",
+		flavor:      true,
+	}
+}
+
+// ActionVM defines the action implementation of one of the actions on
+// virtual machines.
+type ActionVM struct {
+	client *Client
+
+	action      string
+	description string
+
+	// flavor is set for actions taking the flavor attribute.
+	flavor bool
+}
+
+// vmActionAPIModel is the terraform-service representation of the request.
+type vmActionAPIModel struct {
+	Flavor string `json:"flavor,omitempty"`
+}
+
+func (a *ActionVM) Metadata(ctx context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_vm_" + a.action
+}
+
+func (a *ActionVM) Schema(ctx context.Context, req action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: a.description,
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "ID of the `example_regex` virtual machine.",
+				Required:            true,
+			},
+		},
+	}
+
+	if a.flavor {
+		resp.Schema.Attributes["flavor"] = schema.StringAttribute{
+			MarkdownDescription: "Flavor of the virtual machine, `small`, `medium` or `large`.",
+			Required:            true,
+			Validators: []validator.String{
+				stringvalidator.OneOf("small", "medium", "large"),
+			},
+		}
+	}
+}
+
+func (a *ActionVM) Configure(ctx context.Context, req action.ConfigureRequest, resp *action.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Action Configure Type",
+			fmt.Sprintf("Expected *Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	a.client = client
+}
+
+// Invoke starts the operation of the action and waits for it, sending its
+// progress to Terraform.
+func (a *ActionVM) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	var id types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("id"), &id)...)
+
+	var body vmActionAPIModel
+	if a.flavor {
+		var flavor types.String
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("flavor"), &flavor)...)
+		body.Flavor = flavor.ValueString()
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, backendID, err := a.client.scope(ctx, id)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("id"), "Invalid Resource ID", err.Error())
+		return
+	}
+
+	ctx = tflog.SetField(ctx, "action", a.action)
+
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	subject := fmt.Sprintf("%s of %s", a.action, backendID)
+
+	op, err := a.client.StartOperation(ctx, http.MethodPost, "/regex/"+url.PathEscape(backendID)+"/actions/"+a.action, body)
+	if err != nil {
+		addClientError(ctx, &resp.Diagnostics, req.Config, a.action+" vm "+backendID, err)
+		return
+	}
+
+	resp.SendProgress(action.InvokeProgressEvent{
+		Message: fmt.Sprintf("%s: started operation %s", subject, op.Id),
+	})
+
+	_, err = a.client.WaitForOperationProgress(ctx, op.URL, func(op Operation) {
+		resp.SendProgress(action.InvokeProgressEvent{
+			Message: operationProgressMessage(subject, op),
+		})
+	})
+	if err != nil {
+		addClientError(ctx, &resp.Diagnostics, req.Config, a.action+" vm "+backendID, err)
+		return
+	}
+
+	resp.SendProgress(action.InvokeProgressEvent{
+		Message: subject + ": done",
+	})
+}
+
+// operationProgressMessage describes the status of op, the operation of
+// subject.
+func operationProgressMessage(subject string, op Operation) string {
+	msg := fmt.Sprintf("%s: %s, %d%% done", subject, op.Status, op.Progress)
+	if op.Message != "" {
+		msg += ": " + op.Message
+	}
+
+	return msg
+}
@@ -0,0 +1,55 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProtocolActionVM(t *testing.T) {
+	endpoint := newLocalTestServer(t)
+	h := newProtocolHarness(t, map[string]interface{}{"endpoint": endpoint})
+
+	state := h.create("example_regex", map[string]interface{}{"name": "test01"})
+	id, _ := state["id"].(string)
+
+	progress, diags := h.invoke("example_vm_stop", map[string]interface{}{"id": id})
+	requireNoErrors(t, "InvokeAction", diags)
+
+	if len(progress) < 2 || !strings.HasPrefix(progress[0], "stop of "+id+": started operation ") || progress[len(progress)-1] != "stop of "+id+": done" {
+		t.Errorf("unexpected progress %q", progress)
+	}
+
+	_, diags = h.invoke("example_vm_stop", map[string]interface{}{"id": id})
+	requireError(t, "InvokeAction", diags, "already stopped")
+
+	_, diags = h.invoke("example_vm_start", map[string]interface{}{"id": id})
+	requireNoErrors(t, "InvokeAction", diags)
+
+	_, diags = h.invoke("example_vm_reboot", map[string]interface{}{"id": id})
+	requireNoErrors(t, "InvokeAction", diags)
+
+	_, diags = h.invoke("example_vm_resize", map[string]interface{}{"id": id, "flavor": "large"})
+	requireNoErrors(t, "InvokeAction", diags)
+
+	read := h.read("example_regex", state)
+	requireNoErrors(t, "ReadResource", read.Diagnostics)
+
+	if got := h.decode("example_regex", read.NewState); got["power_state"] != "running" || got["flavor"] != "large" {
+		t.Errorf("expected a running large VM, got %v", got)
+	}
+
+	h.destroy("example_regex", state)
+}
+
+func TestProtocolActionVM_Errors(t *testing.T) {
+	h := newProtocolHarness(t, map[string]interface{}{"endpoint": newLocalTestServer(t)})
+
+	_, diags := h.invoke("example_vm_resize", map[string]interface{}{"id": "vm-1", "flavor": "huge"})
+	requireError(t, "ValidateActionConfig", diags, "value must be one of")
+
+	_, diags = h.invoke("example_vm_reboot", map[string]interface{}{"id": "vm-missing"})
+	requireError(t, "InvokeAction", diags, "not found")
+}
@@ -0,0 +1,134 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// Values of the auth_mode provider attribute.
+const (
+	authModeNone   = "none"
+	authModeOAuth2 = "oauth2"
+	authModeHMAC   = "hmac"
+)
+
+// authOptions are the credentials of the provider block, with the
+// environment variable fallbacks applied.
+type authOptions struct {
+	Mode string
+
+	ClientId     string
+	ClientSecret string
+	TokenURL     string
+	Scopes       []string
+
+	AccessKey string
+	SecretKey string
+}
+
+// stringFromEnv returns the value of v, or of the environment variable key
+// if v is null.
+func stringFromEnv(v types.String, key string) string {
+	if v.IsNull() {
+		return os.Getenv(key)
+	}
+
+	return v.ValueString()
+}
+
+// newAuthOptions resolves the authentication settings of the provider. If
+// auth_mode is not set, OAuth2 is used when a client ID is configured.
+func newAuthOptions(ctx context.Context, data ScaffoldingProviderModel) (authOptions, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	o := authOptions{
+		Mode:         data.AuthMode.ValueString(),
+		ClientId:     stringFromEnv(data.ClientId, "EXAMPLE_CLIENT_ID"),
+		ClientSecret: stringFromEnv(data.ClientSecret, "EXAMPLE_CLIENT_SECRET"),
+		TokenURL:     data.TokenURL.ValueString(),
+		AccessKey:    stringFromEnv(data.AccessKey, "EXAMPLE_ACCESS_KEY"),
+		SecretKey:    stringFromEnv(data.SecretKey, "EXAMPLE_SECRET_KEY"),
+	}
+
+	diags.Append(data.Scopes.ElementsAs(ctx, &o.Scopes, false)...)
+
+	if o.Mode == "" {
+		o.Mode = authModeNone
+		if o.ClientId != "" || o.ClientSecret != "" {
+			o.Mode = authModeOAuth2
+		}
+	}
+
+	switch o.Mode {
+	case authModeOAuth2:
+		if o.ClientId == "" {
+			diags.AddAttributeError(
+				path.Root("client_id"),
+				"Missing OAuth2 Client ID",
+				"OAuth2 authentication requires client_id. Set client_id, or the EXAMPLE_CLIENT_ID environment variable.",
+			)
+		}
+		if o.ClientSecret == "" {
+			diags.AddAttributeError(
+				path.Root("client_secret"),
+				"Missing OAuth2 Client Secret",
+				"OAuth2 authentication requires client_secret. Set client_secret, or the EXAMPLE_CLIENT_SECRET environment variable.",
+			)
+		}
+	case authModeHMAC:
+		if o.AccessKey == "" {
+			diags.AddAttributeError(
+				path.Root("access_key"),
+				"Missing HMAC Access Key",
+				"auth_mode \"hmac\" requires access_key. Set access_key, or the EXAMPLE_ACCESS_KEY environment variable.",
+			)
+		}
+		if o.SecretKey == "" {
+			diags.AddAttributeError(
+				path.Root("secret_key"),
+				"Missing HMAC Secret Key",
+				"auth_mode \"hmac\" requires secret_key. Set secret_key, or the EXAMPLE_SECRET_KEY environment variable.",
+			)
+		}
+	}
+
+	return o, diags
+}
+
+// transport wraps next with the authentication of requests to endpoint.
+// The result is meant to be wrapped by the retry transport.
+func (o authOptions) transport(next http.RoundTripper, endpoint string, maxAttempts int) http.RoundTripper {
+	switch o.Mode {
+	case authModeOAuth2:
+		tokenURL := o.TokenURL
+		if tokenURL == "" {
+			tokenURL = strings.TrimSuffix(endpoint, "/") + defaultTokenPath
+		}
+
+		config := &clientcredentials.Config{
+			ClientID:     o.ClientId,
+			ClientSecret: o.ClientSecret,
+			TokenURL:     tokenURL,
+			Scopes:       o.Scopes,
+		}
+		tokenClient := &http.Client{
+			Transport: newRetryTransport(next, maxAttempts),
+		}
+
+		return newOAuthTransport(next, config, tokenClient)
+	case authModeHMAC:
+		return newHMACTransport(next, o.AccessKey, o.SecretKey)
+	}
+
+	return next
+}
@@ -0,0 +1,265 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"golang.org/x/sync/semaphore"
+	"golang.org/x/sync/singleflight"
+)
+
+// defaultEndpoint is the address terraform-service listens on by default.
+const defaultEndpoint = "http://127.0.0.1:29999"
+
+// Client is the shared backend client handed to resources and data sources
+// through ProviderData.
+type Client struct {
+	HTTPClient *http.Client
+
+	// Endpoint is the base URL of the backend. It may contain {region} and
+	// {project} placeholders, expanded for every request.
+	Endpoint string
+
+	// Region and Project are the provider region and project. Requests are
+	// sent to Project unless their context carries another one, see
+	// withProject.
+	Region  string
+	Project string
+
+	// Wait controls how WaitForOperation polls asynchronous operations.
+	Wait WaitOptions
+
+	// DefaultTags are the provider default_tags, merged into the tags of
+	// every resource.
+	DefaultTags types.Map
+
+	// sem limits the number of requests in flight, nil means no limit.
+	sem *semaphore.Weighted
+
+	// inflight coalesces identical concurrent GETs. Terraform starts a new
+	// provider process for every operation, so requests are never shared
+	// across plans or applies.
+	inflight singleflight.Group
+}
+
+func NewClient(endpoint string, httpClient *http.Client) *Client {
+	return &Client{
+		HTTPClient:  httpClient,
+		Endpoint:    strings.TrimSuffix(endpoint, "/"),
+		Wait:        defaultWaitOptions,
+		DefaultTags: types.MapNull(types.StringType),
+	}
+}
+
+// SetMaxConcurrentRequests limits the number of backend requests in flight
+// at any time across all resources and data sources. n <= 0 removes the
+// limit.
+func (c *Client) SetMaxConcurrentRequests(n int64) {
+	if n <= 0 {
+		c.sem = nil
+		return
+	}
+
+	c.sem = semaphore.NewWeighted(n)
+}
+
+// FieldError is a single entry of the backend error body. Field uses the
+// attribute syntax of the provider schema, e.g. "set_nested[0].fixed_ip", and
+// is empty when the error is not tied to an attribute.
+type FieldError struct {
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// APIError is returned by Client.Do when the backend answers with a non-2xx
+// status code.
+type APIError struct {
+	StatusCode int
+	Errors     []FieldError
+}
+
+func (e *APIError) Error() string {
+	if len(e.Errors) == 0 {
+		return fmt.Sprintf("unexpected status code %d", e.StatusCode)
+	}
+
+	return fmt.Sprintf("status code %d: %s", e.StatusCode, e.messages())
+}
+
+func (e *APIError) messages() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, fe := range e.Errors {
+		if fe.Field == "" {
+			msgs = append(msgs, fe.Message)
+		} else {
+			msgs = append(msgs, fe.Field+": "+fe.Message)
+		}
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// isNotFound reports whether err is a 404 returned by the backend.
+func isNotFound(err error) bool {
+	var apiErr *APIError
+
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// Do sends in as the JSON body of a request to the backend and decodes the
+// JSON response into out. Either of in and out may be nil.
+func (c *Client) Do(ctx context.Context, method, path string, in, out interface{}) error {
+	_, err := c.do(ctx, method, path, in, out)
+
+	return err
+}
+
+// do implements Do and additionally returns the response, whose body has
+// already been consumed, so that callers can inspect the status and headers.
+func (c *Client) do(ctx context.Context, method, path string, in, out interface{}) (*http.Response, error) {
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return nil, fmt.Errorf("encoding request body: %w", err)
+		}
+		body = bytes.NewReader(b)
+	}
+
+	project := c.projectFromContext(ctx)
+
+	req, err := http.NewRequestWithContext(ctx, method, c.url(project, path), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if project != "" {
+		req.Header.Set(projectHeader, project)
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	// Creates are not idempotent, the key lets the transport retry them
+	// without the backend creating duplicates.
+	if method == http.MethodPost {
+		req.Header.Set(idempotencyKeyHeader, newIdempotencyKey())
+	}
+
+	var httpResp *http.Response
+	var respBody []byte
+
+	if method == http.MethodGet && in == nil {
+		httpResp, respBody, err = c.sendCoalesced(req)
+	} else {
+		httpResp, respBody, err = c.send(req)
+	}
+	if err != nil {
+		return httpResp, err
+	}
+
+	if httpResp.StatusCode < 200 || httpResp.StatusCode > 299 {
+		apiErr := &APIError{StatusCode: httpResp.StatusCode}
+
+		var errBody struct {
+			Errors []FieldError `json:"errors"`
+		}
+		if json.Unmarshal(respBody, &errBody) == nil {
+			apiErr.Errors = errBody.Errors
+		} else if len(respBody) > 0 {
+			apiErr.Errors = []FieldError{{Message: string(respBody)}}
+		}
+
+		return httpResp, apiErr
+	}
+
+	if out == nil || len(respBody) == 0 {
+		return httpResp, nil
+	}
+
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return httpResp, fmt.Errorf("decoding response body: %w", err)
+	}
+
+	return httpResp, nil
+}
+
+// send performs req within the concurrency limit and reads the whole
+// response body.
+func (c *Client) send(req *http.Request) (*http.Response, []byte, error) {
+	if c.sem != nil {
+		if err := c.sem.Acquire(req.Context(), 1); err != nil {
+			return nil, nil, err
+		}
+		defer c.sem.Release(1)
+	}
+
+	httpResp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer httpResp.Body.Close()
+
+	respBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return httpResp, nil, fmt.Errorf("reading response body: %w", err)
+	}
+
+	return httpResp, respBody, nil
+}
+
+type coalescedResponse struct {
+	resp *http.Response
+	body []byte
+}
+
+// sendCoalesced is send for GET requests. Identical GETs that are in flight
+// at the same time, such as many data sources reading the same object, share
+// a single round trip. The shared request runs with the context of the caller
+// that started it; other callers stop waiting when their own context is done.
+func (c *Client) sendCoalesced(req *http.Request) (*http.Response, []byte, error) {
+	ch := c.inflight.DoChan(req.Header.Get(projectHeader)+" "+req.URL.String(), func() (interface{}, error) {
+		resp, body, err := c.send(req)
+
+		return coalescedResponse{resp: resp, body: body}, err
+	})
+
+	select {
+	case <-req.Context().Done():
+		return nil, nil, req.Context().Err()
+	case res := <-ch:
+		r, _ := res.Val.(coalescedResponse)
+
+		return r.resp, r.body, res.Err
+	}
+}
+
+// url resolves path against the endpoint of project. Absolute URLs, as may
+// be returned in Location headers, are used as they are.
+func (c *Client) url(project, path string) string {
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return path
+	}
+
+	return c.endpointFor(project) + path
+}
+
+func newIdempotencyKey() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
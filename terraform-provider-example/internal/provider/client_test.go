@@ -0,0 +1,158 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClientDo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/regex":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":"vm-1","name":"test01"}`))
+		case "/invalid":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = w.Write([]byte(`{"errors":[{"field":"set_nested[0].fixed_ip","message":"bad ip"}]}`))
+		case "/plain":
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte("boom"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL+"/", server.Client())
+	ctx := context.Background()
+
+	var created regexAPIModel
+	if err := client.Do(ctx, http.MethodPost, "/regex", regexAPIModel{Name: "test01"}, &created); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if created.Id != "vm-1" {
+		t.Errorf("expected id vm-1, got %q", created.Id)
+	}
+
+	var apiErr *APIError
+
+	err := client.Do(ctx, http.MethodPost, "/invalid", nil, nil)
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %T: %v", err, err)
+	}
+	if apiErr.StatusCode != http.StatusUnprocessableEntity || len(apiErr.Errors) != 1 || apiErr.Errors[0].Field != "set_nested[0].fixed_ip" {
+		t.Errorf("unexpected error: %#v", apiErr)
+	}
+
+	err = client.Do(ctx, http.MethodGet, "/plain", nil, nil)
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %T: %v", err, err)
+	}
+	if len(apiErr.Errors) != 1 || apiErr.Errors[0].Message != "boom" {
+		t.Errorf("unexpected error: %#v", apiErr)
+	}
+
+	if err := client.Do(ctx, http.MethodGet, "/missing", nil, nil); !isNotFound(err) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
+
+func TestClientMaxConcurrentRequests(t *testing.T) {
+	var inflight, maxInflight int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inflight, 1)
+		defer atomic.AddInt32(&inflight, -1)
+
+		for {
+			m := atomic.LoadInt32(&maxInflight)
+			if n <= m || atomic.CompareAndSwapInt32(&maxInflight, m, n) {
+				break
+			}
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, server.Client())
+	client.SetMaxConcurrentRequests(2)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := client.Do(context.Background(), http.MethodPut, "/regex/vm-1", regexAPIModel{Name: "test01"}, nil); err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&maxInflight); got > 2 {
+		t.Errorf("expected at most 2 requests in flight, got %d", got)
+	}
+}
+
+func TestClientCoalescesGets(t *testing.T) {
+	var requests int32
+	release := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		<-release
+		_, _ = w.Write([]byte(`{"id":"` + r.URL.Query().Get("id") + `"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, server.Client())
+
+	const readers = 5
+
+	var wg sync.WaitGroup
+	ids := make([]string, readers)
+	for i := 0; i < readers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			var out struct {
+				Id string `json:"id"`
+			}
+			if err := client.Do(context.Background(), http.MethodGet, "/computed/detail?id=abc", nil, &out); err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+			ids[i] = out.Id
+		}(i)
+	}
+
+	// Let the first request reach the server and the others join it.
+	for atomic.LoadInt32(&requests) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Errorf("expected 1 request to the backend, got %d", got)
+	}
+
+	for i, id := range ids {
+		if id != "abc" {
+			t.Errorf("reader %d: expected id abc, got %q", i, id)
+		}
+	}
+}
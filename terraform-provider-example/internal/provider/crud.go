@@ -0,0 +1,411 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// crudOperations are what a resource with the model M implements on top of
+// crudResource: its schema and its backend calls.
+//
+// The id passed to the backend calls is the ID of the object in the
+// backend, without the region and project of the resource ID, and create
+// returns it. create also returns the ID together with an error when the
+// object may exist in the backend nevertheless, e.g. when waiting for an
+// asynchronous create timed out. Errors of the backend calls are reported as client errors,
+// with the field errors of terraform-service mapped to attributes; wrap
+// diagnostics with diagsError to report them as they are.
+type crudOperations[M any] interface {
+	schema(ctx context.Context) schema.Schema
+	create(ctx context.Context, client *Client, data *M) (string, error)
+	read(ctx context.Context, client *Client, id string, data *M) error
+	update(ctx context.Context, client *Client, id string, data *M) error
+	delete(ctx context.Context, client *Client, id string, data *M) error
+}
+
+// crudPlanModifier is implemented by crudOperations whose plan needs more
+// than crudResource plans, e.g. computed attributes of nested blocks.
+// modifyPlan is called after tags_all and project are planned.
+type crudPlanModifier interface {
+	modifyPlan(ctx context.Context, client *Client, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse)
+}
+
+// Ensure crudResource fully satisfies framework interfaces.
+var _ resource.ResourceWithConfigure = &crudResource[struct{}]{}
+var _ resource.ResourceWithImportState = &crudResource[struct{}]{}
+var _ resource.ResourceWithModifyPlan = &crudResource[struct{}]{}
+
+// crudResource implements a resource from its crudOperations. The model M
+// must have an id attribute. Depending on the schema, crudResource also:
+//
+//   - sends requests to the project of the resource and qualifies IDs with
+//     the region and the project, if the schema has a project attribute;
+//   - plans tags_all, if the schema has tags and tags_all attributes;
+//   - calls modifyPlan, if ops implements crudPlanModifier;
+//   - sets the resource identity, if ops implements crudLister;
+//   - bounds the operations with the timeouts block, if the schema has one.
+type crudResource[M any] struct {
+	name   string
+	ops    crudOperations[M]
+	client *Client
+}
+
+// newCRUDResource returns the resource <provider>_<name> implemented by ops.
+// Resources whose ops implement crudLister have an identity, see
+// crudIdentityResource.
+func newCRUDResource[M any](name string, ops crudOperations[M]) resource.Resource {
+	r := &crudResource[M]{
+		name: name,
+		ops:  ops,
+	}
+
+	if _, ok := ops.(crudLister[M]); ok {
+		return &crudIdentityResource[M]{r}
+	}
+
+	return r
+}
+
+// diagsError carries diagnostics, e.g. of model conversions, out of the
+// backend calls of crudOperations.
+type diagsError diag.Diagnostics
+
+func (e diagsError) Error() string {
+	var msgs []string
+	for _, d := range e {
+		msgs = append(msgs, d.Summary()+": "+d.Detail())
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// errorFromDiags returns diags as an error if they contain one. Warnings
+// alone are dropped.
+func errorFromDiags(diags diag.Diagnostics) error {
+	if !diags.HasError() {
+		return nil
+	}
+
+	return diagsError(diags)
+}
+
+func (r *crudResource[M]) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_" + r.name
+}
+
+func (r *crudResource[M]) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = r.ops.schema(ctx)
+
+	if c, ok := r.ops.(schemaCustomizer); ok {
+		resp.Diagnostics.Append(c.customizeSchema(ctx, &resp.Schema)...)
+	}
+}
+
+func (r *crudResource[M]) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *crudResource[M]) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	attributes := req.Plan.Schema.GetAttributes()
+
+	if hasKey(attributes, "tags_all") {
+		r.client.modifyPlanTags(ctx, req, resp)
+	}
+
+	if hasKey(attributes, "project") {
+		r.client.modifyPlanProject(ctx, req, resp)
+	}
+
+	if m, ok := r.ops.(crudPlanModifier); ok && !resp.Diagnostics.HasError() {
+		m.modifyPlan(ctx, r.client, req, resp)
+	}
+}
+
+func (r *crudResource[M]) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data M
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = tflog.SetField(ctx, "resource", r.name)
+
+	// Requests about the resource are sent to its project
+	var project types.String
+	scoped := hasKey(req.Plan.Schema.GetAttributes(), "project")
+	if scoped {
+		resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("project"), &project)...)
+		ctx = withProject(ctx, project.ValueString())
+	}
+
+	ctx, cancel, diags := withOperationTimeout(ctx, req.Plan, hasKey(req.Plan.Schema.GetBlocks(), "timeouts"), timeouts.Value.Create)
+
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer cancel()
+
+	backendID, err := r.ops.create(ctx, r.client, &data)
+	if err != nil {
+		r.addError(ctx, &resp.Diagnostics, req.Plan, "create", err)
+
+		// Objects the backend may still create are saved together with the
+		// error, Terraform keeps them as tainted and replaces them.
+		if backendID == "" {
+			return
+		}
+	}
+
+	id := types.StringValue(backendID)
+	if scoped {
+		id = types.StringValue(r.client.QualifyID(project.ValueString(), backendID))
+	}
+
+	// Write logs using the tflog package
+	// Documentation: https://terraform.io/plugin/log
+	tflog.Trace(ctx, "created a resource", map[string]interface{}{"id": id.ValueString()})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+	resp.Diagnostics.Append(r.setIdentity(ctx, resp.Identity, resp.State, scoped, backendID)...)
+}
+
+func (r *crudResource[M]) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data M
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, id, diags := r.scope(ctx, req.State, hasKey(req.State.Schema.GetAttributes(), "project"))
+
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel, diags := withOperationTimeout(ctx, req.State, hasKey(req.State.Schema.GetBlocks(), "timeouts"), timeouts.Value.Read)
+
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer cancel()
+
+	scoped := hasKey(req.State.Schema.GetAttributes(), "project")
+
+	err := r.ops.read(ctx, r.client, id, &data)
+	if isNotFound(err) {
+		tflog.Warn(ctx, r.name+" not found, removing from state", map[string]interface{}{"id": id})
+		resp.State.RemoveResource(ctx)
+
+		// The framework requires an identity even for removed resources
+		resp.Diagnostics.Append(r.setIdentity(ctx, resp.Identity, req.State, scoped, id)...)
+		return
+	}
+	if err != nil {
+		r.addError(ctx, &resp.Diagnostics, req.State, "read", err)
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(r.setIdentity(ctx, resp.Identity, resp.State, scoped, id)...)
+}
+
+func (r *crudResource[M]) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data M
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, id, diags := r.scope(ctx, req.Plan, hasKey(req.Plan.Schema.GetAttributes(), "project"))
+
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel, diags := withOperationTimeout(ctx, req.Plan, hasKey(req.Plan.Schema.GetBlocks(), "timeouts"), timeouts.Value.Update)
+
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer cancel()
+
+	if err := r.ops.update(ctx, r.client, id, &data); err != nil {
+		r.addError(ctx, &resp.Diagnostics, req.Plan, "update", err)
+		return
+	}
+
+	tflog.Trace(ctx, "updated a resource", map[string]interface{}{"id": id})
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(r.setIdentity(ctx, resp.Identity, resp.State, hasKey(req.Plan.Schema.GetAttributes(), "project"), id)...)
+}
+
+func (r *crudResource[M]) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data M
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, id, diags := r.scope(ctx, req.State, hasKey(req.State.Schema.GetAttributes(), "project"))
+
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel, diags := withOperationTimeout(ctx, req.State, hasKey(req.State.Schema.GetBlocks(), "timeouts"), timeouts.Value.Delete)
+
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer cancel()
+
+	// Objects deleted outside of Terraform are gone already
+	if err := r.ops.delete(ctx, r.client, id, &data); err != nil && !isNotFound(err) {
+		r.addError(ctx, &resp.Diagnostics, req.State, "delete", err)
+		return
+	}
+
+	tflog.Trace(ctx, "deleted a resource", map[string]interface{}{"id": id})
+}
+
+func (r *crudResource[M]) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	scoped := hasKey(resp.State.Schema.GetAttributes(), "project")
+
+	// Import blocks with an identity instead of an id, e.g. generated by
+	// terraform query. Read sets the identity afterwards.
+	if req.ID == "" && req.Identity != nil {
+		var diags diag.Diagnostics
+		req.ID, diags = r.importIDFromIdentity(ctx, req.Identity, scoped)
+
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if scoped {
+		r.client.importStateProject(ctx, req, resp)
+		return
+	}
+
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// scope returns the backend ID of the resource, and the context of requests
+// about it. IDs of resources scoped to a project are qualified.
+func (r *crudResource[M]) scope(ctx context.Context, data attributeGetter, scoped bool) (context.Context, string, diag.Diagnostics) {
+	var id types.String
+
+	diags := data.GetAttribute(ctx, path.Root("id"), &id)
+	if diags.HasError() {
+		return ctx, "", diags
+	}
+
+	ctx = tflog.SetField(ctx, "resource", r.name)
+
+	if !scoped {
+		return ctx, id.ValueString(), diags
+	}
+
+	ctx, backendID, err := r.client.scope(ctx, id)
+	if err != nil {
+		diags.AddAttributeError(path.Root("id"), "Invalid Resource ID", err.Error())
+	}
+
+	return ctx, backendID, diags
+}
+
+// addError reports an error of a backend call.
+func (r *crudResource[M]) addError(ctx context.Context, diags *diag.Diagnostics, data attributeGetter, action string, err error) {
+	var dErr diagsError
+	if errors.As(err, &dErr) {
+		diags.Append(diag.Diagnostics(dErr)...)
+		return
+	}
+
+	addClientError(ctx, diags, data, action+" "+r.name, err)
+}
+
+// hasKey reports whether a schema has the attribute or block name.
+func hasKey[T any](m map[string]T, name string) bool {
+	_, ok := m[name]
+	return ok
+}
+
+// withOperationTimeout bounds ctx with the timeout of an operation, read
+// with timeout from the timeouts block of data, or defaultTimeout.
+// Resources without a timeouts block are not bounded.
+func withOperationTimeout(
+	ctx context.Context,
+	data attributeGetter,
+	hasTimeouts bool,
+	timeout func(timeouts.Value, context.Context, time.Duration) (time.Duration, diag.Diagnostics),
+) (context.Context, context.CancelFunc, diag.Diagnostics) {
+	if !hasTimeouts {
+		return ctx, func() {}, nil
+	}
+
+	var value timeouts.Value
+
+	diags := data.GetAttribute(ctx, path.Root("timeouts"), &value)
+	if diags.HasError() {
+		return ctx, func() {}, diags
+	}
+
+	d, diags2 := timeout(value, ctx, defaultTimeout)
+	diags.Append(diags2...)
+
+	ctx, cancel := context.WithTimeout(ctx, d)
+
+	return ctx, cancel, diags
+}
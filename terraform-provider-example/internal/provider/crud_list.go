@@ -0,0 +1,270 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	listschema "github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// crudLister is implemented by crudOperations whose objects can be listed,
+// for terraform query. list sends query to the list endpoint of the
+// resource and pushes the matching objects, by backend ID and display
+// name, until push returns false. fill copies the object into a model whose
+// attributes are null, like read does after an import.
+type crudLister[M any] interface {
+	list(ctx context.Context, client *Client, query url.Values, push func(id, displayName string, fill func(data *M) error) bool) error
+}
+
+// Ensure crudIdentityResource and crudListResource fully satisfy framework
+// interfaces.
+var _ resource.ResourceWithIdentity = &crudIdentityResource[struct{}]{}
+var _ list.ListResourceWithConfigure = &crudListResource[struct{}]{}
+
+// crudIdentityResource is a crudResource with a resource identity, the
+// project and the backend ID of the resource. List results are identified
+// by it, newCRUDResource returns it for resources implementing crudLister.
+type crudIdentityResource[M any] struct {
+	*crudResource[M]
+}
+
+func (r *crudIdentityResource[M]) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"id": identityschema.StringAttribute{
+				Description:       "ID of the object in the backend.",
+				RequiredForImport: true,
+			},
+		},
+	}
+
+	if hasKey(r.ops.schema(ctx).Attributes, "project") {
+		resp.IdentitySchema.Attributes["project"] = identityschema.StringAttribute{
+			Description:       "Project of the object. Defaults to the provider project.",
+			OptionalForImport: true,
+		}
+	}
+}
+
+// setIdentity sets the identity of resources with one, see
+// crudIdentityResource, from the backend ID and the project of state.
+func (r *crudResource[M]) setIdentity(ctx context.Context, identity *tfsdk.ResourceIdentity, state attributeGetter, scoped bool, backendID string) diag.Diagnostics {
+	if identity == nil {
+		return nil
+	}
+
+	diags := identity.SetAttribute(ctx, path.Root("id"), backendID)
+
+	if scoped {
+		var project types.String
+		diags.Append(state.GetAttribute(ctx, path.Root("project"), &project)...)
+		diags.Append(identity.SetAttribute(ctx, path.Root("project"), project)...)
+	}
+
+	return diags
+}
+
+// importIDFromIdentity returns the import ID of the resource identity of an
+// import block without id.
+func (r *crudResource[M]) importIDFromIdentity(ctx context.Context, identity *tfsdk.ResourceIdentity, scoped bool) (string, diag.Diagnostics) {
+	var id, project types.String
+
+	diags := identity.GetAttribute(ctx, path.Root("id"), &id)
+	if scoped {
+		diags.Append(identity.GetAttribute(ctx, path.Root("project"), &project)...)
+	}
+
+	if project.ValueString() == "" {
+		return id.ValueString(), diags
+	}
+
+	return project.ValueString() + "/" + id.ValueString(), diags
+}
+
+// crudListResource lists the objects of the resource <provider>_<name>,
+// implemented by ops, for terraform query. The list block filters them by
+// project and tags.
+type crudListResource[M any] struct {
+	name   string
+	ops    crudLister[M]
+	client *Client
+}
+
+// crudListModel describes the list block data model.
+type crudListModel struct {
+	Project types.String `tfsdk:"project"`
+	Tags    types.Map    `tfsdk:"tags"`
+}
+
+// newCRUDListResource returns the list resource of <provider>_<name>, see
+// crudListResource.
+func newCRUDListResource[M any](name string, ops crudLister[M]) *crudListResource[M] {
+	return &crudListResource[M]{
+		name: name,
+		ops:  ops,
+	}
+}
+
+func (r *crudListResource[M]) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_" + r.name
+}
+
+func (r *crudListResource[M]) ListResourceConfigSchema(ctx context.Context, req list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = listschema.Schema{
+		MarkdownDescription: "Lists the objects of the backend, for example to generate their import blocks and configuration with `terraform query -generate-config-out`.",
+
+		Attributes: map[string]listschema.Attribute{
+			"project": listschema.StringAttribute{
+				MarkdownDescription: "Project to list the objects of. Defaults to the provider `project`.",
+				Optional:            true,
+			},
+			"tags": listschema.MapAttribute{
+				MarkdownDescription: "Only lists objects with all these tags. An empty value only requires the tag to be set.",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+		},
+	}
+}
+
+func (r *crudListResource[M]) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected List Resource Configure Type",
+			fmt.Sprintf("Expected *Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *crudListResource[M]) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	// List blocks without config block have a null config
+	config := crudListModel{
+		Project: types.StringNull(),
+		Tags:    types.MapNull(types.StringType),
+	}
+
+	if !req.Config.Raw.IsNull() {
+		diags := req.Config.Get(ctx, &config)
+		if diags.HasError() {
+			stream.Results = list.ListResultsStreamDiagnostics(diags)
+			return
+		}
+	}
+
+	project := r.client.Project
+	if !config.Project.IsNull() {
+		project = config.Project.ValueString()
+	}
+
+	ctx = tflog.SetField(ctx, "resource", r.name)
+	ctx = withProject(ctx, project)
+
+	query := url.Values{}
+	for _, tag := range tagFilters(config.Tags) {
+		query.Add("tag", tag)
+	}
+
+	stream.Results = func(push func(list.ListResult) bool) {
+		ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+		defer cancel()
+
+		var count int64
+
+		err := r.ops.list(ctx, r.client, query, func(id, displayName string, fill func(data *M) error) bool {
+			result := req.NewListResult(ctx)
+			result.DisplayName = displayName
+
+			result.Diagnostics.Append(result.Identity.SetAttribute(ctx, path.Root("id"), id)...)
+			if _, ok := req.ResourceIdentitySchema.GetAttributes()["project"]; ok {
+				result.Diagnostics.Append(result.Identity.SetAttribute(ctx, path.Root("project"), optionalString(project))...)
+			}
+
+			if req.IncludeResource {
+				result.Diagnostics.Append(r.resource(ctx, result.Resource, project, id, fill)...)
+			}
+
+			count++
+
+			return push(result) && (req.Limit == 0 || count < req.Limit)
+		})
+		if err != nil {
+			var diags diag.Diagnostics
+			addClientError(ctx, &diags, req.Config, "list "+r.name, err)
+			push(list.ListResult{Diagnostics: diags})
+		}
+	}
+}
+
+// resource sets the state of the resource of a list result, like
+// ImportState followed by Read does.
+func (r *crudListResource[M]) resource(ctx context.Context, state *tfsdk.Resource, project, id string, fill func(data *M) error) diag.Diagnostics {
+	// Setting the id turns the null state into an object with null attributes
+	diags := state.SetAttribute(ctx, path.Root("id"), r.client.QualifyID(project, id))
+
+	_, scoped := state.Schema.GetAttributes()["project"]
+	if scoped {
+		diags.Append(state.SetAttribute(ctx, path.Root("project"), optionalString(project))...)
+	}
+
+	var data M
+	diags.Append(state.Get(ctx, &data)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	// fill only fails converting the object
+	if err := fill(&data); err != nil {
+		var dErr diagsError
+		if errors.As(err, &dErr) {
+			return append(diags, diag.Diagnostics(dErr)...)
+		}
+
+		diags.AddError("Client Error", fmt.Sprintf("Unable to read %s %s, got error: %s", r.name, id, err))
+		return diags
+	}
+
+	diags.Append(state.Set(ctx, &data)...)
+
+	return diags
+}
+
+// tagFilters returns the tag query parameters of the list endpoints for
+// tags: "key=value", or "key" for an empty value, sorted.
+func tagFilters(tags types.Map) []string {
+	filters := make([]string, 0, len(tags.Elements()))
+	for key, value := range expandTags(tags) {
+		if value == "" {
+			filters = append(filters, key)
+		} else {
+			filters = append(filters, key+"="+value)
+		}
+	}
+	sort.Strings(filters)
+
+	return filters
+}
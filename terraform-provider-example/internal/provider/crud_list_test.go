@@ -0,0 +1,95 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestProtocolListResourceRegex(t *testing.T) {
+	h := newProtocolHarness(t, map[string]interface{}{
+		"endpoint": newLocalTestServer(t),
+		"project":  "p1",
+	})
+
+	web1 := h.create("example_regex", map[string]interface{}{"name": "web-1", "tags": map[string]interface{}{"env": "prod"}})
+	web2 := h.create("example_regex", map[string]interface{}{"name": "web-2", "tags": map[string]interface{}{"env": "prod"}})
+	h.create("example_regex", map[string]interface{}{"name": "db", "tags": map[string]interface{}{"env": "dev"}})
+	h.create("example_regex", map[string]interface{}{"name": "other", "project": "p2", "tags": map[string]interface{}{"env": "prod"}})
+
+	// Only objects of the provider project with the tag are listed, with
+	// the state an import followed by a refresh would have.
+	results, diags := h.list("example_regex", map[string]interface{}{"tags": map[string]interface{}{"env": "prod"}}, true)
+	requireNoErrors(t, "ListResource", diags)
+
+	sort.Slice(results, func(i, j int) bool { return results[i].displayName < results[j].displayName })
+
+	if len(results) != 2 || results[0].displayName != "web-1" || results[1].displayName != "web-2" {
+		t.Fatalf("expected web-1 and web-2, got %v", results)
+	}
+
+	for i, state := range []map[string]interface{}{web1, web2} {
+		if !reflect.DeepEqual(results[i].resource, state) {
+			t.Errorf("expected resource %v, got %v", state, results[i].resource)
+		}
+	}
+
+	// The identity imports the object like its ID does.
+	identity := results[0].identity
+	if id, _ := identity["id"].(string); identity["project"] != "p1" || "/p1/"+id != web1["id"] {
+		t.Errorf("unexpected identity %v of %s", identity, web1["id"])
+	}
+
+	imported := h.importIdentity("example_regex", identity)
+	requireNoErrors(t, "ImportResourceState", imported.Diagnostics)
+
+	if got := h.decode("example_regex", imported.ImportedResources[0].State); got["id"] != web1["id"] || got["project"] != "p1" {
+		t.Errorf("expected import of %s, got %v", web1["id"], got)
+	}
+
+	// Tags without a value only need to be set, and the project of the list
+	// block overrides the provider project.
+	results, diags = h.list("example_regex", map[string]interface{}{"tags": map[string]interface{}{"env": ""}}, false)
+	requireNoErrors(t, "ListResource", diags)
+
+	if len(results) != 3 || results[0].resource != nil {
+		t.Errorf("expected the 3 VMs of p1 without resources, got %v", results)
+	}
+
+	results, diags = h.list("example_regex", map[string]interface{}{"project": "p2"}, false)
+	requireNoErrors(t, "ListResource", diags)
+
+	if len(results) != 1 || results[0].displayName != "other" || results[0].identity["project"] != "p2" {
+		t.Errorf("expected the VM of p2, got %v", results)
+	}
+}
+
+func TestProtocolListResourceSetNested(t *testing.T) {
+	h := newProtocolHarness(t, map[string]interface{}{"endpoint": newLocalTestServer(t)})
+
+	state := h.create("example_set_nested", map[string]interface{}{
+		"set_nested": []interface{}{
+			map[string]interface{}{"uuid": "net-1", "fixed_ip": "10.0.0.10", "enable_gateway": true},
+		},
+	})
+
+	results, diags := h.list("example_set_nested", nil, true)
+	requireNoErrors(t, "ListResource", diags)
+
+	// Objects without a name are displayed by their ID, and the computed
+	// attributes of nested objects are set like Read sets them.
+	if len(results) != 1 || results[0].displayName != state["id"] {
+		t.Fatalf("expected %s, got %v", state["id"], results)
+	}
+
+	if !reflect.DeepEqual(results[0].resource, state) {
+		t.Errorf("expected resource %v, got %v", state, results[0].resource)
+	}
+
+	if results[0].identity["id"] != state["id"] || results[0].identity["project"] != nil {
+		t.Errorf("unexpected identity %v", results[0].identity)
+	}
+}
@@ -0,0 +1,238 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// memos is a resource implemented on crudResource that keeps its objects in
+// memory, keyed by project and ID, instead of in terraform-service.
+type memos struct {
+	mu      sync.Mutex
+	objects map[string]string
+	created int
+
+	// err is returned by all backend calls when set.
+	err error
+}
+
+type memoModel struct {
+	Id      types.String `tfsdk:"id"`
+	Project types.String `tfsdk:"project"`
+	Text    types.String `tfsdk:"text"`
+}
+
+func (m *memos) schema(ctx context.Context) schema.Schema {
+	return schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"project": projectAttribute(),
+			"text": schema.StringAttribute{
+				Required: true,
+			},
+		},
+	}
+}
+
+func (m *memos) create(ctx context.Context, client *Client, data *memoModel) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.err != nil {
+		return "", m.err
+	}
+
+	m.created++
+	id := fmt.Sprintf("memo-%d", m.created)
+	m.objects[client.projectFromContext(ctx)+"/"+id] = data.Text.ValueString()
+
+	return id, nil
+}
+
+func (m *memos) read(ctx context.Context, client *Client, id string, data *memoModel) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.err != nil {
+		return m.err
+	}
+
+	text, ok := m.objects[client.projectFromContext(ctx)+"/"+id]
+	if !ok {
+		return &APIError{StatusCode: http.StatusNotFound}
+	}
+
+	data.Text = types.StringValue(text)
+
+	return nil
+}
+
+func (m *memos) update(ctx context.Context, client *Client, id string, data *memoModel) error {
+	if err := m.read(ctx, client, id, &memoModel{}); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.objects[client.projectFromContext(ctx)+"/"+id] = data.Text.ValueString()
+
+	return nil
+}
+
+func (m *memos) delete(ctx context.Context, client *Client, id string, data *memoModel) error {
+	if err := m.read(ctx, client, id, &memoModel{}); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.objects, client.projectFromContext(ctx)+"/"+id)
+
+	return nil
+}
+
+// memoProvider is the provider of the package with the example_memo resource
+// implemented by memos.
+type memoProvider struct {
+	*ScaffoldingProvider
+	memos *memos
+}
+
+func (p memoProvider) Resources(_ context.Context) []func() resource.Resource {
+	return []func() resource.Resource{
+		func() resource.Resource { return newCRUDResource[memoModel]("memo", p.memos) },
+	}
+}
+
+// ListResources returns none, the list resources of the package need their
+// managed resources.
+func (p memoProvider) ListResources(_ context.Context) []func() list.ListResource {
+	return nil
+}
+
+func newMemoHarness(t *testing.T) (*protocolHarness, *memos) {
+	t.Helper()
+
+	m := &memos{objects: map[string]string{}}
+	p := memoProvider{ScaffoldingProvider: &ScaffoldingProvider{version: "test"}, memos: m}
+
+	h := newProviderProtocolHarness(t, p, map[string]interface{}{
+		"region":  "eu",
+		"project": "p1",
+	}, nil)
+
+	return h, m
+}
+
+func TestCRUDResource(t *testing.T) {
+	h, m := newMemoHarness(t)
+
+	state := h.create("example_memo", map[string]interface{}{"text": "hello"})
+
+	if state["id"] != "eu/p1/memo-1" || state["project"] != "p1" {
+		t.Fatalf("expected qualified id of project p1, got %v", state)
+	}
+	if m.objects["p1/memo-1"] != "hello" {
+		t.Fatalf("expected memo to be created in p1, got %v", m.objects)
+	}
+
+	state = h.change("example_memo", state, map[string]interface{}{"text": "bye"})
+	if m.objects["p1/memo-1"] != "bye" {
+		t.Errorf("expected memo to be updated, got %v", m.objects)
+	}
+
+	// Objects deleted outside of Terraform are removed from state, and
+	// destroying them succeeds.
+	delete(m.objects, "p1/memo-1")
+
+	read := h.read("example_memo", state)
+	requireNoErrors(t, "ReadResource", read.Diagnostics)
+
+	if got := h.decode("example_memo", read.NewState); got != nil {
+		t.Errorf("expected resource to be removed from state, got %v", got)
+	}
+
+	h.destroy("example_memo", state)
+}
+
+func TestCRUDResource_Errors(t *testing.T) {
+	h, m := newMemoHarness(t)
+
+	config := map[string]interface{}{"text": "hello"}
+
+	// Field errors of the backend are attached to their attribute.
+	m.err = &APIError{StatusCode: http.StatusBadRequest, Errors: []FieldError{{Field: "text", Message: "too long"}}}
+
+	plan := h.plan("example_memo", nil, config)
+	requireNoErrors(t, "PlanResourceChange", plan.Diagnostics)
+
+	apply := h.apply("example_memo", nil, config, plan)
+	requireError(t, "ApplyResourceChange", apply.Diagnostics, "Unable to create memo: too long")
+
+	if len(apply.Diagnostics) != 1 || apply.Diagnostics[0].Attribute == nil {
+		t.Errorf("expected a single attribute error, got %s", formatDiagnostics(apply.Diagnostics))
+	}
+
+	// Diagnostics are reported as they are.
+	var diags diag.Diagnostics
+	diags.AddAttributeError(path.Root("text"), "Invalid Memo", "memos must not be empty")
+	m.err = errorFromDiags(diags)
+
+	apply = h.apply("example_memo", nil, config, plan)
+	requireError(t, "ApplyResourceChange", apply.Diagnostics, "memos must not be empty")
+
+	if apply.Diagnostics[0].Summary != "Invalid Memo" {
+		t.Errorf("expected diagnostics of the backend call, got %s", formatDiagnostics(apply.Diagnostics))
+	}
+
+	// Other errors are client errors.
+	m.err = fmt.Errorf("connection refused")
+
+	apply = h.apply("example_memo", nil, config, plan)
+	requireError(t, "ApplyResourceChange", apply.Diagnostics, "Unable to create memo, got error: connection refused")
+}
+
+func TestCRUDResource_ImportState(t *testing.T) {
+	h, m := newMemoHarness(t)
+
+	m.objects["p2/memo-7"] = "imported"
+
+	resp := h.importState("example_memo", "p2/memo-7")
+	requireNoErrors(t, "ImportResourceState", resp.Diagnostics)
+
+	if len(resp.ImportedResources) != 1 {
+		t.Fatalf("expected a single imported resource, got %d", len(resp.ImportedResources))
+	}
+
+	imported := h.decode("example_memo", resp.ImportedResources[0].State)
+
+	read := h.read("example_memo", imported)
+	requireNoErrors(t, "ReadResource", read.Diagnostics)
+
+	expected := map[string]interface{}{"id": "eu/p2/memo-7", "project": "p2", "text": "imported"}
+	if got := h.decode("example_memo", read.NewState); !knownValuesMatch(expected, got) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
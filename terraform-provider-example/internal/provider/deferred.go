@@ -0,0 +1,68 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// deferUnknownConfig handles a provider configuration that is not known
+// yet, such as an endpoint taken from a resource that is not created. If
+// Terraform supports deferred actions, all resources and data sources of the
+// provider are deferred until the configuration is known; the framework
+// answers for them without calling the provider. Otherwise every unknown
+// attribute is reported as an error, instead of configuring a client that
+// sends requests to an unknown endpoint. It reports whether Configure has to
+// stop.
+func deferUnknownConfig(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) bool {
+	if req.Config.Raw.IsFullyKnown() {
+		return false
+	}
+
+	if req.ClientCapabilities.DeferralAllowed {
+		tflog.Info(ctx, "provider configuration is unknown, deferring all resources and data sources")
+
+		resp.Deferred = &provider.Deferred{
+			Reason: provider.DeferredReasonProviderConfigUnknown,
+		}
+
+		return true
+	}
+
+	_ = tftypes.Walk(req.Config.Raw, func(p *tftypes.AttributePath, v tftypes.Value) (bool, error) {
+		if v.IsKnown() {
+			return true, nil
+		}
+
+		// Unknown elements of a collection are reported on the attribute.
+		name, ok := p.NextStep().(tftypes.AttributeName)
+		if !ok {
+			return false, nil
+		}
+
+		resp.Diagnostics.AddAttributeError(
+			path.Root(string(name)),
+			"Unknown Provider Configuration",
+			"The value of "+string(name)+" depends on resources that are not created yet, so the provider cannot be configured. "+
+				"Create them first, for example with -target, or use a version of Terraform that supports deferred actions.",
+		)
+
+		return false, nil
+	})
+
+	if !resp.Diagnostics.HasError() {
+		resp.Diagnostics.AddError(
+			"Unknown Provider Configuration",
+			"The provider configuration depends on resources that are not created yet, so the provider cannot be configured. "+
+				"Create them first, for example with -target, or use a version of Terraform that supports deferred actions.",
+		)
+	}
+
+	return true
+}
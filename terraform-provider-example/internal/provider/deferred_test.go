@@ -0,0 +1,112 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+// requireDeferred fails the test unless deferred defers the change because
+// the provider configuration is unknown.
+func requireDeferred(t *testing.T, operation string, deferred *tfprotov6.Deferred, diags []*tfprotov6.Diagnostic) {
+	t.Helper()
+
+	requireNoErrors(t, operation, diags)
+
+	if deferred == nil || deferred.Reason != tfprotov6.DeferredReasonProviderConfigUnknown {
+		t.Fatalf("%s: expected deferral because the provider configuration is unknown, got %v", operation, deferred)
+	}
+}
+
+func TestProtocolDeferred(t *testing.T) {
+	// The endpoint is the output of a resource that is not created yet.
+	h := newProtocolHarnessWithCapabilities(t,
+		map[string]interface{}{"endpoint": unknownValue, "project": "p1"},
+		&tfprotov6.ConfigureProviderClientCapabilities{DeferralAllowed: true},
+	)
+
+	config := map[string]interface{}{"name": "test01", "tags": map[string]interface{}{"env": unknownValue}}
+
+	plan := h.plan("example_regex", nil, config)
+	requireDeferred(t, "PlanResourceChange", plan.Deferred, plan.Diagnostics)
+
+	// The change is planned as configured, nothing is sent to the backend.
+	if got := h.decode("example_regex", plan.PlannedState); got["name"] != "test01" {
+		t.Errorf("expected proposed new state to be planned, got %v", got)
+	}
+
+	prior := map[string]interface{}{"id": "vm-1", "name": "test01"}
+
+	read := h.read("example_regex", prior)
+	requireDeferred(t, "ReadResource", read.Deferred, read.Diagnostics)
+
+	if got := h.decode("example_regex", read.NewState); got["id"] != "vm-1" {
+		t.Errorf("expected prior state to be kept, got %v", got)
+	}
+
+	plan = h.plan("example_regex", prior, map[string]interface{}{"name": "test02"})
+	requireDeferred(t, "PlanResourceChange", plan.Deferred, plan.Diagnostics)
+
+	imported := h.importState("example_regex", "vm-1")
+	requireDeferred(t, "ImportResourceState", imported.Deferred, imported.Diagnostics)
+
+	dataSource := h.schema.DataSourceSchemas["example_example"]
+	if dataSource == nil {
+		t.Fatal("provider does not implement the example_example data source")
+	}
+
+	readData, err := h.server.ReadDataSource(h.ctx, &tfprotov6.ReadDataSourceRequest{
+		TypeName: "example_example",
+		Config:   h.dynamicValue(dataSource.ValueType(), map[string]interface{}{"configurable_attribute": "a"}),
+	})
+	if err != nil {
+		t.Fatalf("ReadDataSource: %s", err)
+	}
+	requireDeferred(t, "ReadDataSource", readData.Deferred, readData.Diagnostics)
+}
+
+func TestProtocolDeferred_NotAllowed(t *testing.T) {
+	h := newProtocolHarness(t, nil)
+
+	testCases := map[string]struct {
+		config   map[string]interface{}
+		expected string
+	}{
+		"endpoint": {
+			config:   map[string]interface{}{"endpoint": unknownValue},
+			expected: "The value of endpoint depends on resources",
+		},
+		"default-tags-element": {
+			config:   map[string]interface{}{"default_tags": map[string]interface{}{"env": unknownValue}},
+			expected: "The value of default_tags depends on resources",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			requireError(t, "ConfigureProvider", h.configureError(tc.config), tc.expected)
+		})
+	}
+}
+
+func TestProtocolDeferred_Known(t *testing.T) {
+	// Providers with a known configuration are not deferred, even if
+	// Terraform allows it.
+	h := newProtocolHarnessWithCapabilities(t,
+		map[string]interface{}{"endpoint": newLocalTestServer(t)},
+		&tfprotov6.ConfigureProviderClientCapabilities{DeferralAllowed: true},
+	)
+
+	plan := h.plan("example_regex", nil, map[string]interface{}{"name": "test01"})
+	requireNoErrors(t, "PlanResourceChange", plan.Diagnostics)
+
+	if plan.Deferred != nil {
+		t.Fatalf("expected plan not to be deferred, got %v", plan.Deferred)
+	}
+
+	state := h.create("example_regex", map[string]interface{}{"name": "test01"})
+	h.destroy("example_regex", state)
+}
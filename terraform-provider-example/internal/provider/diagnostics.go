@@ -0,0 +1,140 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+// attributeGetter is satisfied by tfsdk.Config, tfsdk.Plan and tfsdk.State.
+type attributeGetter interface {
+	GetAttribute(ctx context.Context, p path.Path, target interface{}) diag.Diagnostics
+}
+
+// addClientError appends err to diags. Field errors returned by the backend,
+// either in an error response or in a failed operation, are attached to the
+// matching attribute of data, everything else is reported as a generic
+// "Client Error".
+func addClientError(ctx context.Context, diags *diag.Diagnostics, data attributeGetter, action string, err error) {
+	var fieldErrs []FieldError
+
+	var apiErr *APIError
+	var opErr *OperationError
+
+	switch {
+	case errors.As(err, &apiErr):
+		fieldErrs = apiErr.Errors
+	case errors.As(err, &opErr):
+		fieldErrs = opErr.Operation.Errors
+	}
+
+	if len(fieldErrs) == 0 {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to %s, got error: %s", action, err))
+		return
+	}
+
+	for _, fe := range fieldErrs {
+		msg := fe.Message
+
+		if fe.Field != "" {
+			if p, ok := fieldPath(ctx, data, fe.Field); ok {
+				diags.AddAttributeError(p, "Invalid Attribute Value", fmt.Sprintf("Unable to %s: %s", action, msg))
+				continue
+			}
+
+			msg = fe.Field + ": " + msg
+		}
+
+		diags.AddError("Client Error", fmt.Sprintf("Unable to %s, got error: %s", action, msg))
+	}
+}
+
+// fieldPath converts a backend field reference such as
+// "set_nested[0].fixed_ip" into the path of that attribute in data. Indexes
+// into a set refer to the order in which the elements were sent, which is the
+// order of Elements() on the set value, so they are resolved to AtSetValue
+// steps. It returns false if the reference does not match data.
+func fieldPath(ctx context.Context, data attributeGetter, field string) (path.Path, bool) {
+	var p path.Path
+
+	rest := field
+	for first := true; rest != ""; first = false {
+		if rest[0] == '[' {
+			end := strings.IndexByte(rest, ']')
+			if first || end < 0 {
+				return path.Empty(), false
+			}
+
+			key := rest[1:end]
+			rest = rest[end+1:]
+
+			var value attr.Value
+			if diags := data.GetAttribute(ctx, p, &value); diags.HasError() {
+				return path.Empty(), false
+			}
+
+			switch v := value.(type) {
+			case basetypes.ListValue:
+				i, err := strconv.Atoi(key)
+				if err != nil || i < 0 || i >= len(v.Elements()) {
+					return path.Empty(), false
+				}
+				p = p.AtListIndex(i)
+			case basetypes.SetValue:
+				i, err := strconv.Atoi(key)
+				if err != nil || i < 0 || i >= len(v.Elements()) {
+					return path.Empty(), false
+				}
+				p = p.AtSetValue(v.Elements()[i])
+			case basetypes.MapValue:
+				p = p.AtMapKey(key)
+			default:
+				return path.Empty(), false
+			}
+
+			continue
+		}
+
+		if !first {
+			if rest[0] != '.' {
+				return path.Empty(), false
+			}
+			rest = rest[1:]
+		}
+
+		end := strings.IndexAny(rest, ".[")
+		if end < 0 {
+			end = len(rest)
+		}
+
+		name := rest[:end]
+		rest = rest[end:]
+
+		if name == "" {
+			return path.Empty(), false
+		}
+
+		if first {
+			p = path.Root(name)
+		} else {
+			p = p.AtName(name)
+		}
+	}
+
+	var value attr.Value
+	if diags := data.GetAttribute(ctx, p, &value); diags.HasError() {
+		return path.Empty(), false
+	}
+
+	return p, true
+}
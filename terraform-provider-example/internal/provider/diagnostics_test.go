@@ -0,0 +1,171 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func testSetNestedPlan(t *testing.T, nics ...NicModel) tfsdk.Plan {
+	t.Helper()
+
+	ctx := context.Background()
+
+	var schemaResp resource.SchemaResponse
+	NewResourceSetNested().Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+	set, diags := types.SetValueFrom(ctx, types.ObjectType{AttrTypes: nicModelTypeMap}, nics)
+	if diags.HasError() {
+		t.Fatalf("unexpected error building set: %v", diags)
+	}
+
+	plan := tfsdk.Plan{Schema: schemaResp.Schema}
+	diags = plan.Set(ctx, &ResourceSetNestedModel{
+		Id:        types.StringUnknown(),
+		SetNested: set,
+		Tags:      types.MapNull(types.StringType),
+		TagsAll:   types.MapUnknown(types.StringType),
+		Timeouts: timeouts.Value{
+			Object: types.ObjectNull(map[string]attr.Type{"create": types.StringType}),
+		},
+	})
+	if diags.HasError() {
+		t.Fatalf("unexpected error setting plan: %v", diags)
+	}
+
+	return plan
+}
+
+func testNic(uuid, fixedIp string) NicModel {
+	return NicModel{
+		Uuid:          types.StringValue(uuid),
+		FixedIp:       types.StringValue(fixedIp),
+		FixedIpV4:     types.StringUnknown(),
+		Port:          types.StringUnknown(),
+		Mac:           types.StringUnknown(),
+		EnableGateway: types.BoolValue(false),
+	}
+}
+
+func TestFieldPath(t *testing.T) {
+	ctx := context.Background()
+	plan := testSetNestedPlan(t, testNic("net-1", "10.0.0.1"), testNic("net-2", "not-an-ip"))
+
+	var set types.Set
+	if diags := plan.GetAttribute(ctx, path.Root("set_nested"), &set); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	testCases := map[string]struct {
+		field    string
+		expected path.Path
+		ok       bool
+	}{
+		"root": {
+			field:    "id",
+			expected: path.Root("id"),
+			ok:       true,
+		},
+		"set-element-attribute": {
+			field:    "set_nested[1].fixed_ip",
+			expected: path.Root("set_nested").AtSetValue(set.Elements()[1]).AtName("fixed_ip"),
+			ok:       true,
+		},
+		"set-element": {
+			field:    "set_nested[0]",
+			expected: path.Root("set_nested").AtSetValue(set.Elements()[0]),
+			ok:       true,
+		},
+		"index-out-of-range": {
+			field: "set_nested[2].fixed_ip",
+		},
+		"index-not-a-number": {
+			field: "set_nested[a].fixed_ip",
+		},
+		"unknown-attribute": {
+			field: "set_nested[0].gateway",
+		},
+		"missing-index": {
+			field: "set_nested.fixed_ip",
+		},
+		"leading-index": {
+			field: "[0]",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, ok := fieldPath(ctx, plan, testCase.field)
+
+			if ok != testCase.ok {
+				t.Fatalf("expected ok %t, got %t", testCase.ok, ok)
+			}
+
+			if ok && !got.Equal(testCase.expected) {
+				t.Errorf("expected path %s, got %s", testCase.expected, got)
+			}
+		})
+	}
+}
+
+func TestAddClientError(t *testing.T) {
+	ctx := context.Background()
+	plan := testSetNestedPlan(t, testNic("net-1", "not-an-ip"))
+
+	var set types.Set
+	if diags := plan.GetAttribute(ctx, path.Root("set_nested"), &set); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	var diags diag.Diagnostics
+	addClientError(ctx, &diags, plan, "create set_nested", &APIError{
+		StatusCode: http.StatusUnprocessableEntity,
+		Errors: []FieldError{
+			{Field: "set_nested[0].fixed_ip", Message: `"not-an-ip" is not a valid IP address`},
+			{Field: "set_nested[3].uuid", Message: "uuid is required"},
+			{Message: "quota exceeded"},
+		},
+	})
+
+	if len(diags) != 3 {
+		t.Fatalf("expected 3 diagnostics, got %d: %v", len(diags), diags)
+	}
+
+	withPath, ok := diags[0].(diag.DiagnosticWithPath)
+	if !ok {
+		t.Fatalf("expected first diagnostic to have a path, got %T", diags[0])
+	}
+
+	expected := path.Root("set_nested").AtSetValue(set.Elements()[0]).AtName("fixed_ip")
+	if !withPath.Path().Equal(expected) {
+		t.Errorf("expected path %s, got %s", expected, withPath.Path())
+	}
+
+	for _, d := range diags[1:] {
+		if _, ok := d.(diag.DiagnosticWithPath); ok {
+			t.Errorf("expected diagnostic without path, got %v", d)
+		}
+		if d.Summary() != "Client Error" {
+			t.Errorf("expected summary %q, got %q", "Client Error", d.Summary())
+		}
+	}
+
+	diags = nil
+	addClientError(ctx, &diags, plan, "create set_nested", errors.New("connection refused"))
+
+	if len(diags) != 1 || diags[0].Summary() != "Client Error" {
+		t.Errorf("expected a single generic client error, got %v", diags)
+	}
+}
@@ -0,0 +1,238 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// dynamicToJSON encodes the value of a dynamic attribute as JSON. Lists,
+// sets and tuples become arrays, maps and objects become objects. Unknown
+// values cannot be encoded.
+func dynamicToJSON(ctx context.Context, v types.Dynamic) (json.RawMessage, error) {
+	data, err := valueToJSON(ctx, v)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(data)
+}
+
+func valueToJSON(ctx context.Context, v attr.Value) (interface{}, error) {
+	if v.IsUnknown() {
+		return nil, fmt.Errorf("unknown value cannot be encoded as JSON")
+	}
+
+	if v.IsNull() {
+		return nil, nil
+	}
+
+	var elems []attr.Value
+
+	switch v := v.(type) {
+	case types.Dynamic:
+		return valueToJSON(ctx, v.UnderlyingValue())
+	case types.String:
+		return v.ValueString(), nil
+	case types.Bool:
+		return v.ValueBool(), nil
+	case types.Number:
+		f := v.ValueBigFloat()
+		if f.IsInt() {
+			return json.Number(f.Text('f', -1)), nil
+		}
+		return json.Number(f.Text('g', -1)), nil
+	case types.Int64:
+		return v.ValueInt64(), nil
+	case types.Float64:
+		return v.ValueFloat64(), nil
+	case types.List:
+		elems = v.Elements()
+	case types.Set:
+		elems = v.Elements()
+	case types.Tuple:
+		elems = v.Elements()
+	case types.Map:
+		return attributesToJSON(ctx, v.Elements())
+	case types.Object:
+		return attributesToJSON(ctx, v.Attributes())
+	default:
+		return nil, fmt.Errorf("unsupported value type %s", v.Type(ctx))
+	}
+
+	items := make([]interface{}, 0, len(elems))
+	for i, elem := range elems {
+		item, err := valueToJSON(ctx, elem)
+		if err != nil {
+			return nil, fmt.Errorf("[%d]: %w", i, err)
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func attributesToJSON(ctx context.Context, attrs map[string]attr.Value) (interface{}, error) {
+	m := make(map[string]interface{}, len(attrs))
+	for k, value := range attrs {
+		item, err := valueToJSON(ctx, value)
+		if err != nil {
+			return nil, fmt.Errorf(".%s: %w", k, err)
+		}
+		m[k] = item
+	}
+
+	return m, nil
+}
+
+// dynamicFromJSON decodes JSON into the value of a dynamic attribute. Objects
+// become object values and arrays tuples, as their HCL literals would.
+func dynamicFromJSON(ctx context.Context, data []byte) (types.Dynamic, error) {
+	v, err := decodeJSON(data)
+	if err != nil {
+		return types.DynamicNull(), err
+	}
+
+	if v == nil {
+		return types.DynamicNull(), nil
+	}
+
+	value, err := valueFromJSON(ctx, v)
+	if err != nil {
+		return types.DynamicNull(), err
+	}
+
+	return types.DynamicValue(value), nil
+}
+
+func valueFromJSON(ctx context.Context, v interface{}) (attr.Value, error) {
+	switch v := v.(type) {
+	case nil:
+		return types.DynamicNull(), nil
+	case string:
+		return types.StringValue(v), nil
+	case bool:
+		return types.BoolValue(v), nil
+	case json.Number:
+		f, _, err := big.ParseFloat(v.String(), 10, 512, big.ToNearestEven)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %s: %w", v, err)
+		}
+		return types.NumberValue(f), nil
+	case []interface{}:
+		elemTypes := make([]attr.Type, 0, len(v))
+		elems := make([]attr.Value, 0, len(v))
+		for i, item := range v {
+			elem, err := valueFromJSON(ctx, item)
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+			elemTypes = append(elemTypes, elem.Type(ctx))
+			elems = append(elems, elem)
+		}
+
+		value, diags := types.TupleValue(elemTypes, elems)
+		if diags.HasError() {
+			return nil, fmt.Errorf("invalid array: %s", diags.Errors()[0].Detail())
+		}
+		return value, nil
+	case map[string]interface{}:
+		attrTypes := make(map[string]attr.Type, len(v))
+		attrs := make(map[string]attr.Value, len(v))
+		for k, item := range v {
+			value, err := valueFromJSON(ctx, item)
+			if err != nil {
+				return nil, fmt.Errorf(".%s: %w", k, err)
+			}
+			attrTypes[k] = value.Type(ctx)
+			attrs[k] = value
+		}
+
+		value, diags := types.ObjectValue(attrTypes, attrs)
+		if diags.HasError() {
+			return nil, fmt.Errorf("invalid object: %s", diags.Errors()[0].Detail())
+		}
+		return value, nil
+	}
+
+	return nil, fmt.Errorf("unsupported JSON value %T", v)
+}
+
+// jsonEqual reports whether a and b encode the same structure, regardless of
+// whitespace, object key order and number formatting.
+func jsonEqual(a, b []byte) bool {
+	va, err := decodeJSON(a)
+	if err != nil {
+		return false
+	}
+
+	vb, err := decodeJSON(b)
+	if err != nil {
+		return false
+	}
+
+	return jsonValuesEqual(va, vb)
+}
+
+func jsonValuesEqual(a, b interface{}) bool {
+	switch a := a.(type) {
+	case json.Number:
+		b, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+
+		fa, _, errA := big.ParseFloat(a.String(), 10, 512, big.ToNearestEven)
+		fb, _, errB := big.ParseFloat(b.String(), 10, 512, big.ToNearestEven)
+		return errA == nil && errB == nil && fa.Cmp(fb) == 0
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+
+		for i := range a {
+			if !jsonValuesEqual(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		b, ok := b.(map[string]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+
+		for k, va := range a {
+			vb, ok := b[k]
+			if !ok || !jsonValuesEqual(va, vb) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return a == b
+}
+
+// decodeJSON decodes data keeping numbers as json.Number, so that large
+// integers survive a round trip.
+func decodeJSON(data []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
@@ -0,0 +1,172 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestDynamicJSON(t *testing.T) {
+	ctx := context.Background()
+
+	testCases := map[string]struct {
+		value    attr.Value
+		expected string
+	}{
+		"string": {
+			value:    types.StringValue("a"),
+			expected: `"a"`,
+		},
+		"number": {
+			value:    types.NumberValue(big.NewFloat(1.5)),
+			expected: `1.5`,
+		},
+		"large-integer": {
+			value:    types.NumberValue(new(big.Float).SetInt64(1 << 60)),
+			expected: `1152921504606846976`,
+		},
+		"list": {
+			value:    types.ListValueMust(types.StringType, []attr.Value{types.StringValue("a"), types.StringValue("b")}),
+			expected: `["a","b"]`,
+		},
+		"tuple": {
+			value: types.TupleValueMust(
+				[]attr.Type{types.StringType, types.BoolType},
+				[]attr.Value{types.StringValue("a"), types.BoolValue(true)},
+			),
+			expected: `["a",true]`,
+		},
+		"object": {
+			value: types.ObjectValueMust(
+				map[string]attr.Type{"name": types.StringType, "tags": types.MapType{ElemType: types.StringType}},
+				map[string]attr.Value{
+					"name": types.StringValue("web"),
+					"tags": types.MapValueMust(types.StringType, map[string]attr.Value{"env": types.StringValue("dev")}),
+				},
+			),
+			expected: `{"name":"web","tags":{"env":"dev"}}`,
+		},
+		"null-attribute": {
+			value: types.ObjectValueMust(
+				map[string]attr.Type{"name": types.StringType},
+				map[string]attr.Value{"name": types.StringNull()},
+			),
+			expected: `{"name":null}`,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			data, err := dynamicToJSON(ctx, types.DynamicValue(tc.value))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if string(data) != tc.expected {
+				t.Errorf("expected %s, got %s", tc.expected, data)
+			}
+
+			decoded, err := dynamicFromJSON(ctx, data)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			roundTrip, err := dynamicToJSON(ctx, decoded)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !jsonEqual(data, roundTrip) {
+				t.Errorf("expected %s after round trip, got %s", data, roundTrip)
+			}
+		})
+	}
+
+	if _, err := dynamicToJSON(ctx, types.DynamicValue(types.StringUnknown())); err == nil {
+		t.Error("expected error for unknown value")
+	}
+}
+
+func TestDynamicFromJSON_Types(t *testing.T) {
+	ctx := context.Background()
+
+	value, err := dynamicFromJSON(ctx, []byte(`{"ports":[80,"443"],"enabled":true}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := types.ObjectValueMust(
+		map[string]attr.Type{
+			"ports":   types.TupleType{ElemTypes: []attr.Type{types.NumberType, types.StringType}},
+			"enabled": types.BoolType,
+		},
+		map[string]attr.Value{
+			"ports": types.TupleValueMust(
+				[]attr.Type{types.NumberType, types.StringType},
+				[]attr.Value{types.NumberValue(big.NewFloat(80)), types.StringValue("443")},
+			),
+			"enabled": types.BoolValue(true),
+		},
+	)
+
+	if !value.UnderlyingValue().Equal(expected) {
+		t.Errorf("expected %s, got %s", expected, value.UnderlyingValue())
+	}
+}
+
+func TestJSONEqual(t *testing.T) {
+	testCases := map[string]struct {
+		a, b     string
+		expected bool
+	}{
+		"key-order": {
+			a:        `{"a":1,"b":[true,null]}`,
+			b:        `{ "b": [true, null], "a": 1 }`,
+			expected: true,
+		},
+		"number-format": {
+			a:        `[1, 100]`,
+			b:        `[1.0, 1e2]`,
+			expected: true,
+		},
+		"array-order": {
+			a:        `[1, 2]`,
+			b:        `[2, 1]`,
+			expected: false,
+		},
+		"changed-value": {
+			a:        `{"a":"x"}`,
+			b:        `{"a":"y"}`,
+			expected: false,
+		},
+		"missing-key": {
+			a:        `{"a":1,"b":null}`,
+			b:        `{"a":1}`,
+			expected: false,
+		},
+		"string-number": {
+			a:        `"1"`,
+			b:        `1`,
+			expected: false,
+		},
+		"invalid": {
+			a:        `{`,
+			b:        `{`,
+			expected: false,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if got := jsonEqual([]byte(tc.a), []byte(tc.b)); got != tc.expected {
+				t.Errorf("expected %t, got %t", tc.expected, got)
+			}
+		})
+	}
+}
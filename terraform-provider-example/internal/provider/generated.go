@@ -0,0 +1,47 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+)
+
+// Resources in resource_*_gen.go are generated from the OpenAPI description
+// of terraform-service by tools/resourcegen. The hand-written file of such
+// a resource customises it by implementing the interfaces below.
+
+// schemaCustomizer is implemented by generated resources whose schema needs
+// more than the OpenAPI description says, e.g. validators or plan
+// modifiers. crudResource calls customizeSchema with the generated schema.
+type schemaCustomizer interface {
+	customizeSchema(ctx context.Context, s *schema.Schema) diag.Diagnostics
+}
+
+// stateCustomizer is implemented by models of generated resources that
+// compute attributes the backend does not return. customizeState is called
+// after the backend object is copied into the model.
+type stateCustomizer interface {
+	customizeState(ctx context.Context) diag.Diagnostics
+}
+
+// generatedAttribute returns the attribute name of attributes for
+// customizeSchema, or an error if the generator no longer generates it as a
+// T.
+func generatedAttribute[T schema.Attribute](attributes map[string]schema.Attribute, name string) (T, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	attribute, ok := attributes[name].(T)
+	if !ok {
+		diags.AddError(
+			"Unexpected Generated Schema",
+			fmt.Sprintf("Expected attribute %s to be generated as %T, got: %T. Please report this issue to the provider developers.", name, attribute, attributes[name]),
+		)
+	}
+
+	return attribute, diags
+}
@@ -0,0 +1,40 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+)
+
+func TestGeneratedAttribute(t *testing.T) {
+	attributes := map[string]schema.Attribute{
+		"name": schema.StringAttribute{Required: true},
+	}
+
+	name, diags := generatedAttribute[schema.StringAttribute](attributes, "name")
+	if diags.HasError() || !name.Required {
+		t.Errorf("expected the name attribute, got %v: %v", name, diags)
+	}
+
+	for _, attribute := range []string{"name", "missing"} {
+		_, diags = generatedAttribute[schema.BoolAttribute](attributes, attribute)
+		if !diags.HasError() || !strings.Contains(diags[0].Detail(), "Expected attribute "+attribute+" to be generated as schema.BoolAttribute") {
+			t.Errorf("expected an error for %s, got %v", attribute, diags)
+		}
+	}
+}
+
+func TestResourceRegex_CustomizeSchema(t *testing.T) {
+	s := ResourceRegex{}.schema(context.Background())
+	delete(s.Attributes, "alias")
+
+	diags := ResourceRegex{}.customizeSchema(context.Background(), &s)
+	if !diags.HasError() || !strings.Contains(diags[0].Detail(), "Expected attribute alias") {
+		t.Errorf("expected an error for the missing alias attribute, got %v", diags)
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+
+	"terraform-service/server"
+)
+
+// mockEndpoint is the endpoint of the client in mock mode. Requests never
+// leave the process, the host is only used to build request URLs.
+const mockEndpoint = "http://mock.terraform-service"
+
+// defaultMockStatePath is where the mock backend keeps its objects when
+// EXAMPLE_MOCK_STATE is not set. Terraform runs providers in the working
+// directory, so objects survive from one command to the next like they
+// would in a real backend.
+var defaultMockStatePath = filepath.Join(".terraform", "example-mock-state.json")
+
+// mockEnabled reports whether mock mode is requested by the EXAMPLE_MOCK
+// environment variable.
+func mockEnabled() bool {
+	v, _ := strconv.ParseBool(os.Getenv("EXAMPLE_MOCK"))
+	return v
+}
+
+// mockStatePath returns the file the mock backend persists its objects to.
+func mockStatePath() string {
+	if p := os.Getenv("EXAMPLE_MOCK_STATE"); p != "" {
+		return p
+	}
+
+	return defaultMockStatePath
+}
+
+// newMockTransport returns a transport to a terraform-service whose objects
+// are kept in mockStatePath, and which accepts the credentials of auth so
+// that authentication is exercised as well.
+func newMockTransport(auth authOptions) (http.RoundTripper, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	store, err := server.NewFileStore(mockStatePath())
+	if err != nil {
+		diags.AddError(
+			"Unable to Load Mock State",
+			fmt.Sprintf("Unable to load the objects of the mock backend from %s: %s", mockStatePath(), err),
+		)
+		return nil, diags
+	}
+
+	opts := server.Options{Store: store}
+
+	switch auth.Mode {
+	case authModeOAuth2:
+		opts.OAuthClients = map[string]string{auth.ClientId: auth.ClientSecret}
+	case authModeHMAC:
+		opts.HMACKeys = map[string]string{auth.AccessKey: auth.SecretKey}
+	}
+
+	return &mockTransport{handler: server.New(opts)}, diags
+}
+
+// mockTransport serves requests with an in-process terraform-service
+// instead of sending them over the network, so the mock backend implements
+// exactly the semantics of the real one: ID generation, validation,
+// asynchronous operations and field errors.
+type mockTransport struct {
+	handler http.Handler
+}
+
+func (t *mockTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		defer req.Body.Close()
+	}
+
+	rec := httptest.NewRecorder()
+	t.handler.ServeHTTP(rec, req)
+
+	resp := rec.Result()
+	resp.Request = req
+
+	return resp, nil
+}
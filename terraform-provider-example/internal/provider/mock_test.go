@@ -0,0 +1,170 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestProtocolMock(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "mock.json")
+	t.Setenv("EXAMPLE_MOCK_STATE", statePath)
+
+	// The endpoint is ignored in mock mode, nothing listens on it.
+	h := newProtocolHarness(t, map[string]interface{}{
+		"endpoint": "http://127.0.0.1:1",
+		"mock":     true,
+	})
+
+	regex := h.create("example_regex", map[string]interface{}{"name": "test01"})
+	if id, _ := regex["id"].(string); !strings.HasPrefix(id, "vm-") {
+		t.Fatalf("expected id assigned by the mock backend, got %v", regex["id"])
+	}
+
+	nested := h.create("example_set_nested", map[string]interface{}{
+		"set_nested": []interface{}{
+			map[string]interface{}{"uuid": "net-1", "fixed_ip": "10.0.0.10", "enable_gateway": true},
+		},
+	})
+
+	expected := []interface{}{map[string]interface{}{
+		"uuid":           "net-1",
+		"fixed_ip":       "10.0.0.10",
+		"fixed_ip_v4":    "10.0.0.10",
+		"port":           "port_id_0",
+		"mac":            "mac_address_0",
+		"enable_gateway": true,
+	}}
+	if !knownValuesMatch(expected, nested["set_nested"]) {
+		t.Errorf("expected set_nested %v, got %v", expected, nested["set_nested"])
+	}
+
+	// The mock backend validates like the real one.
+	plan := h.plan("example_regex", nil, map[string]interface{}{"name": "test01"})
+	requireNoErrors(t, "PlanResourceChange", plan.Diagnostics)
+
+	apply := h.apply("example_regex", nil, map[string]interface{}{"name": "test01"}, plan)
+	requireError(t, "ApplyResourceChange", apply.Diagnostics, "already in use")
+
+	if _, err := os.Stat(statePath); err != nil {
+		t.Fatalf("expected mock state to be written: %s", err)
+	}
+
+	// Objects survive a restart of the provider, as they do between
+	// Terraform commands.
+	h = newProtocolHarness(t, map[string]interface{}{"mock": true})
+
+	read := h.read("example_regex", regex)
+	requireNoErrors(t, "ReadResource", read.Diagnostics)
+
+	if got := h.decode("example_regex", read.NewState); !knownValuesMatch(regex, got) {
+		t.Errorf("expected %v after restart, got %v", regex, got)
+	}
+
+	h.destroy("example_regex", regex)
+	h.destroy("example_set_nested", nested)
+
+	read = h.read("example_regex", regex)
+	requireNoErrors(t, "ReadResource", read.Diagnostics)
+
+	if got := h.decode("example_regex", read.NewState); got != nil {
+		t.Errorf("expected resource to be removed from state, got %v", got)
+	}
+}
+
+func TestProtocolMock_Env(t *testing.T) {
+	t.Setenv("EXAMPLE_MOCK", "1")
+	t.Setenv("EXAMPLE_MOCK_STATE", filepath.Join(t.TempDir(), "mock.json"))
+	t.Setenv("EXAMPLE_ENDPOINT", "http://127.0.0.1:1")
+
+	h := newProtocolHarness(t, nil)
+
+	state := h.create("example_document", map[string]interface{}{
+		"content": map[string]interface{}{"name": "web"},
+	})
+	h.destroy("example_document", state)
+
+	// mock = false in the configuration overrides the environment.
+	h = newProtocolHarness(t, map[string]interface{}{"mock": false, "max_attempts": 1})
+
+	plan := h.plan("example_regex", nil, map[string]interface{}{"name": "test01"})
+	requireNoErrors(t, "PlanResourceChange", plan.Diagnostics)
+
+	apply := h.apply("example_regex", nil, map[string]interface{}{"name": "test01"}, plan)
+	requireError(t, "ApplyResourceChange", apply.Diagnostics, "Client Error")
+}
+
+// Aliased providers run in processes of their own which share the state
+// file, none of them loses the objects written by the others.
+func TestProtocolMock_SharedState(t *testing.T) {
+	t.Setenv("EXAMPLE_MOCK_STATE", filepath.Join(t.TempDir(), "mock.json"))
+
+	first := newProtocolHarness(t, map[string]interface{}{"mock": true, "project": "p1"})
+	second := newProtocolHarness(t, map[string]interface{}{"mock": true, "project": "p2"})
+
+	one := first.create("example_regex", map[string]interface{}{"name": "test01"})
+	two := second.create("example_regex", map[string]interface{}{"name": "test02"})
+	three := first.create("example_regex", map[string]interface{}{"name": "test03"})
+
+	h := newProtocolHarness(t, map[string]interface{}{"mock": true})
+
+	for _, state := range []map[string]interface{}{one, two, three} {
+		read := h.read("example_regex", state)
+		requireNoErrors(t, "ReadResource", read.Diagnostics)
+
+		if got := h.decode("example_regex", read.NewState); !knownValuesMatch(state, got) {
+			t.Errorf("expected %v to be kept, got %v", state, got)
+		}
+	}
+
+	// Objects deleted by one provider are gone for the other ones.
+	second.destroy("example_regex", two)
+
+	read := first.read("example_regex", two)
+	requireNoErrors(t, "ReadResource", read.Diagnostics)
+
+	if got := first.decode("example_regex", read.NewState); got != nil {
+		t.Errorf("expected resource to be removed from state, got %v", got)
+	}
+}
+
+func TestProtocolMock_InvalidState(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "mock.json")
+	if err := os.WriteFile(statePath, []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("EXAMPLE_MOCK_STATE", statePath)
+
+	h := newProtocolHarness(t, nil)
+
+	requireError(t, "ConfigureProvider", h.configureError(map[string]interface{}{"mock": true}), "Unable to Load Mock State")
+}
+
+func TestProtocolMock_HMAC(t *testing.T) {
+	t.Setenv("EXAMPLE_MOCK_STATE", filepath.Join(t.TempDir(), "mock.json"))
+
+	h := newProtocolHarness(t, map[string]interface{}{
+		"mock":       true,
+		"auth_mode":  "hmac",
+		"access_key": "access",
+		"secret_key": "secret",
+	})
+
+	state := h.create("example_regex", map[string]interface{}{"name": "test01"})
+	h.destroy("example_regex", state)
+}
+
+func TestProtocolMock_OAuth(t *testing.T) {
+	t.Setenv("EXAMPLE_MOCK_STATE", filepath.Join(t.TempDir(), "mock.json"))
+	t.Setenv("EXAMPLE_CLIENT_ID", "client")
+	t.Setenv("EXAMPLE_CLIENT_SECRET", "secret")
+
+	h := newProtocolHarness(t, map[string]interface{}{"mock": true})
+
+	state := h.create("example_regex", map[string]interface{}{"name": "test01"})
+	h.destroy("example_regex", state)
+}
@@ -0,0 +1,144 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/attr/xattr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ basetypes.StringTypable = NormalizedJSONType{}
+var _ basetypes.StringValuableWithSemanticEquals = NormalizedJSON{}
+var _ xattr.ValidateableAttribute = NormalizedJSON{}
+
+// NormalizedJSONType is a string type holding a JSON document. Values that
+// encode the same structure are semantically equal, so formatting changes
+// made by the backend, such as whitespace or key order, are not drift.
+type NormalizedJSONType struct {
+	basetypes.StringType
+}
+
+func (t NormalizedJSONType) String() string {
+	return "NormalizedJSONType"
+}
+
+func (t NormalizedJSONType) ValueType(ctx context.Context) attr.Value {
+	return NormalizedJSON{}
+}
+
+func (t NormalizedJSONType) Equal(o attr.Type) bool {
+	other, ok := o.(NormalizedJSONType)
+	if !ok {
+		return false
+	}
+
+	return t.StringType.Equal(other.StringType)
+}
+
+func (t NormalizedJSONType) ValueFromString(ctx context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return NormalizedJSON{StringValue: in}, nil
+}
+
+func (t NormalizedJSONType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	attrValue, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	stringValue, ok := attrValue.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", attrValue)
+	}
+
+	return NormalizedJSON{StringValue: stringValue}, nil
+}
+
+// NormalizedJSON is a value of NormalizedJSONType.
+type NormalizedJSON struct {
+	basetypes.StringValue
+}
+
+// NewNormalizedJSONNull returns a null NormalizedJSON.
+func NewNormalizedJSONNull() NormalizedJSON {
+	return NormalizedJSON{StringValue: basetypes.NewStringNull()}
+}
+
+// NewNormalizedJSONValue returns a known NormalizedJSON holding value.
+func NewNormalizedJSONValue(value string) NormalizedJSON {
+	return NormalizedJSON{StringValue: basetypes.NewStringValue(value)}
+}
+
+// newNormalizedJSONFromRaw returns a NormalizedJSON holding a JSON document
+// of the backend, null if the backend omitted it.
+func newNormalizedJSONFromRaw(raw json.RawMessage) NormalizedJSON {
+	if len(raw) == 0 {
+		return NewNormalizedJSONNull()
+	}
+
+	return NewNormalizedJSONValue(string(raw))
+}
+
+func (v NormalizedJSON) Type(ctx context.Context) attr.Type {
+	return NormalizedJSONType{}
+}
+
+func (v NormalizedJSON) Equal(o attr.Value) bool {
+	other, ok := o.(NormalizedJSON)
+	if !ok {
+		return false
+	}
+
+	return v.StringValue.Equal(other.StringValue)
+}
+
+// StringSemanticEquals reports whether both values encode the same JSON
+// structure.
+func (v NormalizedJSON) StringSemanticEquals(ctx context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	newValue, ok := newValuable.(NormalizedJSON)
+	if !ok {
+		diags.AddError(
+			"Semantic Equality Check Error",
+			fmt.Sprintf("Expected value type %T, got: %T. Please report this issue to the provider developers.", v, newValuable),
+		)
+
+		return false, diags
+	}
+
+	return jsonEqual([]byte(v.ValueString()), []byte(newValue.ValueString())), diags
+}
+
+// ValidateAttribute rejects strings that are not valid JSON.
+func (v NormalizedJSON) ValidateAttribute(ctx context.Context, req xattr.ValidateAttributeRequest, resp *xattr.ValidateAttributeResponse) {
+	if v.IsNull() || v.IsUnknown() {
+		return
+	}
+
+	if !json.Valid([]byte(v.ValueString())) {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid JSON String Value",
+			fmt.Sprintf("A string value was provided that is not valid JSON.\n\nGiven Value: %s", v.ValueString()),
+		)
+	}
+}
+
+// ValueRaw returns the JSON document, or nil if the value is null or
+// unknown.
+func (v NormalizedJSON) ValueRaw() json.RawMessage {
+	if v.IsNull() || v.IsUnknown() {
+		return nil
+	}
+
+	return json.RawMessage(v.ValueString())
+}
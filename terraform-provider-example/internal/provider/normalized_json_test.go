@@ -0,0 +1,90 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr/xattr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+func TestNormalizedJSON_StringSemanticEquals(t *testing.T) {
+	testCases := map[string]struct {
+		a, b     string
+		expected bool
+	}{
+		"whitespace": {
+			a:        `{"a": [1, 2]}`,
+			b:        `{"a":[1,2]}`,
+			expected: true,
+		},
+		"key-order": {
+			a:        `{"b": 1, "a": 2}`,
+			b:        `{"a": 2, "b": 1}`,
+			expected: true,
+		},
+		"changed": {
+			a:        `{"a": 1}`,
+			b:        `{"a": 2}`,
+			expected: false,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, diags := NewNormalizedJSONValue(tc.a).StringSemanticEquals(context.Background(), NewNormalizedJSONValue(tc.b))
+			if diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+
+			if got != tc.expected {
+				t.Errorf("expected %t, got %t", tc.expected, got)
+			}
+		})
+	}
+
+	_, diags := NewNormalizedJSONValue(`{}`).StringSemanticEquals(context.Background(), basetypes.NewStringValue(`{}`))
+	if !diags.HasError() {
+		t.Error("expected error for mismatched value type")
+	}
+}
+
+func TestNormalizedJSON_ValidateAttribute(t *testing.T) {
+	testCases := map[string]struct {
+		value     NormalizedJSON
+		expectErr bool
+	}{
+		"object": {
+			value: NewNormalizedJSONValue(`{"a": 1}`),
+		},
+		"primitive": {
+			value: NewNormalizedJSONValue(`"a"`),
+		},
+		"null": {
+			value: NewNormalizedJSONNull(),
+		},
+		"invalid": {
+			value:     NewNormalizedJSONValue(`{"a": 1`),
+			expectErr: true,
+		},
+		"empty": {
+			value:     NewNormalizedJSONValue(``),
+			expectErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			resp := &xattr.ValidateAttributeResponse{}
+			tc.value.ValidateAttribute(context.Background(), xattr.ValidateAttributeRequest{Path: path.Root("user_data_json")}, resp)
+
+			if got := resp.Diagnostics.HasError(); got != tc.expectErr {
+				t.Errorf("expected error %t, got %v", tc.expectErr, resp.Diagnostics)
+			}
+		})
+	}
+}
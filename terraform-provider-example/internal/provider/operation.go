@@ -0,0 +1,201 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Statuses of an asynchronous backend operation.
+const (
+	OperationPending   = "pending"
+	OperationRunning   = "running"
+	OperationSucceeded = "succeeded"
+	OperationFailed    = "failed"
+)
+
+// Operation is the status document of a long-running backend job, served by
+// terraform-service under /operations/{id}.
+type Operation struct {
+	Id         string       `json:"id"`
+	Status     string       `json:"status"`
+	Progress   int          `json:"progress"`
+	Message    string       `json:"message,omitempty"`
+	ResourceId string       `json:"resource_id,omitempty"`
+	Errors     []FieldError `json:"errors,omitempty"`
+
+	// URL is where the operation is polled. StartOperation sets it from the
+	// Location header of the 202 Accepted response.
+	URL string `json:"-"`
+}
+
+// OperationError is returned by WaitForOperation when the operation ends in
+// the failed state.
+type OperationError struct {
+	Operation Operation
+}
+
+func (e *OperationError) Error() string {
+	msg := fmt.Sprintf("operation %s failed", e.Operation.Id)
+	if e.Operation.Message != "" {
+		msg += ": " + e.Operation.Message
+	}
+
+	if len(e.Operation.Errors) > 0 {
+		msg += " (" + (&APIError{Errors: e.Operation.Errors}).messages() + ")"
+	}
+
+	return msg
+}
+
+// WaitOptions configures the exponential backoff used while polling an
+// operation.
+type WaitOptions struct {
+	// MinInterval is the delay before the first poll.
+	MinInterval time.Duration
+
+	// MaxInterval caps the delay between two polls.
+	MaxInterval time.Duration
+
+	// Multiplier is applied to the delay after every poll.
+	Multiplier float64
+
+	// Jitter randomizes every delay by up to this fraction in either
+	// direction so that parallel resources do not poll in lockstep.
+	Jitter float64
+}
+
+// defaultTimeout bounds the operations of resources with a timeouts block
+// when the block does not set them.
+const defaultTimeout = 20 * time.Minute
+
+var defaultWaitOptions = WaitOptions{
+	MinInterval: 500 * time.Millisecond,
+	MaxInterval: 10 * time.Second,
+	Multiplier:  2,
+	Jitter:      0.2,
+}
+
+// StartOperation sends a request to an asynchronous endpoint and returns the
+// operation of the 202 Accepted response. The operation carries the ID of the
+// object it creates, which exists in the backend unless the operation fails.
+func (c *Client) StartOperation(ctx context.Context, method, path string, in interface{}) (*Operation, error) {
+	var op Operation
+
+	httpResp, err := c.do(ctx, method, path, in, &op)
+	if err != nil {
+		return nil, err
+	}
+
+	if httpResp.StatusCode != http.StatusAccepted {
+		return nil, fmt.Errorf("expected status code %d for asynchronous request, got %d", http.StatusAccepted, httpResp.StatusCode)
+	}
+
+	switch {
+	case httpResp.Header.Get("Location") != "":
+		op.URL = httpResp.Header.Get("Location")
+	case op.Id != "":
+		op.URL = "/operations/" + op.Id
+	default:
+		return nil, fmt.Errorf("backend accepted the request without returning an operation URL")
+	}
+
+	return &op, nil
+}
+
+// createdResourceID returns the ID of the object the accepted operation op
+// creates, after waiting for op ended with err. Only operations that failed
+// did not create their object: when the wait times out or ctx is done, the
+// backend still finishes the job. Creates return the ID with err so that
+// Terraform keeps the object as tainted instead of losing track of it.
+func createdResourceID(op *Operation, err error) string {
+	var opErr *OperationError
+	if errors.As(err, &opErr) {
+		return ""
+	}
+
+	return op.ResourceId
+}
+
+// WaitForOperation polls operationURL until the operation succeeds, fails or
+// ctx is done. Callers bound the wait by deriving ctx from the resource
+// timeouts.
+func (c *Client) WaitForOperation(ctx context.Context, operationURL string) (*Operation, error) {
+	return c.WaitForOperationProgress(ctx, operationURL, nil)
+}
+
+// WaitForOperationProgress is WaitForOperation calling progress, unless it
+// is nil, whenever the status, progress or message of the pending or running
+// operation changes.
+func (c *Client) WaitForOperationProgress(ctx context.Context, operationURL string, progress func(op Operation)) (*Operation, error) {
+	interval := c.Wait.MinInterval
+
+	var reported *Operation
+
+	for attempt := 1; ; attempt++ {
+		var op Operation
+		if err := c.Do(ctx, http.MethodGet, operationURL, nil, &op); err != nil {
+			return nil, fmt.Errorf("polling operation %s: %w", operationURL, err)
+		}
+
+		fields := map[string]interface{}{
+			"operation_id": op.Id,
+			"status":       op.Status,
+			"progress":     op.Progress,
+			"attempt":      attempt,
+		}
+		if op.Message != "" {
+			fields["message"] = op.Message
+		}
+
+		switch op.Status {
+		case OperationSucceeded:
+			tflog.Debug(ctx, "operation succeeded", fields)
+			return &op, nil
+		case OperationFailed:
+			tflog.Warn(ctx, "operation failed", fields)
+			return &op, &OperationError{Operation: op}
+		case OperationPending, OperationRunning:
+			tflog.Info(ctx, "waiting for operation", fields)
+
+			if progress != nil && (reported == nil || reported.Status != op.Status || reported.Progress != op.Progress || reported.Message != op.Message) {
+				progress(op)
+				reported = &op
+			}
+		default:
+			return &op, fmt.Errorf("operation %s has unexpected status %q", op.Id, op.Status)
+		}
+
+		timer := time.NewTimer(withJitter(interval, c.Wait.Jitter))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return &op, fmt.Errorf("waiting for operation %s (status %q, %d%% done): %w", op.Id, op.Status, op.Progress, ctx.Err())
+		case <-timer.C:
+		}
+
+		interval = time.Duration(float64(interval) * c.Wait.Multiplier)
+		if interval > c.Wait.MaxInterval {
+			interval = c.Wait.MaxInterval
+		}
+	}
+}
+
+// withJitter returns d randomized by up to fraction of d in either direction.
+func withJitter(d time.Duration, fraction float64) time.Duration {
+	if fraction <= 0 || d <= 0 {
+		return d
+	}
+
+	delta := float64(d) * fraction
+
+	return time.Duration(float64(d) - delta + rand.Float64()*2*delta)
+}
@@ -0,0 +1,185 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var testWaitOptions = WaitOptions{
+	MinInterval: time.Millisecond,
+	MaxInterval: 5 * time.Millisecond,
+	Multiplier:  2,
+	Jitter:      0.2,
+}
+
+// testOperationServer serves an operation that reports the given statuses,
+// one per poll, repeating the last one.
+func testOperationServer(t *testing.T, statuses ...Operation) (*httptest.Server, *int32) {
+	t.Helper()
+
+	var polls int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/regex":
+			w.Header().Set("Location", "/operations/op-1")
+			w.WriteHeader(http.StatusAccepted)
+			_ = json.NewEncoder(w).Encode(Operation{Id: "op-1", Status: OperationPending})
+		case r.Method == http.MethodGet && r.URL.Path == "/operations/op-1":
+			i := int(atomic.AddInt32(&polls, 1)) - 1
+			if i >= len(statuses) {
+				i = len(statuses) - 1
+			}
+			_ = json.NewEncoder(w).Encode(statuses[i])
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &polls
+}
+
+func TestWaitForOperation_Succeeded(t *testing.T) {
+	server, polls := testOperationServer(t,
+		Operation{Id: "op-1", Status: OperationPending},
+		Operation{Id: "op-1", Status: OperationRunning, Progress: 40},
+		Operation{Id: "op-1", Status: OperationSucceeded, Progress: 100, ResourceId: "vm-1"},
+	)
+
+	client := NewClient(server.URL, server.Client())
+	client.Wait = testWaitOptions
+
+	ctx := context.Background()
+
+	accepted, err := client.StartOperation(ctx, http.MethodPost, "/regex", regexAPIModel{Name: "test01"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if accepted.URL != "/operations/op-1" {
+		t.Errorf("expected operation URL /operations/op-1, got %q", accepted.URL)
+	}
+
+	op, err := client.WaitForOperation(ctx, accepted.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if op.ResourceId != "vm-1" {
+		t.Errorf("expected resource id vm-1, got %q", op.ResourceId)
+	}
+
+	if got := atomic.LoadInt32(polls); got != 3 {
+		t.Errorf("expected 3 polls, got %d", got)
+	}
+}
+
+func TestWaitForOperationProgress(t *testing.T) {
+	server, _ := testOperationServer(t,
+		Operation{Id: "op-1", Status: OperationPending},
+		Operation{Id: "op-1", Status: OperationPending},
+		Operation{Id: "op-1", Status: OperationRunning, Progress: 40},
+		Operation{Id: "op-1", Status: OperationRunning, Progress: 40, Message: "rebooting"},
+		Operation{Id: "op-1", Status: OperationSucceeded, Progress: 100},
+	)
+
+	client := NewClient(server.URL, server.Client())
+	client.Wait = testWaitOptions
+
+	var reported []string
+	_, err := client.WaitForOperationProgress(context.Background(), "/operations/op-1", func(op Operation) {
+		reported = append(reported, fmt.Sprintf("%s %d %s", op.Status, op.Progress, op.Message))
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// Unchanged statuses are only reported once, the final one not at all.
+	expected := []string{"pending 0 ", "running 40 ", "running 40 rebooting"}
+	if fmt.Sprint(reported) != fmt.Sprint(expected) {
+		t.Errorf("expected progress %q, got %q", expected, reported)
+	}
+}
+
+func TestWaitForOperation_Failed(t *testing.T) {
+	server, _ := testOperationServer(t,
+		Operation{Id: "op-1", Status: OperationRunning},
+		Operation{Id: "op-1", Status: OperationFailed, Message: "operation failed", Errors: []FieldError{{Field: "name", Message: "no capacity"}}},
+	)
+
+	client := NewClient(server.URL, server.Client())
+	client.Wait = testWaitOptions
+
+	_, err := client.WaitForOperation(context.Background(), "/operations/op-1")
+
+	var opErr *OperationError
+	if !errors.As(err, &opErr) {
+		t.Fatalf("expected *OperationError, got %T: %v", err, err)
+	}
+
+	if len(opErr.Operation.Errors) != 1 || opErr.Operation.Errors[0].Field != "name" {
+		t.Errorf("unexpected operation errors: %#v", opErr.Operation.Errors)
+	}
+}
+
+func TestWaitForOperation_Deadline(t *testing.T) {
+	server, _ := testOperationServer(t, Operation{Id: "op-1", Status: OperationRunning, Progress: 20})
+
+	client := NewClient(server.URL, server.Client())
+	client.Wait = testWaitOptions
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := client.WaitForOperation(ctx, "/operations/op-1")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestWaitForOperation_UnexpectedStatus(t *testing.T) {
+	server, _ := testOperationServer(t, Operation{Id: "op-1", Status: "exploded"})
+
+	client := NewClient(server.URL, server.Client())
+	client.Wait = testWaitOptions
+
+	if _, err := client.WaitForOperation(context.Background(), "/operations/op-1"); err == nil {
+		t.Fatal("expected error for unknown status")
+	}
+}
+
+func TestCreatedResourceID(t *testing.T) {
+	op := &Operation{Id: "op-1", Status: OperationPending, ResourceId: "vm-1"}
+
+	if got := createdResourceID(op, fmt.Errorf("waiting for operation op-1: %w", context.DeadlineExceeded)); got != "vm-1" {
+		t.Errorf("expected vm-1 after a timeout, got %q", got)
+	}
+
+	if got := createdResourceID(op, &OperationError{Operation: *op}); got != "" {
+		t.Errorf("expected no id after the operation failed, got %q", got)
+	}
+}
+
+func TestWithJitter(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		got := withJitter(time.Second, 0.2)
+		if got < 800*time.Millisecond || got > 1200*time.Millisecond {
+			t.Fatalf("jittered delay %s out of range", got)
+		}
+	}
+
+	if got := withJitter(time.Second, 0); got != time.Second {
+		t.Errorf("expected no jitter, got %s", got)
+	}
+}
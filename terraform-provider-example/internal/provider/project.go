@@ -0,0 +1,162 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Placeholders of the endpoint template, replaced by the region of the
+// provider and the project of each request.
+const (
+	regionPlaceholder  = "{region}"
+	projectPlaceholder = "{project}"
+)
+
+// projectHeader carries the project of a request. terraform-service keeps
+// the objects of every project apart.
+const projectHeader = "X-Example-Project"
+
+type projectContextKey struct{}
+
+// withProject returns a context whose requests are sent to project instead
+// of the provider project.
+func withProject(ctx context.Context, project string) context.Context {
+	return context.WithValue(ctx, projectContextKey{}, project)
+}
+
+// projectFromContext returns the project requests made with ctx belong to.
+func (c *Client) projectFromContext(ctx context.Context) string {
+	if project, ok := ctx.Value(projectContextKey{}).(string); ok {
+		return project
+	}
+
+	return c.Project
+}
+
+// endpointFor expands the endpoint template for project.
+func (c *Client) endpointFor(project string) string {
+	return strings.NewReplacer(
+		regionPlaceholder, url.PathEscape(c.Region),
+		projectPlaceholder, url.PathEscape(project),
+	).Replace(c.Endpoint)
+}
+
+// QualifyID returns the ID of a resource in state for the backend ID id.
+// Backend IDs are only unique within a region and project, so unless
+// neither is used, the ID is qualified as "region/project/id" and resources
+// of aliased providers never collide. ImportState accepts these IDs.
+func (c *Client) QualifyID(project, id string) string {
+	if c.Region == "" && project == "" {
+		return id
+	}
+
+	return c.Region + "/" + project + "/" + id
+}
+
+// parseID splits a resource ID into project and backend ID. Unqualified IDs
+// belong to project, qualified IDs must belong to the region of the
+// provider.
+func (c *Client) parseID(id, project string) (string, string, error) {
+	parts := strings.Split(id, "/")
+
+	switch len(parts) {
+	case 1:
+		return project, parts[0], nil
+	case 2:
+		return parts[0], parts[1], nil
+	case 3:
+		if parts[0] != c.Region {
+			return "", "", fmt.Errorf("%q belongs to region %q, but the provider is configured for region %q. Use a provider configured for that region", id, parts[0], c.Region)
+		}
+
+		return parts[1], parts[2], nil
+	}
+
+	return "", "", fmt.Errorf("expected an ID of the form \"id\", \"project/id\" or \"region/project/id\", got %q", id)
+}
+
+// scope returns the context and backend ID of requests about the resource
+// with the ID id in state.
+func (c *Client) scope(ctx context.Context, id types.String) (context.Context, string, error) {
+	project, backendID, err := c.parseID(id.ValueString(), "")
+	if err != nil {
+		return ctx, "", err
+	}
+
+	return withProject(ctx, project), backendID, nil
+}
+
+// projectAttribute is the schema of the project attribute shared by all
+// resources.
+func projectAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		MarkdownDescription: "Project the resource belongs to. Defaults to the provider `project`. Changing it replaces the resource.",
+		Optional:            true,
+		Computed:            true,
+	}
+}
+
+// modifyPlanProject plans project as the provider project unless the
+// resource sets it, and replaces resources whose project changes. Resources
+// call it from ModifyPlan.
+func (c *Client) modifyPlanProject(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to plan when the resource is destroyed
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var project types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("project"), &project)...)
+	if resp.Diagnostics.HasError() || project.IsUnknown() {
+		return
+	}
+
+	if project.IsNull() && c != nil && c.Project != "" {
+		project = types.StringValue(c.Project)
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("project"), project)...)
+
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	var prior types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("project"), &prior)...)
+
+	if prior.ValueString() != project.ValueString() {
+		resp.RequiresReplace = append(resp.RequiresReplace, path.Root("project"))
+	}
+}
+
+// importStateProject imports a resource by its ID, in the form "id" for a
+// resource of the provider project, "project/id" or "region/project/id".
+func (c *Client) importStateProject(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	project, id, err := c.parseID(req.ID, c.Project)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Import ID", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), types.StringValue(c.QualifyID(project, id)))...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("project"), optionalString(project))...)
+}
+
+// optionalString returns s as a string value, null if s is empty.
+func optionalString(s string) types.String {
+	if s == "" {
+		return types.StringNull()
+	}
+
+	return types.StringValue(s)
+}
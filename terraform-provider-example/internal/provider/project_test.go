@@ -0,0 +1,181 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"terraform-service/server"
+)
+
+func TestClientParseID(t *testing.T) {
+	c := &Client{Region: "east", Project: "p1"}
+
+	testCases := map[string]struct {
+		id              string
+		expectedProject string
+		expectedID      string
+		expectErr       string
+	}{
+		"unqualified": {
+			id:              "vm-1",
+			expectedProject: "default",
+			expectedID:      "vm-1",
+		},
+		"project": {
+			id:              "p2/vm-1",
+			expectedProject: "p2",
+			expectedID:      "vm-1",
+		},
+		"qualified": {
+			id:              "east/p2/vm-1",
+			expectedProject: "p2",
+			expectedID:      "vm-1",
+		},
+		"other-region": {
+			id:        "west/p2/vm-1",
+			expectErr: `belongs to region "west"`,
+		},
+		"too-many-parts": {
+			id:        "east/p2/vm/1",
+			expectErr: "expected an ID",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			project, id, err := c.parseID(tc.id, "default")
+
+			if tc.expectErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectErr) {
+					t.Fatalf("expected error containing %q, got %v", tc.expectErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if project != tc.expectedProject || id != tc.expectedID {
+				t.Errorf("expected %s %s, got %s %s", tc.expectedProject, tc.expectedID, project, id)
+			}
+		})
+	}
+}
+
+func TestClientQualifyID(t *testing.T) {
+	if got := (&Client{}).QualifyID("", "vm-1"); got != "vm-1" {
+		t.Errorf("expected IDs to stay unqualified without region and project, got %s", got)
+	}
+
+	if got := (&Client{Region: "east"}).QualifyID("p1", "vm-1"); got != "east/p1/vm-1" {
+		t.Errorf("expected qualified ID, got %s", got)
+	}
+}
+
+func TestProjectHeader(t *testing.T) {
+	if projectHeader != server.ProjectHeader {
+		t.Errorf("expected the project header of terraform-service %q, got %q", server.ProjectHeader, projectHeader)
+	}
+}
+
+// newTestRegionServer serves a single backend under /east and /west, so an
+// endpoint template with {region} in the path reaches it for both regions.
+func newTestRegionServer(t *testing.T) string {
+	t.Helper()
+
+	h := server.New(server.Options{})
+
+	mux := http.NewServeMux()
+	mux.Handle("/east/", http.StripPrefix("/east", h))
+	mux.Handle("/west/", http.StripPrefix("/west", h))
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	return srv.URL + "/{region}"
+}
+
+func TestProtocolProject(t *testing.T) {
+	endpoint := newTestRegionServer(t)
+
+	east := newProtocolHarness(t, map[string]interface{}{"endpoint": endpoint, "region": "east", "project": "p1"})
+	alias := newProtocolHarness(t, map[string]interface{}{"endpoint": endpoint, "region": "east", "project": "p2"})
+
+	// VM names are unique within a project, each provider has its own
+	// objects.
+	s1 := east.create("example_regex", map[string]interface{}{"name": "test01"})
+	s2 := alias.create("example_regex", map[string]interface{}{"name": "test01"})
+
+	id1, _ := s1["id"].(string)
+	id2, _ := s2["id"].(string)
+
+	if !strings.HasPrefix(id1, "east/p1/vm-") || !strings.HasPrefix(id2, "east/p2/vm-") {
+		t.Fatalf("expected qualified IDs, got %s and %s", id1, id2)
+	}
+
+	if s1["project"] != "p1" || s2["project"] != "p2" {
+		t.Errorf("expected project of the provider, got %v and %v", s1["project"], s2["project"])
+	}
+
+	// Resources are read from their own project, whichever provider reads
+	// them.
+	read := east.read("example_regex", s2)
+	requireNoErrors(t, "ReadResource", read.Diagnostics)
+
+	if got := east.decode("example_regex", read.NewState); got["id"] != id2 {
+		t.Errorf("expected %s to be read from project p2, got %v", id2, got)
+	}
+
+	// A resource set project overrides the provider project.
+	s3 := east.create("example_regex", map[string]interface{}{"name": "test01", "project": "p3"})
+	if id, _ := s3["id"].(string); !strings.HasPrefix(id, "east/p3/vm-") {
+		t.Errorf("expected ID in project p3, got %s", id)
+	}
+
+	// Changing the project replaces the resource.
+	plan := east.plan("example_regex", s3, map[string]interface{}{"name": "test01", "project": "p4"})
+	requireNoErrors(t, "PlanResourceChange", plan.Diagnostics)
+
+	if got := requiresReplace(plan); !reflect.DeepEqual(got, []string{"project"}) {
+		t.Errorf("expected project change to require replacement, got %v", got)
+	}
+
+	// Resources of another region are not read by mistake.
+	west := newProtocolHarness(t, map[string]interface{}{"endpoint": endpoint, "region": "west", "project": "p1"})
+
+	read = west.read("example_regex", s1)
+	requireError(t, "ReadResource", read.Diagnostics, `belongs to region "east"`)
+
+	// Unqualified import IDs belong to the provider project.
+	_, vmID, _ := strings.Cut(strings.TrimPrefix(id1, "east/"), "/")
+
+	imported := east.importState("example_regex", vmID)
+	requireNoErrors(t, "ImportResourceState", imported.Diagnostics)
+
+	if got := east.decode("example_regex", imported.ImportedResources[0].State); got["id"] != id1 || got["project"] != "p1" {
+		t.Errorf("expected import of %s, got %v", id1, got)
+	}
+
+	_, vmID, _ = strings.Cut(strings.TrimPrefix(id2, "east/"), "/")
+
+	imported = east.importState("example_regex", "p2/"+vmID)
+	requireNoErrors(t, "ImportResourceState", imported.Diagnostics)
+
+	if got := east.decode("example_regex", imported.ImportedResources[0].State); got["id"] != id2 || got["project"] != "p2" {
+		t.Errorf("expected import of %s, got %v", id2, got)
+	}
+
+	east.destroy("example_regex", s1)
+	east.destroy("example_regex", s2)
+	east.destroy("example_regex", s3)
+
+	// The endpoint needs a region to be expanded.
+	requireError(t, "ConfigureProvider", east.configureError(map[string]interface{}{"endpoint": endpoint}), "Missing Region")
+}
@@ -0,0 +1,1249 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// protocolHarness drives the provider through its protocol version 6 server,
+// the same way Terraform does, but without the Terraform CLI. Values are
+// passed in and returned as Go maps, see toTerraformValue and
+// fromTerraformValue for the mapping.
+type protocolHarness struct {
+	t      *testing.T
+	ctx    context.Context
+	server tfprotov6.ProviderServer
+	schema *tfprotov6.GetProviderSchemaResponse
+}
+
+// protoUnknown is the type of unknownValue.
+type protoUnknown struct{}
+
+// unknownValue marks a value that is not known yet, e.g. a reference to an
+// attribute of a resource that has not been created.
+var unknownValue = protoUnknown{}
+
+// newProtocolHarness starts a provider server and configures it with
+// providerConfig. A nil providerConfig is an empty provider block.
+func newProtocolHarness(t *testing.T, providerConfig map[string]interface{}) *protocolHarness {
+	t.Helper()
+
+	return newProtocolHarnessWithCapabilities(t, providerConfig, nil)
+}
+
+// newProtocolHarnessWithCapabilities is newProtocolHarness for a Terraform
+// announcing capabilities, such as support for deferred actions.
+func newProtocolHarnessWithCapabilities(t *testing.T, providerConfig map[string]interface{}, capabilities *tfprotov6.ConfigureProviderClientCapabilities) *protocolHarness {
+	t.Helper()
+
+	return newProviderProtocolHarness(t, New("test")(), providerConfig, capabilities)
+}
+
+// newProviderProtocolHarness is newProtocolHarnessWithCapabilities for
+// another provider than the one of the package, e.g. one with resources
+// only defined by tests.
+func newProviderProtocolHarness(t *testing.T, p provider.Provider, providerConfig map[string]interface{}, capabilities *tfprotov6.ConfigureProviderClientCapabilities) *protocolHarness {
+	t.Helper()
+
+	if providerConfig == nil {
+		providerConfig = map[string]interface{}{}
+	}
+
+	server, err := providerserver.NewProtocol6WithError(p)()
+	if err != nil {
+		t.Fatalf("unable to create provider server: %s", err)
+	}
+
+	h := &protocolHarness{
+		t:      t,
+		ctx:    context.Background(),
+		server: server,
+	}
+
+	h.schema, err = server.GetProviderSchema(h.ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("GetProviderSchema: %s", err)
+	}
+	requireNoErrors(t, "GetProviderSchema", h.schema.Diagnostics)
+
+	resp, err := server.ConfigureProvider(h.ctx, &tfprotov6.ConfigureProviderRequest{
+		TerraformVersion:   "1.8.0",
+		Config:             h.dynamicValue(h.schema.Provider.ValueType(), providerConfig),
+		ClientCapabilities: capabilities,
+	})
+	if err != nil {
+		t.Fatalf("ConfigureProvider: %s", err)
+	}
+	requireNoErrors(t, "ConfigureProvider", resp.Diagnostics)
+
+	return h
+}
+
+// configureError configures another instance of the provider with
+// providerConfig and returns its diagnostics, for tests of configurations
+// newProtocolHarness would reject.
+func (h *protocolHarness) configureError(providerConfig map[string]interface{}) []*tfprotov6.Diagnostic {
+	h.t.Helper()
+
+	server, err := providerserver.NewProtocol6WithError(New("test")())()
+	if err != nil {
+		h.t.Fatalf("unable to create provider server: %s", err)
+	}
+
+	resp, err := server.ConfigureProvider(h.ctx, &tfprotov6.ConfigureProviderRequest{
+		TerraformVersion: "1.8.0",
+		Config:           h.dynamicValue(h.schema.Provider.ValueType(), providerConfig),
+	})
+	if err != nil {
+		h.t.Fatalf("ConfigureProvider: %s", err)
+	}
+
+	return resp.Diagnostics
+}
+
+// resourceSchema returns the schema of typeName, failing the test if the
+// provider does not implement it.
+func (h *protocolHarness) resourceSchema(typeName string) *tfprotov6.Schema {
+	h.t.Helper()
+
+	s, ok := h.schema.ResourceSchemas[typeName]
+	if !ok {
+		h.t.Fatalf("provider has no resource %s", typeName)
+	}
+
+	return s
+}
+
+func (h *protocolHarness) dynamicValue(typ tftypes.Type, v map[string]interface{}) *tfprotov6.DynamicValue {
+	h.t.Helper()
+
+	var value tftypes.Value
+	if v == nil {
+		value = tftypes.NewValue(typ, nil)
+	} else {
+		var err error
+		value, err = toTerraformValue(typ, v)
+		if err != nil {
+			h.t.Fatalf("unable to convert %v: %s", v, err)
+		}
+	}
+
+	dv, err := tfprotov6.NewDynamicValue(typ, value)
+	if err != nil {
+		h.t.Fatalf("unable to create dynamic value: %s", err)
+	}
+
+	return &dv
+}
+
+// value unmarshals a DynamicValue of typeName.
+func (h *protocolHarness) value(typeName string, dv *tfprotov6.DynamicValue) tftypes.Value {
+	h.t.Helper()
+
+	value, err := dv.Unmarshal(h.resourceSchema(typeName).ValueType())
+	if err != nil {
+		h.t.Fatalf("unable to decode %s value: %s", typeName, err)
+	}
+
+	return value
+}
+
+// decode converts a DynamicValue of typeName back into a Go map. A null
+// object, such as the new state of a destroyed resource, decodes to nil.
+func (h *protocolHarness) decode(typeName string, dv *tfprotov6.DynamicValue) map[string]interface{} {
+	h.t.Helper()
+
+	if dv == nil {
+		return nil
+	}
+
+	m, _ := fromTerraformValue(h.value(typeName, dv)).(map[string]interface{})
+
+	return m
+}
+
+func (h *protocolHarness) validate(typeName string, config map[string]interface{}) []*tfprotov6.Diagnostic {
+	h.t.Helper()
+
+	resp, err := h.server.ValidateResourceConfig(h.ctx, &tfprotov6.ValidateResourceConfigRequest{
+		TypeName: typeName,
+		Config:   h.dynamicValue(h.resourceSchema(typeName).ValueType(), config),
+	})
+	if err != nil {
+		h.t.Fatalf("ValidateResourceConfig: %s", err)
+	}
+
+	return resp.Diagnostics
+}
+
+// plan plans the change from prior to config. A nil prior plans a create, a
+// nil config plans a destroy.
+func (h *protocolHarness) plan(typeName string, prior, config map[string]interface{}) *tfprotov6.PlanResourceChangeResponse {
+	h.t.Helper()
+
+	s := h.resourceSchema(typeName)
+	typ := s.ValueType()
+
+	resp, err := h.server.PlanResourceChange(h.ctx, &tfprotov6.PlanResourceChangeRequest{
+		TypeName:         typeName,
+		PriorState:       h.dynamicValue(typ, prior),
+		ProposedNewState: h.dynamicValue(typ, proposedNewState(s, prior, config)),
+		Config:           h.dynamicValue(typ, config),
+	})
+	if err != nil {
+		h.t.Fatalf("PlanResourceChange: %s", err)
+	}
+
+	return resp
+}
+
+func (h *protocolHarness) apply(typeName string, prior, config map[string]interface{}, plan *tfprotov6.PlanResourceChangeResponse) *tfprotov6.ApplyResourceChangeResponse {
+	h.t.Helper()
+
+	typ := h.resourceSchema(typeName).ValueType()
+
+	resp, err := h.server.ApplyResourceChange(h.ctx, &tfprotov6.ApplyResourceChangeRequest{
+		TypeName:       typeName,
+		PriorState:     h.dynamicValue(typ, prior),
+		PlannedState:   plan.PlannedState,
+		Config:         h.dynamicValue(typ, config),
+		PlannedPrivate: plan.PlannedPrivate,
+	})
+	if err != nil {
+		h.t.Fatalf("ApplyResourceChange: %s", err)
+	}
+
+	return resp
+}
+
+func (h *protocolHarness) read(typeName string, state map[string]interface{}) *tfprotov6.ReadResourceResponse {
+	h.t.Helper()
+
+	resp, err := h.server.ReadResource(h.ctx, &tfprotov6.ReadResourceRequest{
+		TypeName:     typeName,
+		CurrentState: h.dynamicValue(h.resourceSchema(typeName).ValueType(), state),
+	})
+	if err != nil {
+		h.t.Fatalf("ReadResource: %s", err)
+	}
+
+	return resp
+}
+
+func (h *protocolHarness) importState(typeName, id string) *tfprotov6.ImportResourceStateResponse {
+	h.t.Helper()
+
+	resp, err := h.server.ImportResourceState(h.ctx, &tfprotov6.ImportResourceStateRequest{
+		TypeName: typeName,
+		ID:       id,
+	})
+	if err != nil {
+		h.t.Fatalf("ImportResourceState: %s", err)
+	}
+
+	return resp
+}
+
+// importIdentity is importState for an import block with identity instead
+// of id.
+func (h *protocolHarness) importIdentity(typeName string, identity map[string]interface{}) *tfprotov6.ImportResourceStateResponse {
+	h.t.Helper()
+
+	resp, err := h.server.ImportResourceState(h.ctx, &tfprotov6.ImportResourceStateRequest{
+		TypeName: typeName,
+		Identity: &tfprotov6.ResourceIdentityData{
+			IdentityData: h.dynamicValue(h.identityType(typeName), identity),
+		},
+	})
+	if err != nil {
+		h.t.Fatalf("ImportResourceState: %s", err)
+	}
+
+	return resp
+}
+
+// identityType returns the type of the identity of typeName.
+func (h *protocolHarness) identityType(typeName string) tftypes.Type {
+	h.t.Helper()
+
+	resp, err := h.server.GetResourceIdentitySchemas(h.ctx, &tfprotov6.GetResourceIdentitySchemasRequest{})
+	if err != nil {
+		h.t.Fatalf("GetResourceIdentitySchemas: %s", err)
+	}
+
+	s, ok := resp.IdentitySchemas[typeName]
+	if !ok {
+		h.t.Fatalf("resource %s has no identity", typeName)
+	}
+
+	return s.ValueType()
+}
+
+// listResult is a result of list, with its identity and resource decoded
+// like decode does.
+type listResult struct {
+	displayName string
+	identity    map[string]interface{}
+	resource    map[string]interface{}
+}
+
+// list lists the objects of typeName like a list block of terraform query
+// does, with their resources if includeResource is set. It returns the
+// results and the diagnostics of all of them.
+func (h *protocolHarness) list(typeName string, config map[string]interface{}, includeResource bool) ([]listResult, []*tfprotov6.Diagnostic) {
+	h.t.Helper()
+
+	s, ok := h.schema.ListResourceSchemas[typeName]
+	if !ok {
+		h.t.Fatalf("provider has no list resource %s", typeName)
+	}
+
+	server, ok := h.server.(tfprotov6.ProviderServerWithListResource)
+	if !ok {
+		h.t.Fatalf("provider server does not implement list resources")
+	}
+
+	stream, err := server.ListResource(h.ctx, &tfprotov6.ListResourceRequest{
+		TypeName:        typeName,
+		Config:          h.dynamicValue(s.ValueType(), config),
+		IncludeResource: includeResource,
+	})
+	if err != nil {
+		h.t.Fatalf("ListResource: %s", err)
+	}
+
+	identityType := h.identityType(typeName)
+
+	var results []listResult
+	var diags []*tfprotov6.Diagnostic
+	for r := range stream.Results {
+		diags = append(diags, r.Diagnostics...)
+		if r.Identity == nil {
+			continue
+		}
+
+		identity, err := r.Identity.IdentityData.Unmarshal(identityType)
+		if err != nil {
+			h.t.Fatalf("unable to decode %s identity: %s", typeName, err)
+		}
+
+		result := listResult{displayName: r.DisplayName}
+		result.identity, _ = fromTerraformValue(identity).(map[string]interface{})
+		if r.Resource != nil {
+			result.resource = h.decode(typeName, r.Resource)
+		}
+
+		results = append(results, result)
+	}
+
+	return results, diags
+}
+
+// invoke validates, plans and invokes the action typeName like terraform
+// apply -invoke does. It returns the progress messages the action sent and
+// the diagnostics of the first step that has errors, or of the invocation.
+func (h *protocolHarness) invoke(typeName string, config map[string]interface{}) ([]string, []*tfprotov6.Diagnostic) {
+	h.t.Helper()
+
+	s, ok := h.schema.ActionSchemas[typeName]
+	if !ok {
+		h.t.Fatalf("provider has no action %s", typeName)
+	}
+
+	server, ok := h.server.(tfprotov6.ProviderServerWithActions)
+	if !ok {
+		h.t.Fatalf("provider server does not implement actions")
+	}
+
+	dv := h.dynamicValue(s.Schema.ValueType(), config)
+
+	validate, err := server.ValidateActionConfig(h.ctx, &tfprotov6.ValidateActionConfigRequest{
+		ActionType: typeName,
+		Config:     dv,
+	})
+	if err != nil {
+		h.t.Fatalf("ValidateActionConfig: %s", err)
+	}
+	if hasErrors(validate.Diagnostics) {
+		return nil, validate.Diagnostics
+	}
+
+	plan, err := server.PlanAction(h.ctx, &tfprotov6.PlanActionRequest{
+		ActionType: typeName,
+		Config:     dv,
+	})
+	if err != nil {
+		h.t.Fatalf("PlanAction: %s", err)
+	}
+	if hasErrors(plan.Diagnostics) {
+		return nil, plan.Diagnostics
+	}
+
+	stream, err := server.InvokeAction(h.ctx, &tfprotov6.InvokeActionRequest{
+		ActionType: typeName,
+		Config:     dv,
+	})
+	if err != nil {
+		h.t.Fatalf("InvokeAction: %s", err)
+	}
+
+	var progress []string
+	var diags []*tfprotov6.Diagnostic
+	for event := range stream.Events {
+		switch e := event.Type.(type) {
+		case tfprotov6.ProgressInvokeActionEventType:
+			progress = append(progress, e.Message)
+		case tfprotov6.CompletedInvokeActionEventType:
+			diags = append(diags, e.Diagnostics...)
+		}
+	}
+
+	return progress, diags
+}
+
+// change runs validate, plan and apply like terraform apply does and returns
+// the new state. It fails the test on any error diagnostic and when the
+// applied state contradicts a known planned value, which Terraform reports
+// as "Provider produced inconsistent result".
+func (h *protocolHarness) change(typeName string, prior, config map[string]interface{}) map[string]interface{} {
+	h.t.Helper()
+
+	if config != nil {
+		requireNoErrors(h.t, "ValidateResourceConfig", h.validate(typeName, config))
+	}
+
+	plan := h.plan(typeName, prior, config)
+	requireNoErrors(h.t, "PlanResourceChange", plan.Diagnostics)
+
+	resp := h.apply(typeName, prior, config, plan)
+	requireNoErrors(h.t, "ApplyResourceChange", resp.Diagnostics)
+
+	if !plannedValuesMatch(h.value(typeName, plan.PlannedState), h.value(typeName, resp.NewState)) {
+		h.t.Fatalf("applied state does not match plan\nplanned: %v\napplied: %v",
+			h.decode(typeName, plan.PlannedState), h.decode(typeName, resp.NewState))
+	}
+
+	return h.decode(typeName, resp.NewState)
+}
+
+// create is change from no prior state.
+func (h *protocolHarness) create(typeName string, config map[string]interface{}) map[string]interface{} {
+	h.t.Helper()
+
+	return h.change(typeName, nil, config)
+}
+
+// destroy is change to no configuration.
+func (h *protocolHarness) destroy(typeName string, prior map[string]interface{}) {
+	h.t.Helper()
+
+	if state := h.change(typeName, prior, nil); state != nil {
+		h.t.Fatalf("expected no state after destroy, got %v", state)
+	}
+}
+
+// proposedNewState approximates the proposed new state Terraform computes:
+// the configuration, with computed attributes that are not configured
+// carried over from the prior state. Like Terraform, it recurses into nested
+// blocks and nested attributes, pairing their objects with prior ones: list
+// elements by index, map elements by key and set elements by their
+// configured attributes.
+func proposedNewState(s *tfprotov6.Schema, prior, config map[string]interface{}) map[string]interface{} {
+	if config == nil {
+		return nil
+	}
+
+	return proposedNewObject(s.Block.Attributes, s.Block.BlockTypes, prior, config)
+}
+
+// proposedNewObject is proposedNewState for an object with the given
+// attributes and nested blocks.
+func proposedNewObject(attributes []*tfprotov6.SchemaAttribute, blocks []*tfprotov6.SchemaNestedBlock, prior, config map[string]interface{}) map[string]interface{} {
+	proposed := make(map[string]interface{}, len(config))
+	for k, v := range config {
+		proposed[k] = v
+	}
+
+	if prior == nil {
+		return proposed
+	}
+
+	for _, a := range attributes {
+		switch {
+		case a.Computed && config[a.Name] == nil:
+			proposed[a.Name] = prior[a.Name]
+		case a.NestedType != nil:
+			nested := a.NestedType.Attributes
+
+			proposed[a.Name] = proposedNewNested(objectNesting(a.NestedType.Nesting), nested, prior[a.Name], config[a.Name],
+				func(prior, config map[string]interface{}) map[string]interface{} {
+					return proposedNewObject(nested, nil, prior, config)
+				})
+		}
+	}
+
+	for _, b := range blocks {
+		block := b.Block
+
+		proposed[b.TypeName] = proposedNewNested(b.Nesting, block.Attributes, prior[b.TypeName], config[b.TypeName],
+			func(prior, config map[string]interface{}) map[string]interface{} {
+				return proposedNewObject(block.Attributes, block.BlockTypes, prior, config)
+			})
+	}
+
+	return proposed
+}
+
+// objectNesting returns the block nesting mode equivalent to the nesting
+// mode of a nested attribute.
+func objectNesting(nesting tfprotov6.SchemaObjectNestingMode) tfprotov6.SchemaNestedBlockNestingMode {
+	switch nesting {
+	case tfprotov6.SchemaObjectNestingModeSingle:
+		return tfprotov6.SchemaNestedBlockNestingModeSingle
+	case tfprotov6.SchemaObjectNestingModeList:
+		return tfprotov6.SchemaNestedBlockNestingModeList
+	case tfprotov6.SchemaObjectNestingModeSet:
+		return tfprotov6.SchemaNestedBlockNestingModeSet
+	case tfprotov6.SchemaObjectNestingModeMap:
+		return tfprotov6.SchemaNestedBlockNestingModeMap
+	}
+
+	return tfprotov6.SchemaNestedBlockNestingModeInvalid
+}
+
+// proposedNewNested proposes the value config of a nested block or nested
+// attribute, whose objects have the given attributes, by pairing its objects
+// with those of prior and proposing each pair with propose. Objects without
+// a prior object are proposed as configured.
+func proposedNewNested(
+	nesting tfprotov6.SchemaNestedBlockNestingMode,
+	attributes []*tfprotov6.SchemaAttribute,
+	prior, config interface{},
+	propose func(prior, config map[string]interface{}) map[string]interface{},
+) interface{} {
+	switch nesting {
+	case tfprotov6.SchemaNestedBlockNestingModeSingle, tfprotov6.SchemaNestedBlockNestingModeGroup:
+		c, ok := config.(map[string]interface{})
+		if !ok {
+			return config
+		}
+
+		p, _ := prior.(map[string]interface{})
+
+		return propose(p, c)
+	case tfprotov6.SchemaNestedBlockNestingModeList:
+		c, ok := config.([]interface{})
+		if !ok {
+			return config
+		}
+
+		p, _ := prior.([]interface{})
+
+		proposed := make([]interface{}, 0, len(c))
+		for i, item := range c {
+			ce, ok := item.(map[string]interface{})
+			if !ok || i >= len(p) {
+				proposed = append(proposed, item)
+				continue
+			}
+
+			pe, _ := p[i].(map[string]interface{})
+			proposed = append(proposed, propose(pe, ce))
+		}
+
+		return proposed
+	case tfprotov6.SchemaNestedBlockNestingModeMap:
+		c, ok := config.(map[string]interface{})
+		if !ok {
+			return config
+		}
+
+		p, _ := prior.(map[string]interface{})
+
+		proposed := make(map[string]interface{}, len(c))
+		for k, item := range c {
+			ce, ok := item.(map[string]interface{})
+			pe, _ := p[k].(map[string]interface{})
+			if !ok || pe == nil {
+				proposed[k] = item
+				continue
+			}
+
+			proposed[k] = propose(pe, ce)
+		}
+
+		return proposed
+	case tfprotov6.SchemaNestedBlockNestingModeSet:
+		c, ok := config.([]interface{})
+		if !ok {
+			return config
+		}
+
+		p, _ := prior.([]interface{})
+		used := make([]bool, len(p))
+
+		proposed := make([]interface{}, 0, len(c))
+		for _, item := range c {
+			ce, ok := item.(map[string]interface{})
+			if !ok {
+				proposed = append(proposed, item)
+				continue
+			}
+
+			var match map[string]interface{}
+			for i, prior := range p {
+				pe, _ := prior.(map[string]interface{})
+				if !used[i] && pe != nil && sameConfiguredAttributes(attributes, pe, ce) {
+					used[i], match = true, pe
+					break
+				}
+			}
+
+			if match == nil {
+				proposed = append(proposed, item)
+				continue
+			}
+
+			proposed = append(proposed, propose(match, ce))
+		}
+
+		return proposed
+	}
+
+	return config
+}
+
+// sameConfiguredAttributes reports whether the set element config is the
+// configuration of the prior element prior: all attributes that are not
+// computed are equal, and so are the optional computed attributes config
+// sets.
+func sameConfiguredAttributes(attributes []*tfprotov6.SchemaAttribute, prior, config map[string]interface{}) bool {
+	for _, a := range attributes {
+		if a.Computed && (!a.Optional || config[a.Name] == nil) {
+			continue
+		}
+
+		if !reflect.DeepEqual(prior[a.Name], config[a.Name]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// toTerraformValue converts v into a value of typ. nil is null, unknownValue
+// is unknown, strings, bools and numbers map to primitives, []interface{} to
+// lists and sets and map[string]interface{} to maps and objects. Attributes
+// missing from an object are null.
+func toTerraformValue(typ tftypes.Type, v interface{}) (tftypes.Value, error) {
+	if v == nil {
+		return tftypes.NewValue(typ, nil), nil
+	}
+
+	if _, ok := v.(protoUnknown); ok {
+		return tftypes.NewValue(typ, tftypes.UnknownValue), nil
+	}
+
+	// Dynamic attributes take the type of their value, as HCL literals do.
+	if typ.Is(tftypes.DynamicPseudoType) {
+		typ = literalType(v)
+	}
+
+	switch typ := typ.(type) {
+	case tftypes.List, tftypes.Set:
+		items, ok := v.([]interface{})
+		if !ok {
+			return tftypes.Value{}, fmt.Errorf("expected []interface{} for %s, got %T", typ, v)
+		}
+
+		var et tftypes.Type
+		switch t := typ.(type) {
+		case tftypes.List:
+			et = t.ElementType
+		case tftypes.Set:
+			et = t.ElementType
+		}
+
+		values := make([]tftypes.Value, 0, len(items))
+		for i, item := range items {
+			value, err := toTerraformValue(et, item)
+			if err != nil {
+				return tftypes.Value{}, fmt.Errorf("[%d]: %w", i, err)
+			}
+			values = append(values, value)
+		}
+
+		return tftypes.NewValue(typ, values), nil
+	case tftypes.Map:
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return tftypes.Value{}, fmt.Errorf("expected map[string]interface{} for %s, got %T", typ, v)
+		}
+
+		values := make(map[string]tftypes.Value, len(m))
+		for k, item := range m {
+			value, err := toTerraformValue(typ.ElementType, item)
+			if err != nil {
+				return tftypes.Value{}, fmt.Errorf("[%q]: %w", k, err)
+			}
+			values[k] = value
+		}
+
+		return tftypes.NewValue(typ, values), nil
+	case tftypes.Tuple:
+		items, ok := v.([]interface{})
+		if !ok || len(items) != len(typ.ElementTypes) {
+			return tftypes.Value{}, fmt.Errorf("expected []interface{} of length %d for %s, got %T", len(typ.ElementTypes), typ, v)
+		}
+
+		values := make([]tftypes.Value, 0, len(items))
+		for i, item := range items {
+			value, err := toTerraformValue(typ.ElementTypes[i], item)
+			if err != nil {
+				return tftypes.Value{}, fmt.Errorf("[%d]: %w", i, err)
+			}
+			values = append(values, value)
+		}
+
+		return tftypes.NewValue(typ, values), nil
+	case tftypes.Object:
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return tftypes.Value{}, fmt.Errorf("expected map[string]interface{} for %s, got %T", typ, v)
+		}
+
+		for k := range m {
+			if _, ok := typ.AttributeTypes[k]; !ok {
+				return tftypes.Value{}, fmt.Errorf("unexpected attribute %q", k)
+			}
+		}
+
+		values := make(map[string]tftypes.Value, len(typ.AttributeTypes))
+		for k, at := range typ.AttributeTypes {
+			value, err := toTerraformValue(at, m[k])
+			if err != nil {
+				return tftypes.Value{}, fmt.Errorf(".%s: %w", k, err)
+			}
+			values[k] = value
+		}
+
+		return tftypes.NewValue(typ, values), nil
+	}
+
+	switch {
+	case typ.Equal(tftypes.String):
+		s, ok := v.(string)
+		if !ok {
+			return tftypes.Value{}, fmt.Errorf("expected string, got %T", v)
+		}
+		return tftypes.NewValue(typ, s), nil
+	case typ.Equal(tftypes.Bool):
+		b, ok := v.(bool)
+		if !ok {
+			return tftypes.Value{}, fmt.Errorf("expected bool, got %T", v)
+		}
+		return tftypes.NewValue(typ, b), nil
+	case typ.Equal(tftypes.Number):
+		switch n := v.(type) {
+		case int:
+			return tftypes.NewValue(typ, big.NewFloat(float64(n))), nil
+		case int64:
+			return tftypes.NewValue(typ, big.NewFloat(float64(n))), nil
+		case float64:
+			return tftypes.NewValue(typ, big.NewFloat(n)), nil
+		}
+		return tftypes.Value{}, fmt.Errorf("expected number, got %T", v)
+	}
+
+	return tftypes.Value{}, fmt.Errorf("unsupported type %s", typ)
+}
+
+// literalType returns the type Terraform infers for v written as a literal:
+// []interface{} is a tuple and map[string]interface{} an object.
+func literalType(v interface{}) tftypes.Type {
+	switch v := v.(type) {
+	case string:
+		return tftypes.String
+	case bool:
+		return tftypes.Bool
+	case int, int64, float64:
+		return tftypes.Number
+	case []interface{}:
+		elems := make([]tftypes.Type, 0, len(v))
+		for _, item := range v {
+			elems = append(elems, literalType(item))
+		}
+		return tftypes.Tuple{ElementTypes: elems}
+	case map[string]interface{}:
+		attrs := make(map[string]tftypes.Type, len(v))
+		for k, item := range v {
+			attrs[k] = literalType(item)
+		}
+		return tftypes.Object{AttributeTypes: attrs}
+	}
+
+	return tftypes.DynamicPseudoType
+}
+
+// fromTerraformValue is the inverse of toTerraformValue. Whole numbers are
+// returned as int64, other numbers as float64.
+func fromTerraformValue(v tftypes.Value) interface{} {
+	if !v.IsKnown() {
+		return unknownValue
+	}
+
+	if v.IsNull() {
+		return nil
+	}
+
+	switch v.Type().(type) {
+	case tftypes.List, tftypes.Set, tftypes.Tuple:
+		var values []tftypes.Value
+		_ = v.As(&values)
+
+		items := make([]interface{}, 0, len(values))
+		for _, value := range values {
+			items = append(items, fromTerraformValue(value))
+		}
+
+		return items
+	case tftypes.Map, tftypes.Object:
+		var values map[string]tftypes.Value
+		_ = v.As(&values)
+
+		m := make(map[string]interface{}, len(values))
+		for k, value := range values {
+			m[k] = fromTerraformValue(value)
+		}
+
+		return m
+	}
+
+	switch {
+	case v.Type().Equal(tftypes.String):
+		var s string
+		_ = v.As(&s)
+		return s
+	case v.Type().Equal(tftypes.Bool):
+		var b bool
+		_ = v.As(&b)
+		return b
+	case v.Type().Equal(tftypes.Number):
+		var f big.Float
+		_ = v.As(&f)
+		if f.IsInt() {
+			i, _ := f.Int64()
+			return i
+		}
+		n, _ := f.Float64()
+		return n
+	}
+
+	return v.String()
+}
+
+// knownValuesMatch reports whether every known value of planned is equal to
+// the corresponding value of applied. Collections are compared without
+// regard to order, which is how Terraform treats sets.
+func knownValuesMatch(planned, applied interface{}) bool {
+	if _, ok := planned.(protoUnknown); ok {
+		return true
+	}
+
+	switch p := planned.(type) {
+	case map[string]interface{}:
+		a, ok := applied.(map[string]interface{})
+		if !ok || len(a) != len(p) {
+			return false
+		}
+
+		for k, v := range p {
+			if !knownValuesMatch(v, a[k]) {
+				return false
+			}
+		}
+
+		return true
+	case []interface{}:
+		a, ok := applied.([]interface{})
+		if !ok || len(a) != len(p) {
+			return false
+		}
+
+		used := make([]bool, len(a))
+		for _, pv := range p {
+			found := false
+			for i, av := range a {
+				if !used[i] && knownValuesMatch(pv, av) {
+					used[i], found = true, true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return reflect.DeepEqual(planned, applied)
+}
+
+// plannedValuesMatch reports whether every known value of planned is equal
+// to the corresponding value of applied, like Terraform checks after apply.
+// Unlike knownValuesMatch, it keeps the order of lists and tuples.
+func plannedValuesMatch(planned, applied tftypes.Value) bool {
+	if !planned.IsKnown() {
+		return true
+	}
+
+	if !applied.IsKnown() || planned.IsNull() || applied.IsNull() {
+		return planned.Equal(applied)
+	}
+
+	switch planned.Type().(type) {
+	case tftypes.List, tftypes.Tuple:
+		var p, a []tftypes.Value
+		_ = planned.As(&p)
+		_ = applied.As(&a)
+
+		if len(p) != len(a) {
+			return false
+		}
+
+		for i := range p {
+			if !plannedValuesMatch(p[i], a[i]) {
+				return false
+			}
+		}
+
+		return true
+	case tftypes.Set:
+		var p, a []tftypes.Value
+		_ = planned.As(&p)
+		_ = applied.As(&a)
+
+		if len(p) != len(a) {
+			return false
+		}
+
+		used := make([]bool, len(a))
+		for _, pv := range p {
+			found := false
+			for i, av := range a {
+				if !used[i] && plannedValuesMatch(pv, av) {
+					used[i], found = true, true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+
+		return true
+	case tftypes.Map, tftypes.Object:
+		var p, a map[string]tftypes.Value
+		_ = planned.As(&p)
+		_ = applied.As(&a)
+
+		if len(p) != len(a) {
+			return false
+		}
+
+		for k, pv := range p {
+			av, ok := a[k]
+			if !ok || !plannedValuesMatch(pv, av) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return planned.Equal(applied)
+}
+
+// requireNoErrors fails the test if diags contains an error.
+func requireNoErrors(t *testing.T, operation string, diags []*tfprotov6.Diagnostic) {
+	t.Helper()
+
+	for _, d := range diags {
+		if d.Severity == tfprotov6.DiagnosticSeverityError {
+			t.Fatalf("%s: unexpected error: %s", operation, formatDiagnostics(diags))
+		}
+	}
+}
+
+// requireError fails the test unless diags contains an error whose summary or
+// detail contains substr.
+func requireError(t *testing.T, operation string, diags []*tfprotov6.Diagnostic, substr string) {
+	t.Helper()
+
+	for _, d := range diags {
+		if d.Severity == tfprotov6.DiagnosticSeverityError && (strings.Contains(d.Summary, substr) || strings.Contains(d.Detail, substr)) {
+			return
+		}
+	}
+
+	t.Fatalf("%s: expected error containing %q, got: %s", operation, substr, formatDiagnostics(diags))
+}
+
+// hasErrors reports whether diags contains an error.
+func hasErrors(diags []*tfprotov6.Diagnostic) bool {
+	for _, d := range diags {
+		if d.Severity == tfprotov6.DiagnosticSeverityError {
+			return true
+		}
+	}
+
+	return false
+}
+
+func formatDiagnostics(diags []*tfprotov6.Diagnostic) string {
+	if len(diags) == 0 {
+		return "no diagnostics"
+	}
+
+	lines := make([]string, 0, len(diags))
+	for _, d := range diags {
+		line := fmt.Sprintf("[%s] %s: %s", d.Severity, d.Summary, d.Detail)
+		if d.Attribute != nil {
+			line += fmt.Sprintf(" (at %s)", d.Attribute)
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// requiresReplace returns the top-level attribute names of the paths a plan
+// requires replacement for, sorted.
+func requiresReplace(plan *tfprotov6.PlanResourceChangeResponse) []string {
+	names := make([]string, 0, len(plan.RequiresReplace))
+	for _, p := range plan.RequiresReplace {
+		steps := p.Steps()
+		if len(steps) == 0 {
+			continue
+		}
+		if name, ok := steps[0].(tftypes.AttributeName); ok {
+			names = append(names, string(name))
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func TestProtocolHarness_GetProviderSchema(t *testing.T) {
+	h := newProtocolHarness(t, nil)
+
+	for _, typeName := range []string{
+		"example_computed",
+		"example_document",
+		"example_example",
+		"example_modifier",
+		"example_regex",
+		"example_server_networks",
+		"example_set_list",
+		"example_set_nested",
+	} {
+		if _, ok := h.schema.ResourceSchemas[typeName]; !ok {
+			t.Errorf("expected resource schema for %s", typeName)
+		}
+	}
+
+	if _, ok := h.schema.DataSourceSchemas["example_example"]; !ok {
+		t.Error("expected data source schema for example_example")
+	}
+}
+
+func TestProposedNewState(t *testing.T) {
+	nic := []*tfprotov6.SchemaAttribute{
+		{Name: "uuid", Type: tftypes.String, Required: true},
+		{Name: "fixed_ip", Type: tftypes.String, Optional: true, Computed: true},
+		{Name: "port", Type: tftypes.String, Computed: true},
+	}
+
+	s := &tfprotov6.Schema{
+		Block: &tfprotov6.SchemaBlock{
+			Attributes: []*tfprotov6.SchemaAttribute{
+				{Name: "id", Type: tftypes.String, Computed: true},
+				{Name: "name", Type: tftypes.String, Required: true},
+				{Name: "nics", NestedType: &tfprotov6.SchemaObject{Nesting: tfprotov6.SchemaObjectNestingModeSet, Attributes: nic}, Optional: true},
+			},
+			BlockTypes: []*tfprotov6.SchemaNestedBlock{
+				{TypeName: "network", Nesting: tfprotov6.SchemaNestedBlockNestingModeList, Block: &tfprotov6.SchemaBlock{Attributes: nic}},
+			},
+		},
+	}
+
+	prior := map[string]interface{}{
+		"id":   "vm-1",
+		"name": "test01",
+		"nics": []interface{}{
+			map[string]interface{}{"uuid": "net-1", "fixed_ip": "10.0.0.1", "port": "port-1"},
+			map[string]interface{}{"uuid": "net-2", "fixed_ip": "10.0.0.2", "port": "port-2"},
+		},
+		"network": []interface{}{
+			map[string]interface{}{"uuid": "net-1", "fixed_ip": "10.0.0.1", "port": "port-1"},
+			map[string]interface{}{"uuid": "net-2", "fixed_ip": "10.0.0.2", "port": "port-2"},
+		},
+	}
+
+	config := map[string]interface{}{
+		"name": "test02",
+		"nics": []interface{}{
+			map[string]interface{}{"uuid": "net-2"},
+			map[string]interface{}{"uuid": "net-3"},
+		},
+		"network": []interface{}{
+			map[string]interface{}{"uuid": "net-2"},
+			map[string]interface{}{"uuid": "net-1", "fixed_ip": "10.0.0.9"},
+			map[string]interface{}{"uuid": "net-3"},
+		},
+	}
+
+	// Set elements are paired by their configured attributes, list
+	// elements by index, whatever their uuid.
+	expected := map[string]interface{}{
+		"id":   "vm-1",
+		"name": "test02",
+		"nics": []interface{}{
+			map[string]interface{}{"uuid": "net-2", "fixed_ip": "10.0.0.2", "port": "port-2"},
+			map[string]interface{}{"uuid": "net-3"},
+		},
+		"network": []interface{}{
+			map[string]interface{}{"uuid": "net-2", "fixed_ip": "10.0.0.1", "port": "port-1"},
+			map[string]interface{}{"uuid": "net-1", "fixed_ip": "10.0.0.9", "port": "port-2"},
+			map[string]interface{}{"uuid": "net-3"},
+		},
+	}
+
+	if got := proposedNewState(s, prior, config); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	if got := proposedNewState(s, nil, config); !reflect.DeepEqual(got, config) {
+		t.Errorf("expected the configuration without prior state, got %v", got)
+	}
+}
+
+func TestPlannedValuesMatch(t *testing.T) {
+	list := tftypes.List{ElementType: tftypes.String}
+	set := tftypes.Set{ElementType: tftypes.String}
+
+	value := func(typ tftypes.Type, v interface{}) tftypes.Value {
+		value, err := toTerraformValue(typ, v)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		return value
+	}
+
+	testCases := map[string]struct {
+		planned, applied tftypes.Value
+		expected         bool
+	}{
+		"list": {
+			planned:  value(list, []interface{}{"a", unknownValue}),
+			applied:  value(list, []interface{}{"a", "b"}),
+			expected: true,
+		},
+		"list-reordered": {
+			planned: value(list, []interface{}{"a", "b"}),
+			applied: value(list, []interface{}{"b", "a"}),
+		},
+		"set-reordered": {
+			planned:  value(set, []interface{}{"a", "b"}),
+			applied:  value(set, []interface{}{"b", "a"}),
+			expected: true,
+		},
+		"unknown-list": {
+			planned:  value(list, unknownValue),
+			applied:  value(list, []interface{}{"a"}),
+			expected: true,
+		},
+		"null-list": {
+			planned: value(list, nil),
+			applied: value(list, []interface{}{}),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if got := plannedValuesMatch(tc.planned, tc.applied); got != tc.expected {
+				t.Errorf("expected %t, got %t", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestTerraformValueRoundTrip(t *testing.T) {
+	typ := tftypes.Object{
+		AttributeTypes: map[string]tftypes.Type{
+			"name":    tftypes.String,
+			"count":   tftypes.Number,
+			"ratio":   tftypes.Number,
+			"enabled": tftypes.Bool,
+			"tags":    tftypes.Map{ElementType: tftypes.String},
+			"items":   tftypes.List{ElementType: tftypes.String},
+			"nested": tftypes.Set{ElementType: tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+				"uuid": tftypes.String,
+			}}},
+			"missing": tftypes.String,
+		},
+	}
+
+	in := map[string]interface{}{
+		"name":    "test01",
+		"count":   3,
+		"ratio":   0.5,
+		"enabled": true,
+		"tags":    map[string]interface{}{"env": "dev"},
+		"items":   []interface{}{"a", unknownValue},
+		"nested":  []interface{}{map[string]interface{}{"uuid": "net-1"}},
+	}
+
+	value, err := toTerraformValue(typ, in)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := map[string]interface{}{
+		"name":    "test01",
+		"count":   int64(3),
+		"ratio":   0.5,
+		"enabled": true,
+		"tags":    map[string]interface{}{"env": "dev"},
+		"items":   []interface{}{"a", unknownValue},
+		"nested":  []interface{}{map[string]interface{}{"uuid": "net-1"}},
+		"missing": nil,
+	}
+
+	if got := fromTerraformValue(value); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	if _, err := toTerraformValue(typ, map[string]interface{}{"bogus": "x"}); err == nil {
+		t.Error("expected error for unknown attribute")
+	}
+
+	if _, err := toTerraformValue(typ, map[string]interface{}{"count": "3"}); err == nil {
+		t.Error("expected error for mismatched type")
+	}
+}
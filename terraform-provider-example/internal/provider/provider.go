@@ -5,19 +5,30 @@ package provider
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"os"
+	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/action"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 // Ensure ScaffoldingProvider satisfies various provider interfaces.
 var _ provider.Provider = &ScaffoldingProvider{}
 var _ provider.ProviderWithFunctions = &ScaffoldingProvider{}
+var _ provider.ProviderWithActions = &ScaffoldingProvider{}
+var _ provider.ProviderWithListResources = &ScaffoldingProvider{}
 
 // ScaffoldingProvider defines the provider implementation.
 type ScaffoldingProvider struct {
@@ -29,7 +40,24 @@ type ScaffoldingProvider struct {
 
 // ScaffoldingProviderModel describes the provider data model.
 type ScaffoldingProviderModel struct {
-	Endpoint types.String `tfsdk:"endpoint"`
+	Endpoint              types.String `tfsdk:"endpoint"`
+	Region                types.String `tfsdk:"region"`
+	Project               types.String `tfsdk:"project"`
+	MaxAttempts           types.Int64  `tfsdk:"max_attempts"`
+	MaxConcurrentRequests types.Int64  `tfsdk:"max_concurrent_requests"`
+	DefaultTags           types.Map    `tfsdk:"default_tags"`
+	Mock                  types.Bool   `tfsdk:"mock"`
+	ClientId              types.String `tfsdk:"client_id"`
+	ClientSecret          types.String `tfsdk:"client_secret"`
+	TokenURL              types.String `tfsdk:"token_url"`
+	Scopes                types.List   `tfsdk:"scopes"`
+	AuthMode              types.String `tfsdk:"auth_mode"`
+	AccessKey             types.String `tfsdk:"access_key"`
+	SecretKey             types.String `tfsdk:"secret_key"`
+	ClientCertPEM         types.String `tfsdk:"client_cert_pem"`
+	ClientKeyPEM          types.String `tfsdk:"client_key_pem"`
+	CACertPEM             types.String `tfsdk:"ca_cert_pem"`
+	TLSServerName         types.String `tfsdk:"tls_server_name"`
 }
 
 func (p *ScaffoldingProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -41,7 +69,94 @@ func (p *ScaffoldingProvider) Schema(ctx context.Context, req provider.SchemaReq
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
 			"endpoint": schema.StringAttribute{
-				MarkdownDescription: "Example provider attribute",
+				MarkdownDescription: "Base URL of terraform-service. Defaults to the `EXAMPLE_ENDPOINT` environment variable, or `" + defaultEndpoint + "` if that is unset. " +
+					"`{region}` and `{project}` are replaced by the region and the project of every request, e.g. `https://{region}.example.com`.",
+				Optional: true,
+			},
+			"region": schema.StringAttribute{
+				MarkdownDescription: "Region of the backend. Resource IDs are qualified with it, so that providers of different regions never share IDs. " +
+					"Defaults to the `EXAMPLE_REGION` environment variable.",
+				Optional: true,
+			},
+			"project": schema.StringAttribute{
+				MarkdownDescription: "Project resources are created in, unless they set `project`. Defaults to the `EXAMPLE_PROJECT` environment variable.",
+				Optional:            true,
+			},
+			"max_attempts": schema.Int64Attribute{
+				MarkdownDescription: "Maximum number of times a backend request is sent, including the first attempt. Failed requests are retried on 5xx responses, 429 responses and connection resets. Defaults to `4`.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"max_concurrent_requests": schema.Int64Attribute{
+				MarkdownDescription: "Maximum number of backend requests in flight at any time, shared by all resources and data sources. Unlimited when not set.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"default_tags": schema.MapAttribute{
+				MarkdownDescription: "Tags added to every resource managed by the provider. Tags set on a resource override default tags with the same key.",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"mock": schema.BoolAttribute{
+				MarkdownDescription: "Serve all requests from a fake terraform-service built into the provider instead of `endpoint`, for demos and local development. " +
+					"Objects are kept in `.terraform/example-mock-state.json`, or the file named by the `EXAMPLE_MOCK_STATE` environment variable. " +
+					"Defaults to the `EXAMPLE_MOCK` environment variable.",
+				Optional: true,
+			},
+			"client_id": schema.StringAttribute{
+				MarkdownDescription: "OAuth2 client ID. When set, requests are authenticated with an access token obtained with the client credentials grant. Defaults to the `EXAMPLE_CLIENT_ID` environment variable.",
+				Optional:            true,
+			},
+			"client_secret": schema.StringAttribute{
+				MarkdownDescription: "OAuth2 client secret. Defaults to the `EXAMPLE_CLIENT_SECRET` environment variable.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"token_url": schema.StringAttribute{
+				MarkdownDescription: "URL access tokens are requested from. Defaults to `" + defaultTokenPath + "` under `endpoint`, expanded for `region` and `project`.",
+				Optional:            true,
+			},
+			"scopes": schema.ListAttribute{
+				MarkdownDescription: "Scopes requested for the access token.",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"auth_mode": schema.StringAttribute{
+				MarkdownDescription: "How requests are authenticated: `none`, `oauth2` with `client_id` and `client_secret`, " +
+					"or `hmac` to sign every request with `access_key` and `secret_key`. Defaults to `oauth2` if a client ID is set, `none` otherwise.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(authModeNone, authModeOAuth2, authModeHMAC),
+				},
+			},
+			"access_key": schema.StringAttribute{
+				MarkdownDescription: "Access key of signed requests, when `auth_mode` is `hmac`. Defaults to the `EXAMPLE_ACCESS_KEY` environment variable.",
+				Optional:            true,
+			},
+			"secret_key": schema.StringAttribute{
+				MarkdownDescription: "Secret key requests are signed with, when `auth_mode` is `hmac`. Defaults to the `EXAMPLE_SECRET_KEY` environment variable.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"client_cert_pem": schema.StringAttribute{
+				MarkdownDescription: "PEM encoded certificate presented to backends that require client certificates. Requires `client_key_pem`.",
+				Optional:            true,
+			},
+			"client_key_pem": schema.StringAttribute{
+				MarkdownDescription: "PEM encoded private key of `client_cert_pem`.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"ca_cert_pem": schema.StringAttribute{
+				MarkdownDescription: "PEM encoded CA certificates the backend certificate is verified against, instead of the system roots.",
+				Optional:            true,
+			},
+			"tls_server_name": schema.StringAttribute{
+				MarkdownDescription: "Name the backend certificate is verified against, when it differs from the host of `endpoint`.",
 				Optional:            true,
 			},
 		},
@@ -51,19 +166,114 @@ func (p *ScaffoldingProvider) Schema(ctx context.Context, req provider.SchemaReq
 func (p *ScaffoldingProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
 	var data ScaffoldingProviderModel
 
+	if deferUnknownConfig(ctx, req, resp) {
+		return
+	}
+
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
 
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Configuration values are now available.
-	// if data.Endpoint.IsNull() { /* ... */ }
+	endpoint := os.Getenv("EXAMPLE_ENDPOINT")
+	if !data.Endpoint.IsNull() {
+		endpoint = data.Endpoint.ValueString()
+	}
+	if endpoint == "" {
+		endpoint = defaultEndpoint
+	}
+
+	region := stringFromEnv(data.Region, "EXAMPLE_REGION")
+	project := stringFromEnv(data.Project, "EXAMPLE_PROJECT")
 
-	// Example client configuration for data sources and resources
-	client := http.DefaultClient
+	if strings.Contains(endpoint, regionPlaceholder) && region == "" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("region"),
+			"Missing Region",
+			"The endpoint contains "+regionPlaceholder+" but no region is configured. Set region, or the EXAMPLE_REGION environment variable.",
+		)
+		return
+	}
+
+	maxAttempts := defaultMaxAttempts
+	if !data.MaxAttempts.IsNull() {
+		maxAttempts = int(data.MaxAttempts.ValueInt64())
+	}
+
+	mock := mockEnabled()
+	if !data.Mock.IsNull() {
+		mock = data.Mock.ValueBool()
+	}
+
+	auth, diags := newAuthOptions(ctx, data)
+
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tlsOpts := tlsOptions{
+		ClientCertPEM: data.ClientCertPEM.ValueString(),
+		ClientKeyPEM:  data.ClientKeyPEM.ValueString(),
+		CACertPEM:     data.CACertPEM.ValueString(),
+		ServerName:    data.TLSServerName.ValueString(),
+	}
+
+	var transport http.RoundTripper = http.DefaultTransport
+	if tlsOpts.isSet() {
+		tlsConfig, diags := tlsOpts.tlsConfig()
+
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		defaultTransport, ok := http.DefaultTransport.(*http.Transport)
+		if !ok {
+			resp.Diagnostics.AddError(
+				"Unexpected Default Transport Type",
+				fmt.Sprintf("Expected *http.Transport, got: %T. Please report this issue to the provider developers.", http.DefaultTransport),
+			)
+			return
+		}
+
+		base := defaultTransport.Clone()
+		base.TLSClientConfig = tlsConfig
+		transport = base
+	}
+
+	if mock {
+		mockTransport, diags := newMockTransport(auth)
+
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		endpoint = mockEndpoint
+		transport = mockTransport
+	}
+
+	// Client configuration for data sources and resources
+	httpClient := &http.Client{}
+	client := NewClient(endpoint, httpClient)
+	client.Region = region
+	client.Project = project
+	client.SetMaxConcurrentRequests(data.MaxConcurrentRequests.ValueInt64())
+	client.DefaultTags = data.DefaultTags
+
+	transport = newLoggingTransport(transport)
+
+	// Tokens are requested from the endpoint of the provider project, the
+	// endpoint template is not a URL.
+	transport = auth.transport(transport, client.endpointFor(project), maxAttempts)
+
+	httpClient.Transport = newRetryTransport(transport, maxAttempts)
 	resp.DataSourceData = client
 	resp.ResourceData = client
+	resp.ActionData = client
+	resp.ListResourceData = client
 }
 
 func (p *ScaffoldingProvider) Resources(_ context.Context) []func() resource.Resource {
@@ -74,6 +284,24 @@ func (p *ScaffoldingProvider) Resources(_ context.Context) []func() resource.Res
 		NewResourceSetNested,
 		NewResourceComputed,
 		NewResourceSetListList,
+		NewResourceServerNetworks,
+		NewResourceDocument,
+	}
+}
+
+func (p *ScaffoldingProvider) ListResources(_ context.Context) []func() list.ListResource {
+	return []func() list.ListResource{
+		NewListResourceRegex,
+		NewListResourceSetNested,
+	}
+}
+
+func (p *ScaffoldingProvider) Actions(_ context.Context) []func() action.Action {
+	return []func() action.Action{
+		NewActionVMReboot,
+		NewActionVMStop,
+		NewActionVMStart,
+		NewActionVMResize,
 	}
 }
 
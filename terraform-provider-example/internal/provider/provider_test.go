@@ -4,10 +4,15 @@
 package provider
 
 import (
+	"fmt"
+	"net/http/httptest"
+	"os"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+
+	"terraform-service/server"
 )
 
 // testAccProtoV6ProviderFactories are used to instantiate a provider during
@@ -16,6 +21,7 @@ import (
 // reattach.
 var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
 	"scaffolding": providerserver.NewProtocol6WithError(New("test")()),
+	"example":     providerserver.NewProtocol6WithError(New("test")()),
 }
 
 func testAccPreCheck(t *testing.T) {
@@ -23,3 +29,52 @@ func testAccPreCheck(t *testing.T) {
 	// about the appropriate environment variables being set are common to see in a pre-check
 	// function.
 }
+
+// newTestServer returns the backend of acceptance tests: EXAMPLE_ENDPOINT if
+// it is set, so that they run against a real terraform-service whose leaked
+// objects the sweepers clean up, and newLocalTestServer otherwise.
+func newTestServer(t *testing.T) string {
+	t.Helper()
+
+	if endpoint := os.Getenv("EXAMPLE_ENDPOINT"); endpoint != "" {
+		return endpoint
+	}
+
+	return newLocalTestServer(t)
+}
+
+// newLocalTestServer starts an in-process terraform-service for the duration
+// of the test and returns its URL. Operations of the server complete without
+// delay. Protocol tests use it even if EXAMPLE_ENDPOINT is set, they expect
+// an empty backend.
+func newLocalTestServer(t *testing.T) string {
+	t.Helper()
+
+	return newTestServerWithOptions(t, server.Options{})
+}
+
+// newTestServerWithOptions is newLocalTestServer with the server configured
+// by opts, for example to require authentication.
+func newTestServerWithOptions(t *testing.T, opts server.Options) string {
+	t.Helper()
+
+	srv := httptest.NewServer(server.New(opts))
+	t.Cleanup(srv.Close)
+
+	return srv.URL
+}
+
+// testAccProviderConfig returns the provider block for acceptance tests that
+// talk to the backend at endpoint, usually one started by newTestServer.
+// Objects are tagged for the sweepers.
+func testAccProviderConfig(endpoint string) string {
+	return fmt.Sprintf(`
+provider "example" {
+  endpoint = %[1]q
+
+  default_tags = {
+    %[2]q = "true"
+  }
+}
+`, endpoint, testAccNamePrefix)
+}
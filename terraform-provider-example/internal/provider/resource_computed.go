@@ -5,32 +5,25 @@ package provider
 
 import (
 	"context"
-	"fmt"
-	"github.com/hashicorp/terraform-plugin-framework/diag"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
-	"net/http"
 
-	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
-// Ensure provider defined types fully satisfy framework interfaces.
-var _ resource.Resource = &ResourceComputed{}
-var _ resource.ResourceWithImportState = &ResourceComputed{}
-
 func NewResourceComputed() resource.Resource {
-	return &ResourceComputed{}
+	return newCRUDResource[ResourceComputedModel]("computed", ResourceComputed{})
 }
 
 // ResourceComputed defines the resource implementation.
-type ResourceComputed struct {
-	client *http.Client
-}
+type ResourceComputed struct{}
+
+var _ crudOperations[ResourceComputedModel] = ResourceComputed{}
 
 // ResourceComputedModel describes the resource data model.
 type (
@@ -40,15 +33,13 @@ type (
 		ReplaceIfConfigured types.String `tfsdk:"replace_if_configured"`
 		UseStateForUnknown  types.String `tfsdk:"use_state_for_unknown"`
 		ListOptional        types.List   `tfsdk:"list_optional"`
+		Tags                types.Map    `tfsdk:"tags"`
+		TagsAll             types.Map    `tfsdk:"tags_all"`
 	}
 )
 
-func (r *ResourceComputed) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
-	resp.TypeName = req.ProviderTypeName + "_computed"
-}
-
-func (r *ResourceComputed) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
-	resp.Schema = schema.Schema{
+func (r ResourceComputed) schema(ctx context.Context) schema.Schema {
+	return schema.Schema{
 		// This description is used by the documentation generator and the language server.
 		MarkdownDescription: "Set Nested 2 Example resource",
 
@@ -90,137 +81,48 @@ func (r *ResourceComputed) Schema(ctx context.Context, req resource.SchemaReques
 					listplanmodifier.RequiresReplaceIfConfigured(),
 				},
 			},
+			"tags":     tagsAttribute(),
+			"tags_all": tagsAllAttribute(),
 		},
 	}
 }
 
-func (r *ResourceComputed) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
-	// Prevent panic if the provider has not been configured.
-	if req.ProviderData == nil {
-		return
+func (r ResourceComputed) create(ctx context.Context, client *Client, data *ResourceComputedModel) (string, error) {
+	if err := errorFromDiags(data.fnConvert(ctx)); err != nil {
+		return "", err
 	}
 
-	client, ok := req.ProviderData.(*http.Client)
-
-	if !ok {
-		resp.Diagnostics.AddError(
-			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *http.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
-		)
-
-		return
-	}
-
-	r.client = client
+	return "id-f91f202e-abe3-40b6-9d7d-f35fb3bf0471", nil
 }
 
-func (r *ResourceComputed) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
-	var data ResourceComputedModel
-
-	// Read Terraform plan data into the model
-	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
-	if resp.Diagnostics.HasError() {
-		return
+func (r ResourceComputed) read(ctx context.Context, client *Client, id string, data *ResourceComputedModel) error {
+	if err := errorFromDiags(data.fnConvert(ctx)); err != nil {
+		return err
 	}
 
-	data.Id = types.StringValue("id-f91f202e-abe3-40b6-9d7d-f35fb3bf0471")
-	diags := data.fnConvert(ctx)
-
-	resp.Diagnostics.Append(diags...)
-	if resp.Diagnostics.HasError() {
-		return
+	// A resource imported by id has no tags_all yet
+	if data.TagsAll.IsNull() {
+		data.TagsAll = client.mergeTags(data.Tags)
 	}
 
-	// Write logs using the tflog package
-	// Documentation: https://terraform.io/plugin/log
-	tflog.Trace(ctx, "created a resource")
-
-	// Save data into Terraform state
-	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
-}
-
-func (r *ResourceComputed) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
-	var data ResourceComputedModel
-
-	// Read Terraform prior state data into the model
-	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
-
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	diags := data.fnConvert(ctx)
-
-	resp.Diagnostics.Append(diags...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	// Save updated data into Terraform state
-	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
-}
-
-func (r *ResourceComputed) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var data ResourceComputedModel
-
-	// Read Terraform plan data into the model
-	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
-
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	diags := data.fnConvert(ctx)
-
-	resp.Diagnostics.Append(diags...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	// Save updated data into Terraform state
-	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	return nil
 }
 
-func (r *ResourceComputed) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	var data ResourceComputedModel
-
-	// Read Terraform prior state data into the model
-	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
-
-	if resp.Diagnostics.HasError() {
-		return
-	}
+func (r ResourceComputed) update(ctx context.Context, client *Client, id string, data *ResourceComputedModel) error {
+	return errorFromDiags(data.fnConvert(ctx))
 }
 
-func (r *ResourceComputed) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+func (r ResourceComputed) delete(ctx context.Context, client *Client, id string, data *ResourceComputedModel) error {
+	return nil
 }
 
+// fnConvert computes list_optional when it is not configured. Configured
+// elements are kept as they are: Terraform rejects an applied value that
+// differs from the configuration, such as elements with a suffix added by
+// the provider, as an inconsistent result.
 func (s *ResourceComputedModel) fnConvert(ctx context.Context) diag.Diagnostics {
-	listOptional := s.ListOptional
-
-	if !listOptional.IsNull() && !listOptional.IsUnknown() {
-		var list []string
-		diags := listOptional.ElementsAs(ctx, &list, false)
-
-		if diags.HasError() {
-			return diags
-		}
-
-		if len(list) > 0 {
-			for i, v := range list {
-				list[i] = v + "_replace"
-			}
-
-			valueFrom, diags2 := types.ListValueFrom(ctx, types.StringType, list)
-
-			diags.Append(diags2...)
-			if diags.HasError() {
-				return diags
-			}
-
-			s.ListOptional = valueFrom
-		}
+	if s.ListOptional.IsUnknown() {
+		s.ListOptional = types.ListValueMust(types.StringType, []attr.Value{})
 	}
 
 	return nil
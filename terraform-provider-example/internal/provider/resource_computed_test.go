@@ -0,0 +1,172 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestProtocolResourceComputed_Plan(t *testing.T) {
+	h := newProtocolHarness(t, nil)
+
+	prior := map[string]interface{}{
+		"id":                    "id-f91f202e-abe3-40b6-9d7d-f35fb3bf0471",
+		"replace":               "one",
+		"replace_if_configured": "one",
+		"use_state_for_unknown": "one",
+		"list_optional":         []interface{}{"a"},
+	}
+
+	testCases := map[string]struct {
+		config   map[string]interface{}
+		expected []string
+	}{
+		"no-change": {
+			config: map[string]interface{}{
+				"replace":               "one",
+				"replace_if_configured": "one",
+				"use_state_for_unknown": "one",
+			},
+			expected: []string{},
+		},
+		"replace": {
+			config: map[string]interface{}{
+				"replace":               "two",
+				"replace_if_configured": "one",
+				"use_state_for_unknown": "one",
+			},
+			expected: []string{"replace"},
+		},
+		"replace-if-configured-removed": {
+			config: map[string]interface{}{
+				"replace":               "one",
+				"use_state_for_unknown": "one",
+			},
+			expected: []string{},
+		},
+		"list-optional-configured": {
+			config: map[string]interface{}{
+				"replace":               "one",
+				"replace_if_configured": "one",
+				"use_state_for_unknown": "one",
+				"list_optional":         []interface{}{"b"},
+			},
+			expected: []string{"list_optional"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			plan := h.plan("example_computed", prior, tc.config)
+			requireNoErrors(t, "PlanResourceChange", plan.Diagnostics)
+
+			if got := requiresReplace(plan); !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("expected replacement for %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestProtocolResourceComputed_ListOptional(t *testing.T) {
+	h := newProtocolHarness(t, nil)
+
+	// Configured elements are applied as they are planned.
+	state := h.create("example_computed", map[string]interface{}{
+		"list_optional": []interface{}{"a", "b"},
+	})
+
+	if got := state["list_optional"]; !reflect.DeepEqual(got, []interface{}{"a", "b"}) {
+		t.Errorf("expected list_optional [a b], got %v", got)
+	}
+
+	// list_optional is computed as an empty list when it is not configured.
+	state = h.create("example_computed", map[string]interface{}{})
+
+	if got := state["list_optional"]; !reflect.DeepEqual(got, []interface{}{}) {
+		t.Errorf("expected empty list_optional, got %v", got)
+	}
+}
+
+func TestAccResourceComputed(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccResourceComputedConfig("one", "one", "[]"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("example_computed.test", plancheck.ResourceActionCreate),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("example_computed.test", tfjsonpath.New("id"), knownvalue.StringExact("id-f91f202e-abe3-40b6-9d7d-f35fb3bf0471")),
+					statecheck.ExpectKnownValue("example_computed.test", tfjsonpath.New("list_optional"), knownvalue.ListSizeExact(0)),
+				},
+			},
+			// ImportState testing
+			{
+				ResourceName:      "example_computed.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// Read does not refresh from a backend, so an imported
+				// resource only knows its id.
+				ImportStateVerifyIgnore: []string{"replace", "replace_if_configured", "use_state_for_unknown", "list_optional"},
+			},
+			// RequiresReplaceIfConfigured
+			{
+				Config: testAccResourceComputedConfig("one", "two", "[]"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("example_computed.test", plancheck.ResourceActionDestroyBeforeCreate),
+					},
+				},
+			},
+			// RequiresReplace
+			{
+				Config: testAccResourceComputedConfig("two", "two", "[]"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("example_computed.test", plancheck.ResourceActionDestroyBeforeCreate),
+					},
+				},
+			},
+			// RequiresReplaceIfConfigured on list_optional, whose configured
+			// elements are kept as they are.
+			{
+				Config: testAccResourceComputedConfig("two", "two", `["a"]`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("example_computed.test", plancheck.ResourceActionDestroyBeforeCreate),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("example_computed.test", tfjsonpath.New("list_optional"), knownvalue.ListExact([]knownvalue.Check{
+						knownvalue.StringExact("a"),
+					})),
+				},
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccResourceComputedConfig(replace, replaceIfConfigured, listOptional string) string {
+	return fmt.Sprintf(`
+resource "example_computed" "test" {
+  replace               = %[1]q
+  replace_if_configured = %[2]q
+  list_optional         = %[3]s
+}
+`, replace, replaceIfConfigured, listOptional)
+}
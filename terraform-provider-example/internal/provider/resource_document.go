@@ -0,0 +1,159 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func NewResourceDocument() resource.Resource {
+	return newCRUDResource[ResourceDocumentModel]("document", ResourceDocument{})
+}
+
+// ResourceDocument defines the resource implementation. Unlike the other
+// resources its content has no fixed schema, it is stored in terraform-service
+// as JSON.
+type ResourceDocument struct{}
+
+var _ crudOperations[ResourceDocumentModel] = ResourceDocument{}
+
+// ResourceDocumentModel describes the resource data model.
+type (
+	ResourceDocumentModel struct {
+		Id      types.String  `tfsdk:"id"`
+		Project types.String  `tfsdk:"project"`
+		Content types.Dynamic `tfsdk:"content"`
+		Tags    types.Map     `tfsdk:"tags"`
+		TagsAll types.Map     `tfsdk:"tags_all"`
+	}
+
+	// documentAPIModel is the terraform-service representation of the
+	// resource.
+	documentAPIModel struct {
+		Id      string            `json:"id,omitempty"`
+		Content json.RawMessage   `json:"content"`
+		Tags    map[string]string `json:"tags,omitempty"`
+	}
+)
+
+func (r ResourceDocument) schema(ctx context.Context) schema.Schema {
+	return schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Document resource. Stores arbitrary structured data, such as an object, a list or a primitive, " +
+			"in terraform-service as JSON.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "ID",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"content": schema.DynamicAttribute{
+				MarkdownDescription: "文档内容，可以是任意类型的值。" +
+					"后端保存为 JSON，只有结构发生变化时才视为漂移，键的顺序与数字格式不影响比较",
+				Required: true,
+			},
+			"project":  projectAttribute(),
+			"tags":     tagsAttribute(),
+			"tags_all": tagsAllAttribute(),
+		},
+	}
+}
+
+func (r ResourceDocument) create(ctx context.Context, client *Client, data *ResourceDocumentModel) (string, error) {
+	body, diags := data.toAPIModel(ctx)
+	if err := errorFromDiags(diags); err != nil {
+		return "", err
+	}
+
+	var created documentAPIModel
+	if err := client.Do(ctx, http.MethodPost, "/document", body, &created); err != nil {
+		return "", err
+	}
+
+	return created.Id, errorFromDiags(data.fromAPIModel(ctx, created))
+}
+
+func (r ResourceDocument) read(ctx context.Context, client *Client, id string, data *ResourceDocumentModel) error {
+	var current documentAPIModel
+	if err := client.Do(ctx, http.MethodGet, "/document/"+url.PathEscape(id), nil, &current); err != nil {
+		return err
+	}
+
+	data.Tags = client.refreshTags(data.Tags, current.Tags)
+
+	return errorFromDiags(data.fromAPIModel(ctx, current))
+}
+
+func (r ResourceDocument) update(ctx context.Context, client *Client, id string, data *ResourceDocumentModel) error {
+	body, diags := data.toAPIModel(ctx)
+	if err := errorFromDiags(diags); err != nil {
+		return err
+	}
+
+	var updated documentAPIModel
+	if err := client.Do(ctx, http.MethodPut, "/document/"+url.PathEscape(id), body, &updated); err != nil {
+		return err
+	}
+
+	return errorFromDiags(data.fromAPIModel(ctx, updated))
+}
+
+func (r ResourceDocument) delete(ctx context.Context, client *Client, id string, data *ResourceDocumentModel) error {
+	return client.Do(ctx, http.MethodDelete, "/document/"+url.PathEscape(id), nil, nil)
+}
+
+func (s *ResourceDocumentModel) toAPIModel(ctx context.Context) (documentAPIModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	content, err := dynamicToJSON(ctx, s.Content)
+	if err != nil {
+		diags.AddAttributeError(path.Root("content"), "Invalid Document Content",
+			fmt.Sprintf("Unable to encode content as JSON: %s", err))
+	}
+
+	return documentAPIModel{
+		Id:      s.Id.ValueString(),
+		Content: content,
+		Tags:    expandTags(s.TagsAll),
+	}, diags
+}
+
+// fromAPIModel copies the backend object into the model. The content is only
+// replaced if it differs structurally from the current one, so that the type
+// chosen by the configuration, such as a list rather than a tuple, is kept.
+func (s *ResourceDocumentModel) fromAPIModel(ctx context.Context, m documentAPIModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	s.TagsAll = flattenTags(m.Tags)
+
+	if current, err := dynamicToJSON(ctx, s.Content); err == nil && jsonEqual(current, m.Content) {
+		return diags
+	}
+
+	content, err := dynamicFromJSON(ctx, m.Content)
+	if err != nil {
+		diags.AddAttributeError(path.Root("content"), "Invalid Document Content",
+			fmt.Sprintf("Unable to decode the content returned by terraform-service: %s", err))
+		return diags
+	}
+
+	s.Content = content
+
+	return diags
+}
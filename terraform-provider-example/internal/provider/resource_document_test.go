@@ -0,0 +1,254 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+
+	"terraform-service/server"
+)
+
+// documentContentType returns the type of the content attribute of an
+// example_document state.
+func documentContentType(t *testing.T, h *protocolHarness, dv *tfprotov6.DynamicValue) tftypes.Type {
+	t.Helper()
+
+	value, err := dv.Unmarshal(h.resourceSchema("example_document").ValueType())
+	if err != nil {
+		t.Fatalf("unable to decode example_document value: %s", err)
+	}
+
+	var attrs map[string]tftypes.Value
+	if err := value.As(&attrs); err != nil {
+		t.Fatalf("unable to decode example_document value: %s", err)
+	}
+
+	return attrs["content"].Type()
+}
+
+func TestProtocolResourceDocument(t *testing.T) {
+	endpoint := newLocalTestServer(t)
+	h := newProtocolHarness(t, map[string]interface{}{"endpoint": endpoint})
+
+	content := map[string]interface{}{
+		"name":   "web",
+		"ports":  []interface{}{80, 443},
+		"labels": map[string]interface{}{"env": "dev"},
+	}
+
+	// Create and Read testing
+	state := h.create("example_document", map[string]interface{}{"content": content})
+
+	id, _ := state["id"].(string)
+	if !strings.HasPrefix(id, "doc-") {
+		t.Fatalf("expected id assigned by the backend, got %v", state["id"])
+	}
+
+	expected := map[string]interface{}{
+		"name":   "web",
+		"ports":  []interface{}{int64(80), int64(443)},
+		"labels": map[string]interface{}{"env": "dev"},
+	}
+	if !reflect.DeepEqual(state["content"], expected) {
+		t.Errorf("expected content %v, got %v", expected, state["content"])
+	}
+
+	read := h.read("example_document", state)
+	requireNoErrors(t, "ReadResource", read.Diagnostics)
+
+	if got := h.decode("example_document", read.NewState); !reflect.DeepEqual(got["content"], expected) {
+		t.Errorf("expected content %v after read, got %v", expected, got["content"])
+	}
+
+	contentType := documentContentType(t, h, read.NewState)
+
+	// A backend that formats the same structure differently is not drift, the
+	// content and its type are kept as they are in state.
+	client := NewClient(endpoint, http.DefaultClient)
+	err := client.Do(context.Background(), http.MethodPut, "/document/"+id, documentAPIModel{
+		Content: json.RawMessage(`{"labels": {"env": "dev"}, "ports": [80.0, 4.43e2], "name": "web"}`),
+	}, nil)
+	if err != nil {
+		t.Fatalf("unable to update document: %s", err)
+	}
+
+	read = h.read("example_document", state)
+	requireNoErrors(t, "ReadResource", read.Diagnostics)
+
+	if got := documentContentType(t, h, read.NewState); !got.Equal(contentType) {
+		t.Errorf("expected content type %s to be kept, got %s", contentType, got)
+	}
+
+	// A structural change is detected as drift.
+	err = client.Do(context.Background(), http.MethodPut, "/document/"+id, documentAPIModel{
+		Content: json.RawMessage(`{"labels": {"env": "prod"}, "ports": [80, 443], "name": "web"}`),
+	}, nil)
+	if err != nil {
+		t.Fatalf("unable to update document: %s", err)
+	}
+
+	read = h.read("example_document", state)
+	requireNoErrors(t, "ReadResource", read.Diagnostics)
+
+	if got, _ := h.decode("example_document", read.NewState)["content"].(map[string]interface{}); !reflect.DeepEqual(got["labels"], map[string]interface{}{"env": "prod"}) {
+		t.Errorf("expected drifted labels, got %v", got["labels"])
+	}
+
+	// Update to other kinds of values
+	for _, content := range []interface{}{
+		[]interface{}{"a", true, 1.5},
+		"plain text",
+		int64(42),
+	} {
+		state = h.change("example_document", state, map[string]interface{}{"content": content})
+
+		if state["id"] != id || !reflect.DeepEqual(state["content"], content) {
+			t.Errorf("expected in-place update of %s to %v, got %v", id, content, state)
+		}
+	}
+
+	// ImportState testing
+	imported := h.importState("example_document", id)
+	requireNoErrors(t, "ImportResourceState", imported.Diagnostics)
+
+	read = h.read("example_document", h.decode("example_document", imported.ImportedResources[0].State))
+	requireNoErrors(t, "ReadResource", read.Diagnostics)
+
+	if got := h.decode("example_document", read.NewState); got["content"] != int64(42) {
+		t.Errorf("expected imported content to be read from the backend, got %v", got["content"])
+	}
+
+	h.destroy("example_document", state)
+}
+
+func TestDocumentCreate_Retried(t *testing.T) {
+	backend := server.New(server.Options{})
+
+	// The response to the first create is lost, as if the connection broke
+	// after the backend saved the document.
+	var lost int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && atomic.AddInt32(&lost, 1) == 1 {
+			backend.ServeHTTP(httptest.NewRecorder(), r)
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+
+		backend.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	client := NewClient(srv.URL, testRetryClient(4))
+
+	var created documentAPIModel
+	if err := client.Do(context.Background(), http.MethodPost, "/document", map[string]interface{}{"content": map[string]interface{}{"a": 1}}, &created); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var documents []documentAPIModel
+	if err := client.Do(context.Background(), http.MethodGet, "/document", nil, &documents); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(documents) != 1 || documents[0].Id != created.Id {
+		t.Errorf("expected the retried create to return document %s only, got %v", created.Id, documents)
+	}
+}
+
+func TestAccResourceDocument(t *testing.T) {
+	endpoint := newTestServer(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccResourceDocumentConfig(endpoint, `{
+    name  = "web"
+    ports = [80, 443]
+    labels = {
+      env = "dev"
+    }
+  }`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("example_document.test", tfjsonpath.New("id"), knownvalue.StringRegexp(regexp.MustCompile(`^doc-[0-9a-f]{16}$`))),
+					statecheck.ExpectKnownValue("example_document.test", tfjsonpath.New("content"), knownvalue.ObjectExact(map[string]knownvalue.Check{
+						"name":   knownvalue.StringExact("web"),
+						"ports":  knownvalue.TupleExact([]knownvalue.Check{knownvalue.Int64Exact(80), knownvalue.Int64Exact(443)}),
+						"labels": knownvalue.ObjectExact(map[string]knownvalue.Check{"env": knownvalue.StringExact("dev")}),
+					})),
+				},
+			},
+			// Applying the same configuration again is a no-op
+			{
+				Config: testAccResourceDocumentConfig(endpoint, `{
+    labels = {
+      env = "dev"
+    }
+    ports = [80, 443]
+    name  = "web"
+  }`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("example_document.test", plancheck.ResourceActionNoop),
+					},
+				},
+			},
+			// ImportState testing
+			{
+				ResourceName:      "example_document.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Update to a list, then a primitive
+			{
+				Config: testAccResourceDocumentConfig(endpoint, `tolist(["a", "b"])`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("example_document.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("example_document.test", tfjsonpath.New("content"), knownvalue.ListExact([]knownvalue.Check{
+						knownvalue.StringExact("a"),
+						knownvalue.StringExact("b"),
+					})),
+				},
+			},
+			{
+				Config: testAccResourceDocumentConfig(endpoint, `"plain text"`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("example_document.test", tfjsonpath.New("content"), knownvalue.StringExact("plain text")),
+				},
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccResourceDocumentConfig(endpoint, content string) string {
+	return testAccProviderConfig(endpoint) + fmt.Sprintf(`
+resource "example_document" "test" {
+  content = %s
+}
+`, content)
+}
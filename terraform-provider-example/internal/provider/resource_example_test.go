@@ -5,6 +5,7 @@ package provider
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
@@ -54,3 +55,49 @@ resource "scaffolding_example" "test" {
 }
 `, configurableAttribute)
 }
+
+func TestProtocolResourceExample(t *testing.T) {
+	h := newProtocolHarness(t, nil)
+
+	// Create and Read testing
+	state := h.create("example_example", map[string]interface{}{
+		"configurable_attribute": "one",
+	})
+
+	expected := map[string]interface{}{
+		"configurable_attribute": "one",
+		"defaulted":              "example value when not configured",
+		"id":                     "example-id",
+		"tags":                   nil,
+		"tags_all":               map[string]interface{}{},
+	}
+	if !reflect.DeepEqual(state, expected) {
+		t.Errorf("expected %v after create, got %v", expected, state)
+	}
+
+	// ImportState testing
+	imported := h.importState("example_example", "example-id")
+	requireNoErrors(t, "ImportResourceState", imported.Diagnostics)
+
+	if len(imported.ImportedResources) != 1 {
+		t.Fatalf("expected 1 imported resource, got %d", len(imported.ImportedResources))
+	}
+
+	read := h.read("example_example", h.decode("example_example", imported.ImportedResources[0].State))
+	requireNoErrors(t, "ReadResource", read.Diagnostics)
+
+	if got := h.decode("example_example", read.NewState)["id"]; got != "example-id" {
+		t.Errorf("expected imported id example-id, got %v", got)
+	}
+
+	// Update and Read testing
+	state = h.change("example_example", state, map[string]interface{}{
+		"configurable_attribute": "two",
+	})
+
+	if state["configurable_attribute"] != "two" {
+		t.Errorf("expected configurable_attribute two, got %v", state["configurable_attribute"])
+	}
+
+	h.destroy("example_example", state)
+}
@@ -5,32 +5,24 @@ package provider
 
 import (
 	"context"
-	"fmt"
-	"github.com/hashicorp/terraform-plugin-framework/diag"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
-	"net/http"
 
-	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
-// Ensure provider defined types fully satisfy framework interfaces.
-var _ resource.Resource = &ResourceModifier{}
-var _ resource.ResourceWithImportState = &ResourceModifier{}
-
 func NewResourceModifier() resource.Resource {
-	return &ResourceModifier{}
+	return newCRUDResource[ResourceModifierModel]("modifier", ResourceModifier{})
 }
 
 // ResourceModifier defines the resource implementation.
-type ResourceModifier struct {
-	client *http.Client
-}
+type ResourceModifier struct{}
+
+var _ crudOperations[ResourceModifierModel] = ResourceModifier{}
 
 // ResourceModifierModel describes the resource data model.
 type (
@@ -40,15 +32,13 @@ type (
 		ReplaceIfConfigured types.String `tfsdk:"replace_if_configured"`
 		UseStateForUnknown  types.String `tfsdk:"use_state_for_unknown"`
 		ListOptional        types.List   `tfsdk:"list_optional"`
+		Tags                types.Map    `tfsdk:"tags"`
+		TagsAll             types.Map    `tfsdk:"tags_all"`
 	}
 )
 
-func (r *ResourceModifier) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
-	resp.TypeName = req.ProviderTypeName + "_modifier"
-}
-
-func (r *ResourceModifier) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
-	resp.Schema = schema.Schema{
+func (r ResourceModifier) schema(ctx context.Context) schema.Schema {
+	return schema.Schema{
 		// This description is used by the documentation generator and the language server.
 		MarkdownDescription: "Set Nested 2 Example resource",
 
@@ -89,110 +79,39 @@ func (r *ResourceModifier) Schema(ctx context.Context, req resource.SchemaReques
 					listplanmodifier.RequiresReplaceIfConfigured(),
 				},
 			},
+			"tags":     tagsAttribute(),
+			"tags_all": tagsAllAttribute(),
 		},
 	}
 }
 
-func (r *ResourceModifier) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
-	// Prevent panic if the provider has not been configured.
-	if req.ProviderData == nil {
-		return
-	}
-
-	client, ok := req.ProviderData.(*http.Client)
-
-	if !ok {
-		resp.Diagnostics.AddError(
-			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *http.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
-		)
-
-		return
+func (r ResourceModifier) create(ctx context.Context, client *Client, data *ResourceModifierModel) (string, error) {
+	if err := errorFromDiags(data.fnConvert(ctx)); err != nil {
+		return "", err
 	}
 
-	r.client = client
+	return "id-f91f202e-abe3-40b6-9d7d-f35fb3bf0471", nil
 }
 
-func (r *ResourceModifier) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
-	var data ResourceModifierModel
-
-	// Read Terraform plan data into the model
-	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
-	if resp.Diagnostics.HasError() {
-		return
+func (r ResourceModifier) read(ctx context.Context, client *Client, id string, data *ResourceModifierModel) error {
+	if err := errorFromDiags(data.fnConvert(ctx)); err != nil {
+		return err
 	}
 
-	data.Id = types.StringValue("id-f91f202e-abe3-40b6-9d7d-f35fb3bf0471")
-	diags := data.fnConvert(ctx)
-
-	resp.Diagnostics.Append(diags...)
-	if resp.Diagnostics.HasError() {
-		return
+	// A resource imported by id has no tags_all yet
+	if data.TagsAll.IsNull() {
+		data.TagsAll = client.mergeTags(data.Tags)
 	}
 
-	// Write logs using the tflog package
-	// Documentation: https://terraform.io/plugin/log
-	tflog.Trace(ctx, "created a resource")
-
-	// Save data into Terraform state
-	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
-}
-
-func (r *ResourceModifier) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
-	var data ResourceModifierModel
-
-	// Read Terraform prior state data into the model
-	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
-
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	diags := data.fnConvert(ctx)
-
-	resp.Diagnostics.Append(diags...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	// Save updated data into Terraform state
-	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
-}
-
-func (r *ResourceModifier) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var data ResourceModifierModel
-
-	// Read Terraform plan data into the model
-	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
-
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	diags := data.fnConvert(ctx)
-
-	resp.Diagnostics.Append(diags...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	// Save updated data into Terraform state
-	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	return nil
 }
 
-func (r *ResourceModifier) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	var data ResourceModifierModel
-
-	// Read Terraform prior state data into the model
-	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
-
-	if resp.Diagnostics.HasError() {
-		return
-	}
+func (r ResourceModifier) update(ctx context.Context, client *Client, id string, data *ResourceModifierModel) error {
+	return errorFromDiags(data.fnConvert(ctx))
 }
 
-func (r *ResourceModifier) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+func (r ResourceModifier) delete(ctx context.Context, client *Client, id string, data *ResourceModifierModel) error {
+	return nil
 }
 
 func (s *ResourceModifierModel) fnConvert(ctx context.Context) diag.Diagnostics {
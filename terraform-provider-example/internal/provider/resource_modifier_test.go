@@ -0,0 +1,158 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestProtocolResourceModifier(t *testing.T) {
+	h := newProtocolHarness(t, nil)
+
+	config := map[string]interface{}{
+		"replace":               "one",
+		"replace_if_configured": "one",
+		"use_state_for_unknown": "one",
+		"list_optional":         []interface{}{"a"},
+	}
+	state := h.create("example_modifier", config)
+
+	if state["id"] == nil {
+		t.Fatal("expected id to be set after create")
+	}
+
+	updated := map[string]interface{}{
+		"replace":               "one",
+		"replace_if_configured": "one",
+		"use_state_for_unknown": "two",
+		"list_optional":         []interface{}{"a"},
+	}
+
+	plan := h.plan("example_modifier", state, updated)
+	requireNoErrors(t, "PlanResourceChange", plan.Diagnostics)
+
+	if got := requiresReplace(plan); len(got) != 0 {
+		t.Errorf("expected in-place update, got replacement for %v", got)
+	}
+	if got := h.decode("example_modifier", plan.PlannedState)["id"]; got != state["id"] {
+		t.Errorf("expected id %v to be kept from state, got %v", state["id"], got)
+	}
+
+	state = h.change("example_modifier", state, updated)
+
+	replaced := map[string]interface{}{
+		"replace":               "two",
+		"replace_if_configured": "two",
+		"use_state_for_unknown": "two",
+		"list_optional":         []interface{}{"b"},
+	}
+
+	plan = h.plan("example_modifier", state, replaced)
+	requireNoErrors(t, "PlanResourceChange", plan.Diagnostics)
+
+	expected := []string{"list_optional", "replace", "replace_if_configured"}
+	if got := requiresReplace(plan); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected replacement for %v, got %v", expected, got)
+	}
+
+	h.destroy("example_modifier", state)
+}
+
+func TestAccResourceModifier(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccResourceModifierConfig("test_router_12", "one", "test_router", `["test1", "test2"]`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("example_modifier.test", plancheck.ResourceActionCreate),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("example_modifier.test", tfjsonpath.New("id"), knownvalue.NotNull()),
+					statecheck.ExpectKnownValue("example_modifier.test", tfjsonpath.New("list_optional"), knownvalue.ListExact([]knownvalue.Check{
+						knownvalue.StringExact("test1"),
+						knownvalue.StringExact("test2"),
+					})),
+				},
+			},
+			// ImportState testing
+			{
+				ResourceName:      "example_modifier.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// Read does not refresh from a backend, so an imported
+				// resource only knows its id.
+				ImportStateVerifyIgnore: []string{"replace", "replace_if_configured", "use_state_for_unknown", "list_optional"},
+			},
+			// UseStateForUnknown does not force replacement
+			{
+				Config: testAccResourceModifierConfig("test_router_12", "one", "test_router_2", `["test1", "test2"]`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("example_modifier.test", plancheck.ResourceActionUpdate),
+					},
+				},
+			},
+			// Removing an attribute with RequiresReplaceIfConfigured does not
+			// force replacement
+			{
+				Config: testAccResourceModifierConfig("test_router_12", "", "test_router_2", ""),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("example_modifier.test", plancheck.ResourceActionUpdate),
+					},
+				},
+			},
+			// Setting it again does
+			{
+				Config: testAccResourceModifierConfig("test_router_12", "two", "test_router_2", ""),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("example_modifier.test", plancheck.ResourceActionDestroyBeforeCreate),
+					},
+				},
+			},
+			// RequiresReplace
+			{
+				Config: testAccResourceModifierConfig("test_router_13", "two", "test_router_2", ""),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("example_modifier.test", plancheck.ResourceActionDestroyBeforeCreate),
+					},
+				},
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccResourceModifierConfig(replace, replaceIfConfigured, useStateForUnknown, listOptional string) string {
+	config := fmt.Sprintf(`
+resource "example_modifier" "test" {
+  replace               = %[1]q
+  use_state_for_unknown = %[2]q
+`, replace, useStateForUnknown)
+
+	if replaceIfConfigured != "" {
+		config += fmt.Sprintf("  replace_if_configured = %q\n", replaceIfConfigured)
+	}
+
+	if listOptional != "" {
+		config += fmt.Sprintf("  list_optional         = %s\n", listOptional)
+	}
+
+	return config + "}\n"
+}
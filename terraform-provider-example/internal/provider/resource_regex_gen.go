@@ -0,0 +1,224 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Code generated by resourcegen from ../terraform-service/server/openapi.json. DO NOT EDIT.
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func NewResourceRegex() resource.Resource {
+	return newCRUDResource[ResourceRegexModel]("regex", ResourceRegex{})
+}
+
+func NewListResourceRegex() list.ListResource {
+	return newCRUDListResource[ResourceRegexModel]("regex", ResourceRegex{})
+}
+
+// ResourceRegex implements the schema and the backend calls of the
+// resource.
+type ResourceRegex struct{}
+
+var _ crudOperations[ResourceRegexModel] = ResourceRegex{}
+var _ crudLister[ResourceRegexModel] = ResourceRegex{}
+
+type (
+	// ResourceRegexModel describes the resource data model.
+	ResourceRegexModel struct {
+		Id           types.String   `tfsdk:"id"`
+		Project      types.String   `tfsdk:"project"`
+		Name         types.String   `tfsdk:"name"`
+		Alias        types.String   `tfsdk:"alias"`
+		UserDataJson NormalizedJSON `tfsdk:"user_data_json"`
+		PowerState   types.String   `tfsdk:"power_state"`
+		Flavor       types.String   `tfsdk:"flavor"`
+		Tags         types.Map      `tfsdk:"tags"`
+		TagsAll      types.Map      `tfsdk:"tags_all"`
+		Timeouts     timeouts.Value `tfsdk:"timeouts"`
+	}
+
+	// regexAPIModel is the terraform-service representation of the resource.
+	regexAPIModel struct {
+		Id           string            `json:"id,omitempty"`
+		Name         string            `json:"name"`
+		Alias        *string           `json:"alias,omitempty"`
+		UserDataJson json.RawMessage   `json:"user_data_json,omitempty"`
+		PowerState   string            `json:"power_state,omitempty"`
+		Flavor       string            `json:"flavor,omitempty"`
+		Tags         map[string]string `json:"tags,omitempty"`
+	}
+)
+
+func (r ResourceRegex) schema(ctx context.Context) schema.Schema {
+	return schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "虚机",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "ID",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "虚机名称",
+				Required:            true,
+			},
+			"alias": schema.StringAttribute{
+				MarkdownDescription: "虚机别名",
+				Optional:            true,
+			},
+			"user_data_json": schema.StringAttribute{
+				MarkdownDescription: "虚机 user data，JSON 格式。后端会重新格式化，只有 JSON 结构变化时才会产生差异",
+				Optional:            true,
+				CustomType:          NormalizedJSONType{},
+			},
+			"power_state": schema.StringAttribute{
+				MarkdownDescription: "电源状态，running 或 stopped，由 stop 与 start 操作修改",
+				Computed:            true,
+			},
+			"flavor": schema.StringAttribute{
+				MarkdownDescription: "规格，small、medium 或 large，由 resize 操作修改",
+				Computed:            true,
+			},
+			"project":  projectAttribute(),
+			"tags":     tagsAttribute(),
+			"tags_all": tagsAllAttribute(),
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+			}),
+		},
+	}
+}
+
+func (r ResourceRegex) create(ctx context.Context, client *Client, data *ResourceRegexModel) (string, error) {
+	body, diags := data.toAPIModel(ctx)
+	if err := errorFromDiags(diags); err != nil {
+		return "", err
+	}
+
+	var created regexAPIModel
+
+	// Creating the object is asynchronous, the backend answers with an operation
+	op, err := client.StartOperation(ctx, http.MethodPost, "/regex", body)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := client.WaitForOperation(ctx, op.URL); err != nil {
+		return createdResourceID(op, err), err
+	}
+
+	err = client.Do(ctx, http.MethodGet, "/regex/"+url.PathEscape(op.ResourceId), nil, &created)
+	if err != nil {
+		return "", fmt.Errorf("read created regex: %w", err)
+	}
+
+	return created.Id, errorFromDiags(data.fromAPIModel(ctx, created))
+}
+
+func (r ResourceRegex) read(ctx context.Context, client *Client, id string, data *ResourceRegexModel) error {
+	var current regexAPIModel
+	if err := client.Do(ctx, http.MethodGet, "/regex/"+url.PathEscape(id), nil, &current); err != nil {
+		return err
+	}
+
+	data.Tags = client.refreshTags(data.Tags, current.Tags)
+
+	return errorFromDiags(data.fromAPIModel(ctx, current))
+}
+
+func (r ResourceRegex) update(ctx context.Context, client *Client, id string, data *ResourceRegexModel) error {
+	body, diags := data.toAPIModel(ctx)
+	if err := errorFromDiags(diags); err != nil {
+		return err
+	}
+
+	var updated regexAPIModel
+	if err := client.Do(ctx, http.MethodPut, "/regex/"+url.PathEscape(id), body, &updated); err != nil {
+		return err
+	}
+
+	return errorFromDiags(data.fromAPIModel(ctx, updated))
+}
+
+func (r ResourceRegex) delete(ctx context.Context, client *Client, id string, data *ResourceRegexModel) error {
+	return client.Do(ctx, http.MethodDelete, "/regex/"+url.PathEscape(id), nil, nil)
+}
+
+func (r ResourceRegex) list(ctx context.Context, client *Client, query url.Values, push func(id, displayName string, fill func(data *ResourceRegexModel) error) bool) error {
+	path := "/regex"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	var objects []regexAPIModel
+	if err := client.Do(ctx, http.MethodGet, path, nil, &objects); err != nil {
+		return err
+	}
+
+	for _, current := range objects {
+		fill := func(data *ResourceRegexModel) error {
+			data.Tags = client.refreshTags(data.Tags, current.Tags)
+			return errorFromDiags(data.fromAPIModel(ctx, current))
+		}
+
+		if !push(current.Id, current.Name, fill) {
+			break
+		}
+	}
+
+	return nil
+}
+
+func (s *ResourceRegexModel) toAPIModel(ctx context.Context) (regexAPIModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	m := regexAPIModel{
+		Id:   s.Id.ValueString(),
+		Tags: expandTags(s.TagsAll),
+	}
+	m.Name = s.Name.ValueString()
+	m.Alias = s.Alias.ValueStringPointer()
+	m.UserDataJson = s.UserDataJson.ValueRaw()
+
+	return m, diags
+}
+
+// fromAPIModel copies the backend object into the model, then runs the
+// customizeState hook if the resource has one.
+func (s *ResourceRegexModel) fromAPIModel(ctx context.Context, m regexAPIModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	s.Name = types.StringValue(m.Name)
+	s.Alias = types.StringPointerValue(m.Alias)
+	s.UserDataJson = newNormalizedJSONFromRaw(m.UserDataJson)
+	s.PowerState = types.StringValue(m.PowerState)
+	s.Flavor = types.StringValue(m.Flavor)
+	s.TagsAll = flattenTags(m.Tags)
+
+	if c, ok := interface{}(s).(stateCustomizer); ok && !diags.HasError() {
+		diags.Append(c.customizeState(ctx)...)
+	}
+
+	return diags
+}
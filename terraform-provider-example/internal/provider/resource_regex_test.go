@@ -0,0 +1,404 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+
+	"terraform-service/server"
+)
+
+func TestProtocolResourceRegex_Validate(t *testing.T) {
+	h := newProtocolHarness(t, nil)
+
+	requireNoErrors(t, "ValidateResourceConfig", h.validate("example_regex", map[string]interface{}{
+		"name":  "test01",
+		"alias": "测试 01",
+	}))
+
+	testCases := map[string]struct {
+		config map[string]interface{}
+	}{
+		"name-non-ascii": {
+			config: map[string]interface{}{"name": "啊t01"},
+		},
+		"name-trailing-dash": {
+			config: map[string]interface{}{"name": "t01-"},
+		},
+		"alias-dots": {
+			config: map[string]interface{}{"name": "test01", "alias": ".asd."},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			requireError(t, "ValidateResourceConfig", h.validate("example_regex", tc.config), "Invalid Attribute Value Match")
+		})
+	}
+
+	requireError(t, "ValidateResourceConfig", h.validate("example_regex", map[string]interface{}{
+		"name":           "test01",
+		"user_data_json": `{"a": 1`,
+	}), "Invalid JSON String Value")
+}
+
+func TestProtocolResourceRegex_Plan(t *testing.T) {
+	h := newProtocolHarness(t, nil)
+
+	plan := h.plan("example_regex", nil, map[string]interface{}{"name": "test01"})
+	requireNoErrors(t, "PlanResourceChange", plan.Diagnostics)
+
+	planned := h.decode("example_regex", plan.PlannedState)
+	if planned["id"] != unknownValue {
+		t.Errorf("expected unknown id, got %v", planned["id"])
+	}
+	if planned["name"] != "test01" {
+		t.Errorf("expected name test01, got %v", planned["name"])
+	}
+
+	prior := map[string]interface{}{"id": "vm-1", "name": "test01"}
+	plan = h.plan("example_regex", prior, map[string]interface{}{"name": "test02"})
+	requireNoErrors(t, "PlanResourceChange", plan.Diagnostics)
+
+	if got := h.decode("example_regex", plan.PlannedState)["id"]; got != "vm-1" {
+		t.Errorf("expected id to be kept from state, got %v", got)
+	}
+	if got := requiresReplace(plan); len(got) != 0 {
+		t.Errorf("expected in-place update, got replacement for %v", got)
+	}
+}
+
+func TestProtocolResourceRegex(t *testing.T) {
+	h := newProtocolHarness(t, map[string]interface{}{"endpoint": newLocalTestServer(t)})
+
+	// Create and Read testing
+	state := h.create("example_regex", map[string]interface{}{
+		"name":  "test01",
+		"alias": "测试 01",
+	})
+
+	id, _ := state["id"].(string)
+	if !strings.HasPrefix(id, "vm-") {
+		t.Fatalf("expected id assigned by the backend, got %v", state["id"])
+	}
+
+	read := h.read("example_regex", state)
+	requireNoErrors(t, "ReadResource", read.Diagnostics)
+
+	if got := h.decode("example_regex", read.NewState); !knownValuesMatch(state, got) {
+		t.Errorf("expected %v after read, got %v", state, got)
+	}
+
+	// The backend rejects a second VM with the same name on the name
+	// attribute.
+	plan := h.plan("example_regex", nil, map[string]interface{}{"name": "test01"})
+	requireNoErrors(t, "PlanResourceChange", plan.Diagnostics)
+
+	apply := h.apply("example_regex", nil, map[string]interface{}{"name": "test01"}, plan)
+	requireError(t, "ApplyResourceChange", apply.Diagnostics, "already in use")
+
+	// ImportState testing
+	imported := h.importState("example_regex", id)
+	requireNoErrors(t, "ImportResourceState", imported.Diagnostics)
+
+	read = h.read("example_regex", h.decode("example_regex", imported.ImportedResources[0].State))
+	requireNoErrors(t, "ReadResource", read.Diagnostics)
+
+	if got := h.decode("example_regex", read.NewState); got["name"] != "test01" || got["alias"] != "测试 01" {
+		t.Errorf("expected imported resource to be read from the backend, got %v", got)
+	}
+
+	// Update and Read testing
+	state = h.change("example_regex", state, map[string]interface{}{
+		"name": "test02",
+	})
+
+	if state["id"] != id || state["name"] != "test02" || state["alias"] != nil {
+		t.Errorf("expected in-place update of %s, got %v", id, state)
+	}
+
+	h.destroy("example_regex", state)
+
+	// A resource deleted outside of Terraform is removed from state.
+	read = h.read("example_regex", state)
+	requireNoErrors(t, "ReadResource", read.Diagnostics)
+
+	if got := h.decode("example_regex", read.NewState); got != nil {
+		t.Errorf("expected resource to be removed from state, got %v", got)
+	}
+}
+
+func TestProtocolResourceRegex_PowerState(t *testing.T) {
+	endpoint := newLocalTestServer(t)
+	h := newProtocolHarness(t, map[string]interface{}{"endpoint": endpoint})
+
+	state := h.create("example_regex", map[string]interface{}{"name": "test01"})
+
+	if state["power_state"] != "running" || state["flavor"] != "small" {
+		t.Fatalf("expected a running small VM, got %v", state)
+	}
+
+	// Actions change the VM outside of its configuration, and are reported
+	// by the next read.
+	_, diags := h.invoke("example_vm_stop", map[string]interface{}{"id": state["id"]})
+	requireNoErrors(t, "InvokeAction", diags)
+
+	_, diags = h.invoke("example_vm_reboot", map[string]interface{}{"id": state["id"]})
+	requireError(t, "InvokeAction", diags, "is stopped")
+
+	_, diags = h.invoke("example_vm_resize", map[string]interface{}{"id": state["id"], "flavor": "medium"})
+	requireNoErrors(t, "InvokeAction", diags)
+
+	read := h.read("example_regex", state)
+	requireNoErrors(t, "ReadResource", read.Diagnostics)
+
+	state = h.decode("example_regex", read.NewState)
+	if state["power_state"] != "running" || state["flavor"] != "medium" {
+		t.Errorf("expected resize to start the VM with the new flavor, got %v", state)
+	}
+
+	// Updates keep the state set by actions.
+	state = h.change("example_regex", state, map[string]interface{}{"name": "test02"})
+	if state["flavor"] != "medium" {
+		t.Errorf("expected update to keep the flavor, got %v", state)
+	}
+
+	h.destroy("example_regex", state)
+}
+
+func TestProtocolResourceRegex_CreateTimeout(t *testing.T) {
+	endpoint := newTestServerWithOptions(t, server.Options{OperationDuration: 200 * time.Millisecond})
+	h := newProtocolHarness(t, map[string]interface{}{"endpoint": endpoint})
+
+	config := map[string]interface{}{
+		"name":     "test01",
+		"timeouts": map[string]interface{}{"create": "20ms"},
+	}
+
+	plan := h.plan("example_regex", nil, config)
+	requireNoErrors(t, "PlanResourceChange", plan.Diagnostics)
+
+	apply := h.apply("example_regex", nil, config, plan)
+	requireError(t, "ApplyResourceChange", apply.Diagnostics, "context deadline exceeded")
+
+	// The backend creates the VM nevertheless. Its ID is saved with the
+	// error so that Terraform keeps the VM as tainted.
+	id, _ := h.decode("example_regex", apply.NewState)["id"].(string)
+	if !strings.HasPrefix(id, "vm-") {
+		t.Fatalf("expected id of the accepted VM in state, got %v", id)
+	}
+
+	client := NewClient(endpoint, http.DefaultClient)
+
+	var vm regexAPIModel
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(50 * time.Millisecond) {
+		err := client.Do(context.Background(), http.MethodGet, "/regex/"+id, nil, &vm)
+		if err == nil {
+			break
+		}
+		if !isNotFound(err) || time.Now().After(deadline) {
+			t.Fatalf("expected VM %s to be created, got %v", id, err)
+		}
+	}
+
+	if vm.Name != "test01" {
+		t.Errorf("expected VM test01, got %v", vm)
+	}
+}
+
+func TestProtocolResourceRegex_UserDataJSON(t *testing.T) {
+	h := newProtocolHarness(t, map[string]interface{}{"endpoint": newLocalTestServer(t)})
+
+	// The backend stores user data compacted with sorted keys. The value
+	// written in the configuration is kept in state.
+	userData := `{
+  "packages": ["nginx", "git"],
+  "hostname": "web-01"
+}`
+
+	state := h.create("example_regex", map[string]interface{}{
+		"name":           "test01",
+		"user_data_json": userData,
+	})
+
+	if state["user_data_json"] != userData {
+		t.Errorf("expected user_data_json %q, got %q", userData, state["user_data_json"])
+	}
+
+	read := h.read("example_regex", state)
+	requireNoErrors(t, "ReadResource", read.Diagnostics)
+
+	if got := h.decode("example_regex", read.NewState)["user_data_json"]; got != userData {
+		t.Errorf("expected user_data_json %q after read, got %q", userData, got)
+	}
+
+	// A structural change is applied and refreshed as is.
+	changed := `{"hostname": "web-02", "packages": ["nginx", "git"]}`
+	state = h.change("example_regex", state, map[string]interface{}{
+		"name":           "test01",
+		"user_data_json": changed,
+	})
+
+	if state["user_data_json"] != changed {
+		t.Errorf("expected user_data_json %q, got %q", changed, state["user_data_json"])
+	}
+
+	// Imported resources get the user data as formatted by the backend.
+	id, _ := state["id"].(string)
+	imported := h.importState("example_regex", id)
+	requireNoErrors(t, "ImportResourceState", imported.Diagnostics)
+
+	read = h.read("example_regex", h.decode("example_regex", imported.ImportedResources[0].State))
+	requireNoErrors(t, "ReadResource", read.Diagnostics)
+
+	if got := h.decode("example_regex", read.NewState)["user_data_json"]; got != `{"hostname":"web-02","packages":["nginx","git"]}` {
+		t.Errorf("expected normalized user_data_json, got %q", got)
+	}
+
+	state = h.change("example_regex", state, map[string]interface{}{"name": "test01"})
+
+	if state["user_data_json"] != nil {
+		t.Errorf("expected user_data_json to be removed, got %q", state["user_data_json"])
+	}
+
+	h.destroy("example_regex", state)
+}
+
+func TestAccResourceRegex(t *testing.T) {
+	endpoint := newTestServer(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccResourceRegexConfig(endpoint, testAccNamePrefix+"-01", "测试 01"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("example_regex.test", plancheck.ResourceActionCreate),
+						plancheck.ExpectUnknownValue("example_regex.test", tfjsonpath.New("id")),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("example_regex.test", tfjsonpath.New("id"), knownvalue.StringRegexp(regexp.MustCompile(`^vm-[0-9a-f]{16}$`))),
+					statecheck.ExpectKnownValue("example_regex.test", tfjsonpath.New("name"), knownvalue.StringExact(testAccNamePrefix+"-01")),
+					statecheck.ExpectKnownValue("example_regex.test", tfjsonpath.New("alias"), knownvalue.StringExact("测试 01")),
+				},
+			},
+			// ImportState testing
+			{
+				ResourceName:      "example_regex.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Update and Read testing
+			{
+				Config: testAccResourceRegexConfig(endpoint, testAccNamePrefix+"-02", ""),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("example_regex.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("example_regex.test", tfjsonpath.New("name"), knownvalue.StringExact(testAccNamePrefix+"-02")),
+					statecheck.ExpectKnownValue("example_regex.test", tfjsonpath.New("alias"), knownvalue.Null()),
+				},
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccResourceRegex_Validation(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccResourceRegexConfig(defaultEndpoint, "啊t01", ""),
+				ExpectError: regexp.MustCompile(`Invalid Attribute Value Match`),
+			},
+			{
+				Config:      testAccResourceRegexConfig(defaultEndpoint, "t01-", ""),
+				ExpectError: regexp.MustCompile(`Invalid Attribute Value Match`),
+			},
+			{
+				Config:      testAccResourceRegexConfig(defaultEndpoint, "test01", ".asd."),
+				ExpectError: regexp.MustCompile(`Invalid Attribute Value Match`),
+			},
+			{
+				Config:      testAccResourceRegexConfig(defaultEndpoint, "test01", "。"),
+				ExpectError: regexp.MustCompile(`Invalid Attribute Value Match`),
+			},
+		},
+	})
+}
+
+func TestAccResourceRegex_UserDataJSON(t *testing.T) {
+	endpoint := newTestServer(t)
+
+	config := testAccProviderConfig(endpoint) + `
+resource "example_regex" "test" {
+  name = "tf-acc-test-01"
+  user_data_json = jsonencode({
+    packages = ["nginx", "git"]
+    hostname = "web-01"
+  })
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+			},
+			// The backend reformats the JSON, which must not show up as a diff
+			{
+				Config: config,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+			{
+				Config: testAccProviderConfig(endpoint) + `
+resource "example_regex" "test" {
+  name           = "tf-acc-test-01"
+  user_data_json = "{not json"
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid JSON String Value`),
+			},
+		},
+	})
+}
+
+func testAccResourceRegexConfig(endpoint, name, alias string) string {
+	config := testAccProviderConfig(endpoint) + fmt.Sprintf(`
+resource "example_regex" "test" {
+  name = %[1]q
+`, name)
+
+	if alias != "" {
+		config += fmt.Sprintf("  alias = %q\n", alias)
+	}
+
+	return config + "}\n"
+}
@@ -0,0 +1,362 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func NewResourceServerNetworks() resource.Resource {
+	return newCRUDResource[ResourceServerNetworksModel]("server_networks", ResourceServerNetworks{})
+}
+
+// ResourceServerNetworks defines the resource implementation. It manages the
+// same NIC attachments as ResourceSetNested, but with nested blocks instead of
+// a nested attribute.
+type ResourceServerNetworks struct{}
+
+var _ crudOperations[ResourceServerNetworksModel] = ResourceServerNetworks{}
+var _ crudPlanModifier = ResourceServerNetworks{}
+
+// ResourceServerNetworksModel describes the resource data model.
+type (
+	ResourceServerNetworksModel struct {
+		Id            types.String   `tfsdk:"id"`
+		Project       types.String   `tfsdk:"project"`
+		Network       types.List     `tfsdk:"network"`
+		SecurityGroup types.Set      `tfsdk:"security_group"`
+		Tags          types.Map      `tfsdk:"tags"`
+		TagsAll       types.Map      `tfsdk:"tags_all"`
+		Timeouts      timeouts.Value `tfsdk:"timeouts"`
+	}
+
+	ServerNetworkModel struct {
+		Uuid    types.String `tfsdk:"uuid"`
+		FixedIp types.String `tfsdk:"fixed_ip"`
+		Port    types.String `tfsdk:"port"`
+		Mac     types.String `tfsdk:"mac"`
+		Primary types.Bool   `tfsdk:"primary"`
+	}
+
+	SecurityGroupModel struct {
+		Id types.String `tfsdk:"id"`
+	}
+
+	// serverNetworksAPIModel is the terraform-service representation of the
+	// resource. Networks are kept in order, the first one is the primary NIC.
+	serverNetworksAPIModel struct {
+		Id             string            `json:"id,omitempty"`
+		Networks       []networkAPIModel `json:"networks"`
+		SecurityGroups []string          `json:"security_groups"`
+		Tags           map[string]string `json:"tags,omitempty"`
+	}
+
+	networkAPIModel struct {
+		Uuid    string `json:"uuid"`
+		FixedIp string `json:"fixed_ip,omitempty"`
+		Port    string `json:"port,omitempty"`
+		Mac     string `json:"mac,omitempty"`
+		Primary bool   `json:"primary,omitempty"`
+	}
+)
+
+var (
+	serverNetworkModelTypeMap = map[string]attr.Type{
+		"uuid":     types.StringType,
+		"fixed_ip": types.StringType,
+		"port":     types.StringType,
+		"mac":      types.StringType,
+		"primary":  types.BoolType,
+	}
+
+	securityGroupModelTypeMap = map[string]attr.Type{
+		"id": types.StringType,
+	}
+)
+
+func (r ResourceServerNetworks) schema(ctx context.Context) schema.Schema {
+	return schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Server networks resource. Attaches networks to a server like `example_set_nested`, " +
+			"but with `network` and `security_group` blocks instead of the `set_nested` attribute. " +
+			"`network` blocks keep their order: the first one is the primary NIC, and reordering them is an update.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "ID",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"project":  projectAttribute(),
+			"tags":     tagsAttribute(),
+			"tags_all": tagsAllAttribute(),
+		},
+		Blocks: map[string]schema.Block{
+			"network": schema.ListNestedBlock{
+				MarkdownDescription: "网卡，1 至 8 个，第一个为主网卡",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"uuid": schema.StringAttribute{
+							MarkdownDescription: "经典网络ID",
+							Required:            true,
+						},
+						"fixed_ip": schema.StringAttribute{
+							MarkdownDescription: "指定IP地址",
+							Optional:            true,
+						},
+						"port": schema.StringAttribute{
+							MarkdownDescription: "网卡端口ID",
+							Computed:            true,
+						},
+						"mac": schema.StringAttribute{
+							MarkdownDescription: "MAC地址",
+							Computed:            true,
+						},
+						"primary": schema.BoolAttribute{
+							MarkdownDescription: "是否为主网卡",
+							Computed:            true,
+						},
+					},
+				},
+				// Empty blocks are null to validators, which skip null
+				// values, so SizeBetween alone does not require a block.
+				Validators: []validator.List{
+					listvalidator.IsRequired(),
+					listvalidator.SizeBetween(1, 8),
+				},
+			},
+			"security_group": schema.SetNestedBlock{
+				MarkdownDescription: "安全组，最多 5 个，没有顺序",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "安全组ID",
+							Required:            true,
+						},
+					},
+				},
+				Validators: []validator.Set{
+					setvalidator.SizeAtMost(5),
+				},
+			},
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+			}),
+		},
+	}
+}
+
+// modifyPlan plans the computed attributes of the network blocks.
+// Terraform pairs blocks with the prior ones by index, so a block moved to
+// another index would be planned with the port and mac of the NIC that was
+// there before. The backend keeps the port and mac of networks that stay
+// attached, whatever their index, so they are planned from the prior block
+// with the same uuid instead, and are unknown for networks attached anew.
+// The first block is the primary NIC.
+func (r ResourceServerNetworks) modifyPlan(ctx context.Context, client *Client, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to plan on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var planned types.List
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("network"), &planned)...)
+	if resp.Diagnostics.HasError() || planned.IsNull() || planned.IsUnknown() {
+		return
+	}
+
+	prior := map[string]ServerNetworkModel{}
+	if !req.State.Raw.IsNull() {
+		var state types.List
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("network"), &state)...)
+
+		var networks []ServerNetworkModel
+		resp.Diagnostics.Append(state.ElementsAs(ctx, &networks, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		for _, network := range networks {
+			prior[network.Uuid.ValueString()] = network
+		}
+	}
+
+	var networks []ServerNetworkModel
+	resp.Diagnostics.Append(planned.ElementsAs(ctx, &networks, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for i := range networks {
+		network := &networks[i]
+		network.Primary = types.BoolValue(i == 0)
+
+		if p, ok := prior[network.Uuid.ValueString()]; ok && !network.Uuid.IsUnknown() {
+			network.Port = p.Port
+			network.Mac = p.Mac
+		} else {
+			network.Port = types.StringUnknown()
+			network.Mac = types.StringUnknown()
+		}
+	}
+
+	list, diags := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: serverNetworkModelTypeMap}, networks)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("network"), list)...)
+}
+
+func (r ResourceServerNetworks) create(ctx context.Context, client *Client, data *ResourceServerNetworksModel) (string, error) {
+	body, diags := data.toAPIModel(ctx)
+	if err := errorFromDiags(diags); err != nil {
+		return "", err
+	}
+
+	// Attaching networks is asynchronous, the backend answers with an operation
+	op, err := client.StartOperation(ctx, http.MethodPost, "/server_networks", body)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := client.WaitForOperation(ctx, op.URL); err != nil {
+		return createdResourceID(op, err), err
+	}
+
+	var created serverNetworksAPIModel
+	err = client.Do(ctx, http.MethodGet, "/server_networks/"+url.PathEscape(op.ResourceId), nil, &created)
+	if err != nil {
+		return "", fmt.Errorf("read created server_networks: %w", err)
+	}
+
+	return created.Id, errorFromDiags(data.fromAPIModel(ctx, created))
+}
+
+func (r ResourceServerNetworks) read(ctx context.Context, client *Client, id string, data *ResourceServerNetworksModel) error {
+	var current serverNetworksAPIModel
+	if err := client.Do(ctx, http.MethodGet, "/server_networks/"+url.PathEscape(id), nil, &current); err != nil {
+		return err
+	}
+
+	data.Tags = client.refreshTags(data.Tags, current.Tags)
+
+	return errorFromDiags(data.fromAPIModel(ctx, current))
+}
+
+func (r ResourceServerNetworks) update(ctx context.Context, client *Client, id string, data *ResourceServerNetworksModel) error {
+	body, diags := data.toAPIModel(ctx)
+	if err := errorFromDiags(diags); err != nil {
+		return err
+	}
+
+	var updated serverNetworksAPIModel
+	if err := client.Do(ctx, http.MethodPut, "/server_networks/"+url.PathEscape(id), body, &updated); err != nil {
+		return err
+	}
+
+	return errorFromDiags(data.fromAPIModel(ctx, updated))
+}
+
+func (r ResourceServerNetworks) delete(ctx context.Context, client *Client, id string, data *ResourceServerNetworksModel) error {
+	return client.Do(ctx, http.MethodDelete, "/server_networks/"+url.PathEscape(id), nil, nil)
+}
+
+// toAPIModel builds the request body. Networks keep the order of the
+// configuration, security groups are sent in the order of Elements() so that
+// indexes in backend field errors can be mapped back to set elements.
+func (s *ResourceServerNetworksModel) toAPIModel(ctx context.Context) (serverNetworksAPIModel, diag.Diagnostics) {
+	m := serverNetworksAPIModel{
+		Id:             s.Id.ValueString(),
+		Networks:       make([]networkAPIModel, 0, len(s.Network.Elements())),
+		SecurityGroups: make([]string, 0, len(s.SecurityGroup.Elements())),
+		Tags:           expandTags(s.TagsAll),
+	}
+
+	var networks []ServerNetworkModel
+	diags := s.Network.ElementsAs(ctx, &networks, false)
+	if diags.HasError() {
+		return m, diags
+	}
+
+	for _, network := range networks {
+		m.Networks = append(m.Networks, networkAPIModel{
+			Uuid:    network.Uuid.ValueString(),
+			FixedIp: network.FixedIp.ValueString(),
+		})
+	}
+
+	var groups []SecurityGroupModel
+	diags.Append(s.SecurityGroup.ElementsAs(ctx, &groups, false)...)
+	if diags.HasError() {
+		return m, diags
+	}
+
+	for _, group := range groups {
+		m.SecurityGroups = append(m.SecurityGroups, group.Id.ValueString())
+	}
+
+	return m, diags
+}
+
+// fromAPIModel copies the backend object into the model.
+func (s *ResourceServerNetworksModel) fromAPIModel(ctx context.Context, m serverNetworksAPIModel) diag.Diagnostics {
+	s.TagsAll = flattenTags(m.Tags)
+
+	networks := make([]ServerNetworkModel, 0, len(m.Networks))
+	for _, network := range m.Networks {
+		fixedIp := types.StringNull()
+		if network.FixedIp != "" {
+			fixedIp = types.StringValue(network.FixedIp)
+		}
+
+		networks = append(networks, ServerNetworkModel{
+			Uuid:    types.StringValue(network.Uuid),
+			FixedIp: fixedIp,
+			Port:    types.StringValue(network.Port),
+			Mac:     types.StringValue(network.Mac),
+			Primary: types.BoolValue(network.Primary),
+		})
+	}
+
+	list, diags := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: serverNetworkModelTypeMap}, networks)
+	if diags.HasError() {
+		return diags
+	}
+
+	groups := make([]SecurityGroupModel, 0, len(m.SecurityGroups))
+	for _, id := range m.SecurityGroups {
+		groups = append(groups, SecurityGroupModel{Id: types.StringValue(id)})
+	}
+
+	set, d := types.SetValueFrom(ctx, types.ObjectType{AttrTypes: securityGroupModelTypeMap}, groups)
+	diags.Append(d...)
+	if diags.HasError() {
+		return diags
+	}
+
+	s.Network = list
+	s.SecurityGroup = set
+
+	return diags
+}
@@ -0,0 +1,302 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func testNetwork(uuid, fixedIp string) map[string]interface{} {
+	network := map[string]interface{}{"uuid": uuid}
+	if fixedIp != "" {
+		network["fixed_ip"] = fixedIp
+	}
+
+	return network
+}
+
+func TestProtocolResourceServerNetworks(t *testing.T) {
+	h := newProtocolHarness(t, map[string]interface{}{"endpoint": newLocalTestServer(t)})
+
+	state := h.create("example_server_networks", map[string]interface{}{
+		"network": []interface{}{
+			testNetwork("net-1", "10.0.0.10"),
+			testNetwork("net-2", ""),
+		},
+		"security_group": []interface{}{
+			map[string]interface{}{"id": "sg-1"},
+			map[string]interface{}{"id": "sg-2"},
+		},
+	})
+
+	if id, _ := state["id"].(string); !strings.HasPrefix(id, "srv-") {
+		t.Fatalf("expected id assigned by the backend, got %v", state["id"])
+	}
+
+	networks, _ := state["network"].([]interface{})
+	if len(networks) != 2 {
+		t.Fatalf("expected 2 networks, got %v", state["network"])
+	}
+
+	ports := map[string]interface{}{}
+	for i, uuid := range []string{"net-1", "net-2"} {
+		network, _ := networks[i].(map[string]interface{})
+
+		if network["uuid"] != uuid {
+			t.Errorf("expected network[%d] to be %s, got %v", i, uuid, network["uuid"])
+		}
+		if network["primary"] != (i == 0) {
+			t.Errorf("expected network[%d].primary to be %t, got %v", i, i == 0, network["primary"])
+		}
+		if network["port"] == nil || network["mac"] == nil {
+			t.Errorf("expected network[%d] to have a port and a mac, got %v", i, network)
+		}
+
+		ports[uuid] = network["port"]
+	}
+
+	if groups, _ := state["security_group"].([]interface{}); len(groups) != 2 {
+		t.Errorf("expected 2 security groups, got %v", state["security_group"])
+	}
+
+	// Reordering the blocks makes net-2 the primary NIC. The backend keeps
+	// the ports of networks that stay attached, which are planned by uuid
+	// rather than by index.
+	reordered := map[string]interface{}{
+		"network": []interface{}{
+			testNetwork("net-2", ""),
+			testNetwork("net-1", "10.0.0.10"),
+			testNetwork("net-3", ""),
+		},
+		"security_group": []interface{}{
+			map[string]interface{}{"id": "sg-2"},
+			map[string]interface{}{"id": "sg-1"},
+		},
+	}
+
+	plan := h.plan("example_server_networks", state, reordered)
+	requireNoErrors(t, "PlanResourceChange", plan.Diagnostics)
+
+	planned, _ := h.decode("example_server_networks", plan.PlannedState)["network"].([]interface{})
+	for i, expected := range []map[string]interface{}{
+		{"uuid": "net-2", "port": ports["net-2"], "primary": true},
+		{"uuid": "net-1", "port": ports["net-1"], "primary": false},
+		{"uuid": "net-3", "port": unknownValue, "mac": unknownValue, "primary": false},
+	} {
+		network, _ := planned[i].(map[string]interface{})
+		for k, v := range expected {
+			if network[k] != v {
+				t.Errorf("expected planned network[%d].%s to be %v, got %v", i, k, v, network[k])
+			}
+		}
+	}
+
+	state = h.change("example_server_networks", state, reordered)
+
+	networks, _ = state["network"].([]interface{})
+	if len(networks) != 3 {
+		t.Fatalf("expected 3 networks, got %v", state["network"])
+	}
+
+	for i, uuid := range []string{"net-2", "net-1"} {
+		network, _ := networks[i].(map[string]interface{})
+
+		if network["uuid"] != uuid {
+			t.Errorf("expected network[%d] to be %s after reordering, got %v", i, uuid, network["uuid"])
+		}
+		if network["primary"] != (i == 0) {
+			t.Errorf("expected network[%d].primary to be %t after reordering, got %v", i, i == 0, network["primary"])
+		}
+		if network["port"] != ports[uuid] {
+			t.Errorf("expected %s to keep port %v, got %v", uuid, ports[uuid], network["port"])
+		}
+	}
+
+	read := h.read("example_server_networks", state)
+	requireNoErrors(t, "ReadResource", read.Diagnostics)
+
+	if got := h.decode("example_server_networks", read.NewState); !knownValuesMatch(state, got) {
+		t.Errorf("expected %v after read, got %v", state, got)
+	}
+
+	// The backend rejects a network attached twice on the second block
+	config := map[string]interface{}{
+		"network": []interface{}{
+			testNetwork("net-2", ""),
+			testNetwork("net-2", ""),
+		},
+		"security_group": []interface{}{},
+	}
+
+	plan = h.plan("example_server_networks", state, config)
+	requireNoErrors(t, "PlanResourceChange", plan.Diagnostics)
+
+	apply := h.apply("example_server_networks", state, config, plan)
+	requireError(t, "ApplyResourceChange", apply.Diagnostics, "is already attached by network[0]")
+
+	expected := tftypes.NewAttributePath().WithAttributeName("network").WithElementKeyInt(1).WithAttributeName("uuid")
+	if d := apply.Diagnostics[0]; d.Attribute == nil || !d.Attribute.Equal(expected) {
+		t.Errorf("expected diagnostic at %s, got %s", expected, formatDiagnostics(apply.Diagnostics))
+	}
+
+	h.destroy("example_server_networks", state)
+}
+
+func TestProtocolResourceServerNetworks_Validate(t *testing.T) {
+	h := newProtocolHarness(t, nil)
+
+	tooMany := make([]interface{}, 9)
+	for i := range tooMany {
+		tooMany[i] = testNetwork(fmt.Sprintf("net-%d", i), "")
+	}
+
+	groups := make([]interface{}, 6)
+	for i := range groups {
+		groups[i] = map[string]interface{}{"id": fmt.Sprintf("sg-%d", i)}
+	}
+
+	testCases := map[string]struct {
+		config   map[string]interface{}
+		expected string
+	}{
+		"no-network": {
+			config: map[string]interface{}{
+				"network":        []interface{}{},
+				"security_group": []interface{}{},
+			},
+			expected: "must have a configuration value",
+		},
+		"too-many-networks": {
+			config: map[string]interface{}{
+				"network":        tooMany,
+				"security_group": []interface{}{},
+			},
+			expected: "must contain at least 1 elements and at most 8 elements",
+		},
+		"too-many-security-groups": {
+			config: map[string]interface{}{
+				"network":        []interface{}{testNetwork("net-1", "")},
+				"security_group": groups,
+			},
+			expected: "must contain at most 5 elements",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			requireError(t, "ValidateResourceConfig", h.validate("example_server_networks", tc.config), tc.expected)
+		})
+	}
+}
+
+// TestAccResourceServerNetworks_Ordering manages the same networks with the
+// block form and the attribute form: reordering network blocks changes the
+// primary NIC, reordering set_nested elements is not a change at all.
+func TestAccResourceServerNetworks_Ordering(t *testing.T) {
+	endpoint := newTestServer(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccResourceServerNetworksConfig(endpoint, "net-1", "net-2"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("example_server_networks.test", plancheck.ResourceActionCreate),
+						plancheck.ExpectResourceAction("example_set_nested.test", plancheck.ResourceActionCreate),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("example_server_networks.test", tfjsonpath.New("id"), knownvalue.StringRegexp(regexp.MustCompile(`^srv-[0-9a-f]{16}$`))),
+					statecheck.ExpectKnownValue("example_server_networks.test", tfjsonpath.New("network"), knownvalue.ListExact([]knownvalue.Check{
+						knownvalue.ObjectPartial(map[string]knownvalue.Check{
+							"uuid":    knownvalue.StringExact("net-1"),
+							"primary": knownvalue.Bool(true),
+							"port":    knownvalue.StringRegexp(regexp.MustCompile(`^port-`)),
+						}),
+						knownvalue.ObjectPartial(map[string]knownvalue.Check{
+							"uuid":    knownvalue.StringExact("net-2"),
+							"primary": knownvalue.Bool(false),
+							"port":    knownvalue.StringRegexp(regexp.MustCompile(`^port-`)),
+						}),
+					})),
+					statecheck.ExpectKnownValue("example_server_networks.test", tfjsonpath.New("security_group"), knownvalue.SetExact([]knownvalue.Check{
+						knownvalue.ObjectExact(map[string]knownvalue.Check{"id": knownvalue.StringExact("sg-1")}),
+					})),
+				},
+			},
+			// ImportState testing
+			{
+				ResourceName:      "example_server_networks.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Reordering
+			{
+				Config: testAccResourceServerNetworksConfig(endpoint, "net-2", "net-1"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("example_server_networks.test", plancheck.ResourceActionUpdate),
+						plancheck.ExpectResourceAction("example_set_nested.test", plancheck.ResourceActionNoop),
+						// Ports are kept by uuid, not by index.
+						plancheck.ExpectKnownValue("example_server_networks.test", tfjsonpath.New("network").AtSliceIndex(0).AtMapKey("port"), knownvalue.StringRegexp(regexp.MustCompile(`^port-`))),
+						plancheck.ExpectKnownValue("example_server_networks.test", tfjsonpath.New("network").AtSliceIndex(0).AtMapKey("primary"), knownvalue.Bool(true)),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("example_server_networks.test", tfjsonpath.New("network").AtSliceIndex(0).AtMapKey("uuid"), knownvalue.StringExact("net-2")),
+					statecheck.ExpectKnownValue("example_server_networks.test", tfjsonpath.New("network").AtSliceIndex(0).AtMapKey("primary"), knownvalue.Bool(true)),
+				},
+			},
+			// Validation
+			{
+				Config:      testAccResourceServerNetworksConfig(endpoint),
+				ExpectError: regexp.MustCompile(`must have a configuration value`),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccResourceServerNetworksConfig(endpoint string, uuids ...string) string {
+	var networks, nics strings.Builder
+	for _, uuid := range uuids {
+		fmt.Fprintf(&networks, `
+  network {
+    uuid = %[1]q
+  }
+`, uuid)
+		fmt.Fprintf(&nics, `
+    {
+      uuid           = %[1]q
+      enable_gateway = false
+    },`, uuid)
+	}
+
+	return testAccProviderConfig(endpoint) + fmt.Sprintf(`
+resource "example_server_networks" "test" {
+%[1]s
+  security_group {
+    id = "sg-1"
+  }
+}
+
+resource "example_set_nested" "test" {
+  set_nested = [%[2]s
+  ]
+}
+`, networks.String(), nics.String())
+}
@@ -5,36 +5,30 @@ package provider
 
 import (
 	"context"
-	"fmt"
+
 	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
-	"log"
-	"net/http"
-
-	"github.com/hashicorp/terraform-plugin-framework/diag"
-	"github.com/hashicorp/terraform-plugin-framework/resource"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
-// Ensure provider defined types fully satisfy framework interfaces.
-var _ resource.Resource = &ResourceSetList{}
-
 func NewResourceSetListList() resource.Resource {
-	return &ResourceSetList{}
+	return newCRUDResource[ResourceSetListModel]("set_list", ResourceSetList{})
 }
 
 // ResourceSetList defines the resource implementation.
-type ResourceSetList struct {
-	client *http.Client
-}
+type ResourceSetList struct{}
+
+var _ crudOperations[ResourceSetListModel] = ResourceSetList{}
 
 // ResourceSetListModel describes the resource data model.
 type (
@@ -42,15 +36,13 @@ type (
 		Id       types.String `tfsdk:"id"`
 		TestSet  types.Set    `tfsdk:"test_set"`
 		TestList types.List   `tfsdk:"test_list"`
+		Tags     types.Map    `tfsdk:"tags"`
+		TagsAll  types.Map    `tfsdk:"tags_all"`
 	}
 )
 
-func (r *ResourceSetList) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
-	resp.TypeName = req.ProviderTypeName + "_set_list"
-}
-
-func (r *ResourceSetList) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
-	resp.Schema = schema.Schema{
+func (r ResourceSetList) schema(ctx context.Context) schema.Schema {
+	return schema.Schema{
 		// This description is used by the documentation generator and the language server.
 		MarkdownDescription: "Set List Example resource",
 
@@ -94,114 +86,39 @@ func (r *ResourceSetList) Schema(ctx context.Context, req resource.SchemaRequest
 					),
 				},
 			},
+			"tags":     tagsAttribute(),
+			"tags_all": tagsAllAttribute(),
 		},
 	}
 }
 
-func (r *ResourceSetList) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
-	// Prevent panic if the provider has not been configured.
-	if req.ProviderData == nil {
-		return
-	}
-
-	client, ok := req.ProviderData.(*http.Client)
-
-	if !ok {
-		resp.Diagnostics.AddError(
-			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *http.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
-		)
-
-		return
-	}
-
-	r.client = client
-}
-
-func (r *ResourceSetList) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
-	var data ResourceSetListModel
-
-	// Read Terraform plan data into the model
-	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	data.Id = types.StringValue("example-id")
-	diags := data.fnConvert(ctx)
-
-	resp.Diagnostics.Append(diags...)
-	if resp.Diagnostics.HasError() {
-		return
+func (r ResourceSetList) create(ctx context.Context, client *Client, data *ResourceSetListModel) (string, error) {
+	if err := errorFromDiags(data.fnConvert(ctx)); err != nil {
+		return "", err
 	}
 
-	// Write logs using the tflog package
-	// Documentation: https://terraform.io/plugin/log
-	tflog.Trace(ctx, "created a resource")
-
-	// Save data into Terraform state
-	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	return "example-id", nil
 }
 
-func (r *ResourceSetList) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
-	var data ResourceSetListModel
-
-	// Read Terraform prior state data into the model
-	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
-
-	if resp.Diagnostics.HasError() {
-		return
+func (r ResourceSetList) read(ctx context.Context, client *Client, id string, data *ResourceSetListModel) error {
+	if err := errorFromDiags(data.fnConvert(ctx)); err != nil {
+		return err
 	}
 
-	diags := data.fnConvert(ctx)
-
-	resp.Diagnostics.Append(diags...)
-	if resp.Diagnostics.HasError() {
-		return
+	// A resource imported by id has no tags_all yet
+	if data.TagsAll.IsNull() {
+		data.TagsAll = client.mergeTags(data.Tags)
 	}
 
-	// Save updated data into Terraform state
-	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	return nil
 }
 
-func (r *ResourceSetList) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var data ResourceSetListModel
-
-	// Read Terraform plan data into the model
-	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
-
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	diags := data.fnConvert(ctx)
-
-	resp.Diagnostics.Append(diags...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	// Save updated data into Terraform state
-	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+func (r ResourceSetList) update(ctx context.Context, client *Client, id string, data *ResourceSetListModel) error {
+	return errorFromDiags(data.fnConvert(ctx))
 }
 
-func (r *ResourceSetList) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	var data ResourceSetListModel
-
-	// Read Terraform prior state data into the model
-	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
-
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	// If applicable, this is a great opportunity to initialize any necessary
-	// provider client data and make a call using it.
-	// httpResp, err := r.client.Do(httpReq)
-	// if err != nil {
-	//     resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete example, got error: %s", err))
-	//     return
-	// }
+func (r ResourceSetList) delete(ctx context.Context, client *Client, id string, data *ResourceSetListModel) error {
+	return nil
 }
 
 func (s *ResourceSetListModel) fnConvert(ctx context.Context) diag.Diagnostics {
@@ -217,19 +134,17 @@ func (s *ResourceSetListModel) fnConvert(ctx context.Context) diag.Diagnostics {
 	}
 
 	for _, tsElement := range setElements {
-		log.Printf("before set element: %s", tsElement.ValueString())
-
-		if !tsElement.IsNull() && !tsElement.IsUnknown() {
-			log.Printf("set element: %s", tsElement.ValueString())
-
-			if "abc" == tsElement.ValueString() {
-				log.Printf("set element == abc")
-			} else {
-				log.Printf("set element != abc")
-			}
+		if tsElement.IsNull() || tsElement.IsUnknown() {
+			tflog.Debug(ctx, "test_set element has no known value", map[string]interface{}{
+				"element": tsElement.String(),
+			})
+			continue
 		}
 
-		log.Printf("after set element: %s", tsElement.ValueString())
+		tflog.Debug(ctx, "test_set element", map[string]interface{}{
+			"value":  tsElement.ValueString(),
+			"is_abc": tsElement.ValueString() == "abc",
+		})
 	}
 
 	// list ---------------
@@ -242,9 +157,12 @@ func (s *ResourceSetListModel) fnConvert(ctx context.Context) diag.Diagnostics {
 		return diags
 	}
 
-	for _, tlElement := range listElements {
+	for i, tlElement := range listElements {
 		if !tlElement.IsNull() && !tlElement.IsUnknown() {
-			log.Printf("list element: %s", tlElement.ValueString())
+			tflog.Debug(ctx, "test_list element", map[string]interface{}{
+				"index": i,
+				"value": tlElement.ValueString(),
+			})
 		}
 	}
 
@@ -0,0 +1,130 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestProtocolResourceSetList(t *testing.T) {
+	h := newProtocolHarness(t, nil)
+
+	state := h.create("example_set_list", map[string]interface{}{
+		"test_set":  []interface{}{"abc", "def"},
+		"test_list": []interface{}{"a", "b"},
+	})
+
+	if state["id"] != "example-id" {
+		t.Errorf("expected id example-id, got %v", state["id"])
+	}
+
+	state = h.change("example_set_list", state, map[string]interface{}{
+		"test_set":  []interface{}{"def"},
+		"test_list": []interface{}{"b", "a"},
+	})
+
+	resp := h.read("example_set_list", state)
+	requireNoErrors(t, "ReadResource", resp.Diagnostics)
+
+	if got := h.decode("example_set_list", resp.NewState); !knownValuesMatch(state, got) {
+		t.Errorf("expected %v after read, got %v", state, got)
+	}
+
+	requireError(t, "ValidateResourceConfig", h.validate("example_set_list", map[string]interface{}{
+		"test_set":  []interface{}{strings.Repeat("a", 256)},
+		"test_list": []interface{}{},
+	}), "Invalid Attribute Value Length")
+
+	h.destroy("example_set_list", state)
+}
+
+func TestAccResourceSetList(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccResourceSetListConfig(`["abc", "bcd", "cde"]`, `["def", "efg", "fgh"]`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("example_set_list.test", plancheck.ResourceActionCreate),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("example_set_list.test", tfjsonpath.New("id"), knownvalue.StringExact("example-id")),
+					statecheck.ExpectKnownValue("example_set_list.test", tfjsonpath.New("test_set"), knownvalue.SetExact([]knownvalue.Check{
+						knownvalue.StringExact("cde"),
+						knownvalue.StringExact("abc"),
+						knownvalue.StringExact("bcd"),
+					})),
+					statecheck.ExpectKnownValue("example_set_list.test", tfjsonpath.New("test_list"), knownvalue.ListExact([]knownvalue.Check{
+						knownvalue.StringExact("def"),
+						knownvalue.StringExact("efg"),
+						knownvalue.StringExact("fgh"),
+					})),
+				},
+			},
+			// Reordering a set is not a change
+			{
+				Config: testAccResourceSetListConfig(`["cde", "bcd", "abc"]`, `["def", "efg", "fgh"]`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+			// Reordering a list is
+			{
+				Config: testAccResourceSetListConfig(`["cde", "bcd", "abc"]`, `["fgh", "efg", "def"]`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("example_set_list.test", plancheck.ResourceActionUpdate),
+					},
+				},
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccResourceSetList_Validation(t *testing.T) {
+	tooMany := make([]string, 17)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("%q", fmt.Sprintf("item-%d", i))
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccResourceSetListConfig(fmt.Sprintf("[%q]", strings.Repeat("a", 256)), "[]"),
+				ExpectError: regexp.MustCompile(`Invalid Attribute Value Length`),
+			},
+			{
+				Config:      testAccResourceSetListConfig("[]", "["+strings.Join(tooMany, ", ")+"]"),
+				ExpectError: regexp.MustCompile(`Invalid Attribute Value`),
+			},
+		},
+	})
+}
+
+func testAccResourceSetListConfig(testSet, testList string) string {
+	return fmt.Sprintf(`
+resource "example_set_list" "test" {
+  test_set  = %[1]s
+  test_list = %[2]s
+}
+`, testSet, testList)
+}
@@ -0,0 +1,289 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Code generated by resourcegen from ../terraform-service/server/openapi.json. DO NOT EDIT.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func NewResourceSetNested() resource.Resource {
+	return newCRUDResource[ResourceSetNestedModel]("set_nested", ResourceSetNested{})
+}
+
+func NewListResourceSetNested() list.ListResource {
+	return newCRUDListResource[ResourceSetNestedModel]("set_nested", ResourceSetNested{})
+}
+
+// ResourceSetNested implements the schema and the backend calls of the
+// resource.
+type ResourceSetNested struct{}
+
+var _ crudOperations[ResourceSetNestedModel] = ResourceSetNested{}
+var _ crudLister[ResourceSetNestedModel] = ResourceSetNested{}
+
+type (
+	// ResourceSetNestedModel describes the resource data model.
+	ResourceSetNestedModel struct {
+		Id        types.String   `tfsdk:"id"`
+		Project   types.String   `tfsdk:"project"`
+		SetNested types.Set      `tfsdk:"set_nested"`
+		Tags      types.Map      `tfsdk:"tags"`
+		TagsAll   types.Map      `tfsdk:"tags_all"`
+		Timeouts  timeouts.Value `tfsdk:"timeouts"`
+	}
+
+	NicModel struct {
+		Uuid          types.String `tfsdk:"uuid"`
+		FixedIp       types.String `tfsdk:"fixed_ip"`
+		FixedIpV4     types.String `tfsdk:"fixed_ip_v4"`
+		Port          types.String `tfsdk:"port"`
+		Mac           types.String `tfsdk:"mac"`
+		EnableGateway types.Bool   `tfsdk:"enable_gateway"`
+	}
+
+	// setNestedAPIModel is the terraform-service representation of the resource.
+	setNestedAPIModel struct {
+		Id        string            `json:"id,omitempty"`
+		SetNested []nicAPIModel     `json:"set_nested,omitempty"`
+		Tags      map[string]string `json:"tags,omitempty"`
+	}
+
+	nicAPIModel struct {
+		Uuid          string  `json:"uuid"`
+		FixedIp       *string `json:"fixed_ip,omitempty"`
+		EnableGateway *bool   `json:"enable_gateway,omitempty"`
+	}
+)
+
+var nicModelTypeMap = map[string]attr.Type{
+	"uuid":           types.StringType,
+	"fixed_ip":       types.StringType,
+	"fixed_ip_v4":    types.StringType,
+	"port":           types.StringType,
+	"mac":            types.StringType,
+	"enable_gateway": types.BoolType,
+}
+
+func (r ResourceSetNested) schema(ctx context.Context) schema.Schema {
+	return schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "网卡挂载",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "ID",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"set_nested": schema.SetNestedAttribute{
+				MarkdownDescription: "网卡",
+				Optional:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"uuid": schema.StringAttribute{
+							MarkdownDescription: "经典网络ID",
+							Required:            true,
+						},
+						"fixed_ip": schema.StringAttribute{
+							MarkdownDescription: "指定IP地址",
+							Optional:            true,
+						},
+						"fixed_ip_v4": schema.StringAttribute{
+							MarkdownDescription: "指定IPv4地址",
+							Computed:            true,
+						},
+						"port": schema.StringAttribute{
+							MarkdownDescription: "网卡端口ID",
+							Computed:            true,
+						},
+						"mac": schema.StringAttribute{
+							MarkdownDescription: "MAC地址",
+							Computed:            true,
+						},
+						"enable_gateway": schema.BoolAttribute{
+							MarkdownDescription: "是否启用网关",
+							Optional:            true,
+							Computed:            true,
+							Default:             booldefault.StaticBool(false),
+						},
+					},
+				},
+			},
+			"project":  projectAttribute(),
+			"tags":     tagsAttribute(),
+			"tags_all": tagsAllAttribute(),
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+			}),
+		},
+	}
+}
+
+func (r ResourceSetNested) create(ctx context.Context, client *Client, data *ResourceSetNestedModel) (string, error) {
+	body, diags := data.toAPIModel(ctx)
+	if err := errorFromDiags(diags); err != nil {
+		return "", err
+	}
+
+	var created setNestedAPIModel
+
+	// Creating the object is asynchronous, the backend answers with an operation
+	op, err := client.StartOperation(ctx, http.MethodPost, "/set_nested", body)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := client.WaitForOperation(ctx, op.URL); err != nil {
+		return createdResourceID(op, err), err
+	}
+
+	err = client.Do(ctx, http.MethodGet, "/set_nested/"+url.PathEscape(op.ResourceId), nil, &created)
+	if err != nil {
+		return "", fmt.Errorf("read created set_nested: %w", err)
+	}
+
+	return created.Id, errorFromDiags(data.fromAPIModel(ctx, created))
+}
+
+func (r ResourceSetNested) read(ctx context.Context, client *Client, id string, data *ResourceSetNestedModel) error {
+	var current setNestedAPIModel
+	if err := client.Do(ctx, http.MethodGet, "/set_nested/"+url.PathEscape(id), nil, &current); err != nil {
+		return err
+	}
+
+	data.Tags = client.refreshTags(data.Tags, current.Tags)
+
+	return errorFromDiags(data.fromAPIModel(ctx, current))
+}
+
+func (r ResourceSetNested) update(ctx context.Context, client *Client, id string, data *ResourceSetNestedModel) error {
+	body, diags := data.toAPIModel(ctx)
+	if err := errorFromDiags(diags); err != nil {
+		return err
+	}
+
+	var updated setNestedAPIModel
+	if err := client.Do(ctx, http.MethodPut, "/set_nested/"+url.PathEscape(id), body, &updated); err != nil {
+		return err
+	}
+
+	return errorFromDiags(data.fromAPIModel(ctx, updated))
+}
+
+func (r ResourceSetNested) delete(ctx context.Context, client *Client, id string, data *ResourceSetNestedModel) error {
+	return client.Do(ctx, http.MethodDelete, "/set_nested/"+url.PathEscape(id), nil, nil)
+}
+
+func (r ResourceSetNested) list(ctx context.Context, client *Client, query url.Values, push func(id, displayName string, fill func(data *ResourceSetNestedModel) error) bool) error {
+	path := "/set_nested"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	var objects []setNestedAPIModel
+	if err := client.Do(ctx, http.MethodGet, path, nil, &objects); err != nil {
+		return err
+	}
+
+	for _, current := range objects {
+		fill := func(data *ResourceSetNestedModel) error {
+			data.Tags = client.refreshTags(data.Tags, current.Tags)
+			return errorFromDiags(data.fromAPIModel(ctx, current))
+		}
+
+		if !push(current.Id, current.Id, fill) {
+			break
+		}
+	}
+
+	return nil
+}
+
+func (s *ResourceSetNestedModel) toAPIModel(ctx context.Context) (setNestedAPIModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	m := setNestedAPIModel{
+		Id:   s.Id.ValueString(),
+		Tags: expandTags(s.TagsAll),
+	}
+	var setNested []NicModel
+	diags.Append(s.SetNested.ElementsAs(ctx, &setNested, false)...)
+	for _, e := range setNested {
+		v, d := e.toAPIModel(ctx)
+		diags.Append(d...)
+		m.SetNested = append(m.SetNested, v)
+	}
+
+	return m, diags
+}
+
+// fromAPIModel copies the backend object into the model, then runs the
+// customizeState hook if the resource has one.
+func (s *ResourceSetNestedModel) fromAPIModel(ctx context.Context, m setNestedAPIModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if len(m.SetNested) == 0 && (s.SetNested.IsNull() || s.SetNested.IsUnknown()) {
+		s.SetNested = types.SetNull(types.ObjectType{AttrTypes: nicModelTypeMap})
+	} else {
+		setNested := make([]NicModel, len(m.SetNested))
+		for i := range m.SetNested {
+			diags.Append(setNested[i].fromAPIModel(ctx, m.SetNested[i])...)
+		}
+
+		v, d := types.SetValueFrom(ctx, types.ObjectType{AttrTypes: nicModelTypeMap}, setNested)
+		diags.Append(d...)
+		s.SetNested = v
+	}
+	s.TagsAll = flattenTags(m.Tags)
+
+	if c, ok := interface{}(s).(stateCustomizer); ok && !diags.HasError() {
+		diags.Append(c.customizeState(ctx)...)
+	}
+
+	return diags
+}
+
+func (s *NicModel) toAPIModel(ctx context.Context) (nicAPIModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	var m nicAPIModel
+	m.Uuid = s.Uuid.ValueString()
+	m.FixedIp = s.FixedIp.ValueStringPointer()
+	m.EnableGateway = s.EnableGateway.ValueBoolPointer()
+
+	return m, diags
+}
+
+func (s *NicModel) fromAPIModel(ctx context.Context, m nicAPIModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	s.Uuid = types.StringValue(m.Uuid)
+	s.FixedIp = types.StringPointerValue(m.FixedIp)
+	s.FixedIpV4 = types.StringNull()
+	s.Port = types.StringNull()
+	s.Mac = types.StringNull()
+	s.EnableGateway = types.BoolPointerValue(m.EnableGateway)
+
+	return diags
+}
@@ -0,0 +1,242 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestProtocolResourceSetNested_Plan(t *testing.T) {
+	h := newProtocolHarness(t, nil)
+
+	// enable_gateway is configured on purpose: when its default rewrites a
+	// set element the framework can no longer match the element against the
+	// configuration and leaves the computed attributes null instead of
+	// unknown.
+	plan := h.plan("example_set_nested", nil, map[string]interface{}{
+		"set_nested": []interface{}{
+			map[string]interface{}{"uuid": "net-1", "fixed_ip": "10.0.0.10", "enable_gateway": false},
+			map[string]interface{}{"uuid": "net-2", "enable_gateway": true},
+		},
+	})
+	requireNoErrors(t, "PlanResourceChange", plan.Diagnostics)
+
+	planned := h.decode("example_set_nested", plan.PlannedState)
+	nics, _ := planned["set_nested"].([]interface{})
+	if len(nics) != 2 {
+		t.Fatalf("expected 2 planned nics, got %v", planned["set_nested"])
+	}
+
+	for _, v := range nics {
+		nic, _ := v.(map[string]interface{})
+
+		for _, attr := range []string{"fixed_ip_v4", "port", "mac"} {
+			if nic[attr] != unknownValue {
+				t.Errorf("expected %s of %v to be unknown, got %v", attr, nic["uuid"], nic[attr])
+			}
+		}
+
+		switch nic["uuid"] {
+		case "net-1":
+			if nic["fixed_ip"] != "10.0.0.10" {
+				t.Errorf("expected configured fixed_ip, got %v", nic["fixed_ip"])
+			}
+			if nic["enable_gateway"] != false {
+				t.Errorf("expected enable_gateway false, got %v", nic["enable_gateway"])
+			}
+		case "net-2":
+			if nic["fixed_ip"] != nil {
+				t.Errorf("expected unconfigured fixed_ip to stay null, got %v", nic["fixed_ip"])
+			}
+			if nic["enable_gateway"] != true {
+				t.Errorf("expected enable_gateway true, got %v", nic["enable_gateway"])
+			}
+		}
+	}
+}
+
+func TestProtocolResourceSetNested(t *testing.T) {
+	h := newProtocolHarness(t, map[string]interface{}{"endpoint": newLocalTestServer(t)})
+
+	state := h.create("example_set_nested", map[string]interface{}{
+		"set_nested": []interface{}{
+			map[string]interface{}{"uuid": "net-1", "fixed_ip": "10.0.0.10", "enable_gateway": true},
+			map[string]interface{}{"uuid": "net-2", "enable_gateway": false},
+		},
+	})
+
+	id, _ := state["id"].(string)
+	if !strings.HasPrefix(id, "nic-") {
+		t.Fatalf("expected id assigned by the backend, got %v", state["id"])
+	}
+
+	read := h.read("example_set_nested", state)
+	requireNoErrors(t, "ReadResource", read.Diagnostics)
+
+	if got := h.decode("example_set_nested", read.NewState); !knownValuesMatch(state, got) {
+		t.Errorf("expected %v after read, got %v", state, got)
+	}
+
+	// NICs without fixed_ip only get a computed fixed_ip_v4, fixed_ip is sent
+	// back unset when the resource is updated in place.
+	nics, _ := state["set_nested"].([]interface{})
+	for _, v := range nics {
+		if nic, _ := v.(map[string]interface{}); nic["uuid"] == "net-2" && (nic["fixed_ip"] != nil || nic["fixed_ip_v4"] == nil) {
+			t.Errorf("expected null fixed_ip and computed fixed_ip_v4, got %v", nic)
+		}
+	}
+
+	state = h.change("example_set_nested", state, map[string]interface{}{
+		"set_nested": []interface{}{
+			map[string]interface{}{"uuid": "net-1", "fixed_ip": "10.0.0.10", "enable_gateway": true},
+			map[string]interface{}{"uuid": "net-2", "enable_gateway": false},
+		},
+		"tags": map[string]interface{}{"env": "test"},
+	})
+
+	if tags, _ := state["tags"].(map[string]interface{}); state["id"] != id || tags["env"] != "test" {
+		t.Errorf("expected in-place update of the tags of %s, got %v", id, state)
+	}
+
+	state = h.change("example_set_nested", state, map[string]interface{}{
+		"set_nested": []interface{}{
+			map[string]interface{}{"uuid": "net-2", "fixed_ip": "10.0.0.20", "enable_gateway": false},
+		},
+	})
+
+	if nics, _ := state["set_nested"].([]interface{}); state["id"] != id || len(nics) != 1 {
+		t.Errorf("expected in-place update of %s to a single nic, got %v", id, state)
+	}
+
+	// An invalid address is reported on the nic that carries it.
+	config := map[string]interface{}{
+		"set_nested": []interface{}{
+			map[string]interface{}{"uuid": "net-2", "fixed_ip": "10.0.0.256", "enable_gateway": false},
+		},
+	}
+
+	plan := h.plan("example_set_nested", state, config)
+	requireNoErrors(t, "PlanResourceChange", plan.Diagnostics)
+
+	apply := h.apply("example_set_nested", state, config, plan)
+	requireError(t, "ApplyResourceChange", apply.Diagnostics, "is not a valid IP address")
+
+	if apply.Diagnostics[0].Attribute == nil {
+		t.Errorf("expected diagnostic on the fixed_ip attribute, got %s", formatDiagnostics(apply.Diagnostics))
+	}
+
+	h.destroy("example_set_nested", state)
+}
+
+func TestAccResourceSetNested(t *testing.T) {
+	endpoint := newTestServer(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccResourceSetNestedConfig(endpoint, `
+    {
+      uuid           = "net-1"
+      fixed_ip       = "10.0.0.10"
+      enable_gateway = true
+    },`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("example_set_nested.test", plancheck.ResourceActionCreate),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("example_set_nested.test", tfjsonpath.New("id"), knownvalue.StringRegexp(regexp.MustCompile(`^nic-[0-9a-f]{16}$`))),
+					statecheck.ExpectKnownValue("example_set_nested.test", tfjsonpath.New("set_nested"), knownvalue.SetExact([]knownvalue.Check{
+						knownvalue.ObjectExact(map[string]knownvalue.Check{
+							"uuid":           knownvalue.StringExact("net-1"),
+							"fixed_ip":       knownvalue.StringExact("10.0.0.10"),
+							"fixed_ip_v4":    knownvalue.StringExact("10.0.0.10"),
+							"port":           knownvalue.StringExact("port_id_0"),
+							"mac":            knownvalue.StringExact("mac_address_0"),
+							"enable_gateway": knownvalue.Bool(true),
+						}),
+					})),
+				},
+			},
+			// ImportState testing
+			{
+				ResourceName:      "example_set_nested.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Update and Read testing
+			{
+				Config: testAccResourceSetNestedConfig(endpoint, `
+    {
+      uuid           = "net-1"
+      fixed_ip       = "10.0.0.10"
+      enable_gateway = false
+    },
+    {
+      uuid           = "net-2"
+      fixed_ip       = "10.0.0.20"
+      enable_gateway = true
+    },`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("example_set_nested.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("example_set_nested.test", tfjsonpath.New("set_nested"), knownvalue.SetExact([]knownvalue.Check{
+						knownvalue.ObjectExact(map[string]knownvalue.Check{
+							"uuid":           knownvalue.StringExact("net-1"),
+							"fixed_ip":       knownvalue.StringExact("10.0.0.10"),
+							"fixed_ip_v4":    knownvalue.StringExact("10.0.0.10"),
+							"port":           knownvalue.StringRegexp(regexp.MustCompile(`^port_id_[01]$`)),
+							"mac":            knownvalue.StringRegexp(regexp.MustCompile(`^mac_address_[01]$`)),
+							"enable_gateway": knownvalue.Bool(false),
+						}),
+						knownvalue.ObjectExact(map[string]knownvalue.Check{
+							"uuid":           knownvalue.StringExact("net-2"),
+							"fixed_ip":       knownvalue.StringExact("10.0.0.20"),
+							"fixed_ip_v4":    knownvalue.StringExact("10.0.0.20"),
+							"port":           knownvalue.StringRegexp(regexp.MustCompile(`^port_id_[01]$`)),
+							"mac":            knownvalue.StringRegexp(regexp.MustCompile(`^mac_address_[01]$`)),
+							"enable_gateway": knownvalue.Bool(true),
+						}),
+					})),
+				},
+			},
+			// The backend rejects invalid addresses
+			{
+				Config: testAccResourceSetNestedConfig(endpoint, `
+    {
+      uuid           = "net-1"
+      fixed_ip       = "10.0.0.256"
+      enable_gateway = false
+    },`),
+				ExpectError: regexp.MustCompile(`is not a valid IP address`),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccResourceSetNestedConfig(endpoint, nics string) string {
+	return testAccProviderConfig(endpoint) + fmt.Sprintf(`
+resource "example_set_nested" "test" {
+  set_nested = [%[1]s
+  ]
+}
+`, nics)
+}
@@ -0,0 +1,109 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// testAccNamePrefix starts the names of the VMs created by acceptance tests,
+// and is the key of a default tag of all objects they create, so that
+// sweepers can find objects leaked by crashed runs.
+const testAccNamePrefix = "tf-acc-test"
+
+// TestMain runs the sweepers instead of the tests when go test is called with
+// -sweep, e.g. against a local terraform-service:
+//
+//	go test ./internal/provider -v -sweep=local
+//
+// The sweepers send requests to EXAMPLE_ENDPOINT and EXAMPLE_PROJECT, the
+// value of -sweep is the region of the endpoint. Acceptance tests create
+// their objects there too when EXAMPLE_ENDPOINT is set, see newTestServer.
+func TestMain(m *testing.M) {
+	resource.TestMain(m)
+}
+
+func init() {
+	resource.AddTestSweepers("example_set_nested", &resource.Sweeper{
+		Name: "example_set_nested",
+		F:    sweepTagged("/set_nested"),
+	})
+
+	resource.AddTestSweepers("example_server_networks", &resource.Sweeper{
+		Name: "example_server_networks",
+		F:    sweepTagged("/server_networks"),
+	})
+
+	resource.AddTestSweepers("example_document", &resource.Sweeper{
+		Name: "example_document",
+		F:    sweepTagged("/document"),
+	})
+
+	// NICs are detached before the VMs they belong to are deleted.
+	resource.AddTestSweepers("example_regex", &resource.Sweeper{
+		Name:         "example_regex",
+		Dependencies: []string{"example_set_nested", "example_server_networks"},
+		F:            sweepTagged("/regex"),
+	})
+}
+
+// sweeperClient returns a client of the backend sweepers clean up.
+func sweeperClient(region string) *Client {
+	endpoint := os.Getenv("EXAMPLE_ENDPOINT")
+	if endpoint == "" {
+		endpoint = defaultEndpoint
+	}
+
+	client := NewClient(endpoint, &http.Client{Transport: newRetryTransport(http.DefaultTransport, defaultMaxAttempts)})
+	client.Region = region
+	client.Project = os.Getenv("EXAMPLE_PROJECT")
+
+	return client
+}
+
+// sweepTagged returns a sweeper deleting the objects under path with the
+// testAccNamePrefix tag.
+func sweepTagged(path string) resource.SweeperFunc {
+	return func(region string) error {
+		client := sweeperClient(region)
+
+		var objects []struct {
+			Id string `json:"id"`
+		}
+		err := client.Do(context.Background(), http.MethodGet, path+"?"+url.Values{"tag": {testAccNamePrefix}}.Encode(), nil, &objects)
+		if err != nil {
+			return fmt.Errorf("listing %s: %w", path, err)
+		}
+
+		ids := make([]string, 0, len(objects))
+		for _, o := range objects {
+			ids = append(ids, o.Id)
+		}
+
+		return sweep(client, path, ids)
+	}
+}
+
+// sweep deletes the objects ids under path. Objects deleted meanwhile are
+// skipped.
+func sweep(client *Client, path string, ids []string) error {
+	for _, id := range ids {
+		log.Printf("[INFO] Deleting %s/%s", path, id)
+
+		err := client.Do(context.Background(), http.MethodDelete, path+"/"+url.PathEscape(id), nil, nil)
+		if err != nil && !isNotFound(err) {
+			return fmt.Errorf("deleting %s/%s: %w", path, id, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// tagsAttribute is the schema of the tags attribute shared by all resources.
+func tagsAttribute() schema.MapAttribute {
+	return schema.MapAttribute{
+		MarkdownDescription: "Tags of the resource. Tags with the same key as a provider `default_tags` entry override it.",
+		Optional:            true,
+		ElementType:         types.StringType,
+	}
+}
+
+// tagsAllAttribute is the schema of the tags_all attribute shared by all
+// resources.
+func tagsAllAttribute() schema.MapAttribute {
+	return schema.MapAttribute{
+		MarkdownDescription: "All tags of the resource, including those inherited from the provider `default_tags`.",
+		Computed:            true,
+		ElementType:         types.StringType,
+	}
+}
+
+// modifyPlanTags plans tags_all as the provider default_tags merged with the
+// tags of the resource. Resources call it from ModifyPlan.
+func (c *Client) modifyPlanTags(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to plan when the resource is destroyed
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var tags types.Map
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("tags"), &tags)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("tags_all"), c.mergeTags(tags))...)
+}
+
+// mergeTags returns the provider default_tags overridden by tags. The result
+// is unknown if either is, and an empty map if both are null.
+func (c *Client) mergeTags(tags types.Map) types.Map {
+	defaults := types.MapNull(types.StringType)
+	if c != nil {
+		defaults = c.DefaultTags
+	}
+
+	if defaults.IsUnknown() || tags.IsUnknown() {
+		return types.MapUnknown(types.StringType)
+	}
+
+	elems := make(map[string]attr.Value, len(defaults.Elements())+len(tags.Elements()))
+	for k, v := range defaults.Elements() {
+		elems[k] = v
+	}
+	for k, v := range tags.Elements() {
+		elems[k] = v
+	}
+
+	return types.MapValueMust(types.StringType, elems)
+}
+
+// refreshTags derives the tags of a resource from all tags the backend
+// reports for it. A tag that equals a default tag is only attributed to the
+// resource if the resource already had it, so a refresh does not move
+// defaults into the configuration.
+func (c *Client) refreshTags(prior types.Map, all map[string]string) types.Map {
+	defaults := map[string]string{}
+	if c != nil {
+		defaults = expandTags(c.DefaultTags)
+	}
+
+	priorElems := prior.Elements()
+
+	elems := make(map[string]attr.Value, len(all))
+	for k, v := range all {
+		if _, ok := priorElems[k]; !ok {
+			if d, ok := defaults[k]; ok && d == v {
+				continue
+			}
+		}
+
+		elems[k] = types.StringValue(v)
+	}
+
+	if len(elems) == 0 && prior.IsNull() {
+		return prior
+	}
+
+	return types.MapValueMust(types.StringType, elems)
+}
+
+// expandTags returns the known elements of m.
+func expandTags(m types.Map) map[string]string {
+	tags := make(map[string]string, len(m.Elements()))
+	for k, v := range m.Elements() {
+		if s, ok := v.(types.String); ok && !s.IsNull() && !s.IsUnknown() {
+			tags[k] = s.ValueString()
+		}
+	}
+
+	return tags
+}
+
+// flattenTags returns tags as a map value, empty if tags is nil.
+func flattenTags(tags map[string]string) types.Map {
+	elems := make(map[string]attr.Value, len(tags))
+	for k, v := range tags {
+		elems[k] = types.StringValue(v)
+	}
+
+	return types.MapValueMust(types.StringType, elems)
+}
@@ -0,0 +1,202 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func testTags(tags map[string]string) types.Map {
+	if tags == nil {
+		return types.MapNull(types.StringType)
+	}
+
+	return flattenTags(tags)
+}
+
+func TestMergeTags(t *testing.T) {
+	testCases := map[string]struct {
+		defaults types.Map
+		tags     types.Map
+		expected types.Map
+	}{
+		"none": {
+			defaults: testTags(nil),
+			tags:     testTags(nil),
+			expected: testTags(map[string]string{}),
+		},
+		"defaults-only": {
+			defaults: testTags(map[string]string{"owner": "team-a", "env": "dev"}),
+			tags:     testTags(nil),
+			expected: testTags(map[string]string{"owner": "team-a", "env": "dev"}),
+		},
+		"override": {
+			defaults: testTags(map[string]string{"owner": "team-a", "env": "dev"}),
+			tags:     testTags(map[string]string{"env": "prod", "cost-center": "42"}),
+			expected: testTags(map[string]string{"owner": "team-a", "env": "prod", "cost-center": "42"}),
+		},
+		"unknown-defaults": {
+			defaults: types.MapUnknown(types.StringType),
+			tags:     testTags(map[string]string{"env": "prod"}),
+			expected: types.MapUnknown(types.StringType),
+		},
+		"unknown-tags": {
+			defaults: testTags(map[string]string{"env": "dev"}),
+			tags:     types.MapUnknown(types.StringType),
+			expected: types.MapUnknown(types.StringType),
+		},
+		"unknown-element": {
+			defaults: testTags(map[string]string{"env": "dev"}),
+			tags: types.MapValueMust(types.StringType, map[string]attr.Value{
+				"owner": types.StringUnknown(),
+			}),
+			expected: types.MapValueMust(types.StringType, map[string]attr.Value{
+				"env":   types.StringValue("dev"),
+				"owner": types.StringUnknown(),
+			}),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			client := NewClient(defaultEndpoint, http.DefaultClient)
+			client.DefaultTags = tc.defaults
+
+			if got := client.mergeTags(tc.tags); !got.Equal(tc.expected) {
+				t.Errorf("expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestRefreshTags(t *testing.T) {
+	testCases := map[string]struct {
+		prior    types.Map
+		all      map[string]string
+		expected types.Map
+	}{
+		"defaults-only": {
+			prior:    testTags(nil),
+			all:      map[string]string{"owner": "team-a", "env": "dev"},
+			expected: testTags(nil),
+		},
+		"override": {
+			prior:    testTags(map[string]string{"env": "prod"}),
+			all:      map[string]string{"owner": "team-a", "env": "prod"},
+			expected: testTags(map[string]string{"env": "prod"}),
+		},
+		"same-as-default": {
+			prior:    testTags(map[string]string{"env": "dev"}),
+			all:      map[string]string{"owner": "team-a", "env": "dev"},
+			expected: testTags(map[string]string{"env": "dev"}),
+		},
+		"drift": {
+			prior:    testTags(map[string]string{"env": "prod"}),
+			all:      map[string]string{"owner": "team-b", "env": "staging", "added": "yes"},
+			expected: testTags(map[string]string{"owner": "team-b", "env": "staging", "added": "yes"}),
+		},
+		"removed": {
+			prior:    testTags(map[string]string{"env": "prod"}),
+			all:      map[string]string{"owner": "team-a"},
+			expected: testTags(map[string]string{}),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			client := NewClient(defaultEndpoint, http.DefaultClient)
+			client.DefaultTags = testTags(map[string]string{"owner": "team-a", "env": "dev"})
+
+			if got := client.refreshTags(tc.prior, tc.all); !got.Equal(tc.expected) {
+				t.Errorf("expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestProtocolDefaultTags(t *testing.T) {
+	endpoint := newLocalTestServer(t)
+	h := newProtocolHarness(t, map[string]interface{}{
+		"endpoint": endpoint,
+		"default_tags": map[string]interface{}{
+			"owner":       "team-a",
+			"env":         "dev",
+			"cost-center": "42",
+		},
+	})
+
+	state := h.create("example_regex", map[string]interface{}{
+		"name": "test01",
+		"tags": map[string]interface{}{"env": "prod"},
+	})
+
+	expected := map[string]interface{}{"owner": "team-a", "env": "prod", "cost-center": "42"}
+	if !knownValuesMatch(expected, state["tags_all"]) {
+		t.Errorf("expected tags_all %v, got %v", expected, state["tags_all"])
+	}
+
+	// The backend stores the merged tags
+	var found []regexAPIModel
+	client := NewClient(endpoint, http.DefaultClient)
+	if err := client.Do(context.Background(), http.MethodGet, "/regex?tag=owner=team-a&tag=env=prod", nil, &found); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(found) != 1 || found[0].Id != state["id"] {
+		t.Errorf("expected backend to find %v by its merged tags, got %v", state["id"], found)
+	}
+
+	// Refreshing does not move default tags into tags
+	read := h.read("example_regex", state)
+	requireNoErrors(t, "ReadResource", read.Diagnostics)
+
+	if got := h.decode("example_regex", read.NewState); !knownValuesMatch(state, got) {
+		t.Errorf("expected %v after read, got %v", state, got)
+	}
+
+	// Resources without a backend get tags_all as well
+	example := h.create("example_example", map[string]interface{}{
+		"tags": map[string]interface{}{"owner": "team-b"},
+	})
+
+	expected = map[string]interface{}{"owner": "team-b", "env": "dev", "cost-center": "42"}
+	if !knownValuesMatch(expected, example["tags_all"]) {
+		t.Errorf("expected tags_all %v, got %v", expected, example["tags_all"])
+	}
+}
+
+func TestProtocolDefaultTags_Changed(t *testing.T) {
+	h := newProtocolHarness(t, map[string]interface{}{
+		"default_tags": map[string]interface{}{"env": "prod"},
+	})
+
+	prior := map[string]interface{}{
+		"id":       "example-id",
+		"tags":     map[string]interface{}{"owner": "team-a"},
+		"tags_all": map[string]interface{}{"owner": "team-a", "env": "dev"},
+	}
+
+	plan := h.plan("example_example", prior, map[string]interface{}{
+		"tags": map[string]interface{}{"owner": "team-a"},
+	})
+	requireNoErrors(t, "PlanResourceChange", plan.Diagnostics)
+
+	expected := map[string]interface{}{"owner": "team-a", "env": "prod"}
+	if got := h.decode("example_example", plan.PlannedState)["tags_all"]; !knownValuesMatch(expected, got) {
+		t.Errorf("expected planned tags_all %v, got %v", expected, got)
+	}
+
+	plan = h.plan("example_example", prior, map[string]interface{}{
+		"tags": unknownValue,
+	})
+	requireNoErrors(t, "PlanResourceChange", plan.Diagnostics)
+
+	if got := h.decode("example_example", plan.PlannedState)["tags_all"]; got != unknownValue {
+		t.Errorf("expected unknown tags_all, got %v", got)
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// tlsOptions are the TLS settings of the provider block. Empty fields keep
+// the defaults of crypto/tls.
+type tlsOptions struct {
+	ClientCertPEM string
+	ClientKeyPEM  string
+	CACertPEM     string
+	ServerName    string
+}
+
+// isSet reports whether any TLS setting differs from the defaults.
+func (o tlsOptions) isSet() bool {
+	return o != tlsOptions{}
+}
+
+// tlsConfig builds the TLS configuration of the shared client. A CA
+// certificate replaces the system roots, a client certificate is presented
+// to backends that require one.
+func (o tlsOptions) tlsConfig() (*tls.Config, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: o.ServerName,
+	}
+
+	switch {
+	case o.ClientCertPEM != "" && o.ClientKeyPEM != "":
+		cert, err := tls.X509KeyPair([]byte(o.ClientCertPEM), []byte(o.ClientKeyPEM))
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("client_cert_pem"),
+				"Invalid Client Certificate",
+				fmt.Sprintf("Unable to load the client certificate and key: %s", err),
+			)
+			break
+		}
+		config.Certificates = []tls.Certificate{cert}
+	case o.ClientCertPEM != "":
+		diags.AddAttributeError(
+			path.Root("client_key_pem"),
+			"Missing Client Key",
+			"client_cert_pem is set but client_key_pem is not. Both are required for client certificate authentication.",
+		)
+	case o.ClientKeyPEM != "":
+		diags.AddAttributeError(
+			path.Root("client_cert_pem"),
+			"Missing Client Certificate",
+			"client_key_pem is set but client_cert_pem is not. Both are required for client certificate authentication.",
+		)
+	}
+
+	if o.CACertPEM != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(o.CACertPEM)) {
+			diags.AddAttributeError(
+				path.Root("ca_cert_pem"),
+				"Invalid CA Certificate",
+				"ca_cert_pem does not contain any PEM encoded certificate.",
+			)
+		}
+		config.RootCAs = pool
+	}
+
+	return config, diags
+}
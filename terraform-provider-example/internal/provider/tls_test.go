@@ -0,0 +1,250 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"terraform-service/server"
+)
+
+// testTLSServerName is the only name in the certificate of the test server,
+// so clients have to set tls_server_name to connect to it by IP address.
+const testTLSServerName = "terraform-service.test"
+
+// testCertificate is an ephemeral certificate with its key, PEM encoded.
+type testCertificate struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM string
+	keyPEM  string
+}
+
+// newTestCertificate creates a certificate from template, signed by parent or
+// self-signed if parent is nil.
+func newTestCertificate(t *testing.T, template *x509.Certificate, parent *testCertificate) *testCertificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("unable to generate key: %s", err)
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 62))
+	if err != nil {
+		t.Fatalf("unable to generate serial number: %s", err)
+	}
+
+	template.SerialNumber = serial
+	template.NotBefore = time.Now().Add(-time.Minute)
+	template.NotAfter = time.Now().Add(time.Hour)
+
+	signer, signerKey := template, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatalf("unable to create certificate: %s", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("unable to parse certificate: %s", err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("unable to marshal key: %s", err)
+	}
+
+	return &testCertificate{
+		cert:    cert,
+		key:     key,
+		certPEM: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		keyPEM:  string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})),
+	}
+}
+
+// testPKI is a CA with a server and a client certificate signed by it.
+type testPKI struct {
+	ca, server, client *testCertificate
+}
+
+func newTestPKI(t *testing.T) *testPKI {
+	t.Helper()
+
+	ca := newTestCertificate(t, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "terraform-service test CA"},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, nil)
+
+	return &testPKI{
+		ca: ca,
+		server: newTestCertificate(t, &x509.Certificate{
+			Subject:     pkix.Name{CommonName: testTLSServerName},
+			DNSNames:    []string{testTLSServerName},
+			KeyUsage:    x509.KeyUsageDigitalSignature,
+			ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		}, ca),
+		client: newTestCertificate(t, &x509.Certificate{
+			Subject:     pkix.Name{CommonName: "terraform-provider-example"},
+			KeyUsage:    x509.KeyUsageDigitalSignature,
+			ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		}, ca),
+	}
+}
+
+// newTestTLSServer starts terraform-service over HTTPS, requiring client
+// certificates signed by the CA of pki.
+func newTestTLSServer(t *testing.T, pki *testPKI) string {
+	t.Helper()
+
+	serverCert, err := tls.X509KeyPair([]byte(pki.server.certPEM), []byte(pki.server.keyPEM))
+	if err != nil {
+		t.Fatalf("unable to load server certificate: %s", err)
+	}
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(pki.ca.cert)
+
+	srv := httptest.NewUnstartedServer(server.New(server.Options{}))
+	srv.TLS = &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientCAs:    clientCAs,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	}
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+
+	return srv.URL
+}
+
+func TestProtocolMutualTLS(t *testing.T) {
+	pki := newTestPKI(t)
+	endpoint := newTestTLSServer(t, pki)
+
+	h := newProtocolHarness(t, map[string]interface{}{
+		"endpoint":        endpoint,
+		"client_cert_pem": pki.client.certPEM,
+		"client_key_pem":  pki.client.keyPEM,
+		"ca_cert_pem":     pki.ca.certPEM,
+		"tls_server_name": testTLSServerName,
+	})
+
+	state := h.create("example_regex", map[string]interface{}{"name": "test01"})
+	h.destroy("example_regex", state)
+
+	testCases := map[string]struct {
+		config   map[string]interface{}
+		expected string
+	}{
+		"no-client-certificate": {
+			config: map[string]interface{}{
+				"ca_cert_pem":     pki.ca.certPEM,
+				"tls_server_name": testTLSServerName,
+			},
+			expected: "certificate required",
+		},
+		"untrusted-server": {
+			config: map[string]interface{}{
+				"client_cert_pem": pki.client.certPEM,
+				"client_key_pem":  pki.client.keyPEM,
+				"tls_server_name": testTLSServerName,
+			},
+			expected: "certificate signed by unknown authority",
+		},
+		"no-server-name": {
+			config: map[string]interface{}{
+				"client_cert_pem": pki.client.certPEM,
+				"client_key_pem":  pki.client.keyPEM,
+				"ca_cert_pem":     pki.ca.certPEM,
+			},
+			expected: "doesn't contain any IP SANs",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			tc.config["endpoint"] = endpoint
+			tc.config["max_attempts"] = 1
+
+			h := newProtocolHarness(t, tc.config)
+
+			plan := h.plan("example_regex", nil, map[string]interface{}{"name": "test01"})
+			requireNoErrors(t, "PlanResourceChange", plan.Diagnostics)
+
+			apply := h.apply("example_regex", nil, map[string]interface{}{"name": "test01"}, plan)
+			requireError(t, "ApplyResourceChange", apply.Diagnostics, tc.expected)
+		})
+	}
+}
+
+func TestTLSOptions(t *testing.T) {
+	pki := newTestPKI(t)
+
+	testCases := map[string]struct {
+		opts      tlsOptions
+		expectErr string
+	}{
+		"valid": {
+			opts: tlsOptions{
+				ClientCertPEM: pki.client.certPEM,
+				ClientKeyPEM:  pki.client.keyPEM,
+				CACertPEM:     pki.ca.certPEM,
+				ServerName:    testTLSServerName,
+			},
+		},
+		"missing-key": {
+			opts:      tlsOptions{ClientCertPEM: pki.client.certPEM},
+			expectErr: "Missing Client Key",
+		},
+		"missing-certificate": {
+			opts:      tlsOptions{ClientKeyPEM: pki.client.keyPEM},
+			expectErr: "Missing Client Certificate",
+		},
+		"mismatched-key": {
+			opts:      tlsOptions{ClientCertPEM: pki.client.certPEM, ClientKeyPEM: pki.server.keyPEM},
+			expectErr: "Invalid Client Certificate",
+		},
+		"invalid-ca": {
+			opts:      tlsOptions{CACertPEM: "not a certificate"},
+			expectErr: "Invalid CA Certificate",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			config, diags := tc.opts.tlsConfig()
+
+			if tc.expectErr == "" {
+				if diags.HasError() {
+					t.Fatalf("unexpected error: %v", diags)
+				}
+
+				if len(config.Certificates) != 1 || config.RootCAs == nil || config.ServerName != testTLSServerName {
+					t.Errorf("expected client certificate, CA and server name to be set, got %+v", config)
+				}
+				return
+			}
+
+			if !diags.HasError() || diags.Errors()[0].Summary() != tc.expectErr {
+				t.Errorf("expected error %q, got %v", tc.expectErr, diags)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"terraform-service/server"
+)
+
+// recordingTransport keeps a copy of the last request it sends.
+type recordingTransport struct {
+	next http.RoundTripper
+
+	last *http.Request
+	body []byte
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := peekRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	t.last = req.Clone(req.Context())
+	t.body = body
+
+	return t.next.RoundTrip(req)
+}
+
+// resend sends the last request once more, unchanged apart from modify.
+func (t *recordingTransport) resend(t2 *testing.T, modify func(req *http.Request)) *http.Response {
+	t2.Helper()
+
+	req := t.last.Clone(t.last.Context())
+	req.Body = io.NopCloser(bytes.NewReader(t.body))
+	if modify != nil {
+		modify(req)
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		t2.Fatalf("unexpected error: %s", err)
+	}
+	resp.Body.Close()
+
+	return resp
+}
+
+func newTestHMACServer(t *testing.T) string {
+	t.Helper()
+
+	return newTestServerWithOptions(t, server.Options{
+		HMACKeys:    map[string]string{"access": "secret"},
+		HMACMaxSkew: time.Minute,
+	})
+}
+
+func requireStatus(t *testing.T, resp *http.Response, err error, expected int) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != expected {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("expected %d, got %d: %s", expected, resp.StatusCode, body)
+	}
+}
+
+func TestHMACTransport(t *testing.T) {
+	endpoint := newTestHMACServer(t)
+
+	testCases := map[string]struct {
+		secretKey string
+		clockSkew time.Duration
+		expected  int
+	}{
+		"signed": {
+			secretKey: "secret",
+			expected:  http.StatusCreated,
+		},
+		"wrong-secret": {
+			secretKey: "wrong",
+			expected:  http.StatusUnauthorized,
+		},
+		"clock-ahead": {
+			secretKey: "secret",
+			clockSkew: 2 * time.Minute,
+			expected:  http.StatusUnauthorized,
+		},
+		"clock-behind": {
+			secretKey: "secret",
+			clockSkew: -2 * time.Minute,
+			expected:  http.StatusUnauthorized,
+		},
+		"clock-within-skew": {
+			secretKey: "secret",
+			clockSkew: 30 * time.Second,
+			expected:  http.StatusCreated,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			transport := newHMACTransport(http.DefaultTransport, "access", tc.secretKey)
+			transport.now = func() time.Time { return time.Now().Add(tc.clockSkew) }
+
+			client := &http.Client{Transport: transport}
+			resp, err := client.Post(endpoint+"/document?b=2&a=1", "application/json", strings.NewReader(`{"content":{"name":"test01"}}`))
+			requireStatus(t, resp, err, tc.expected)
+		})
+	}
+}
+
+func TestHMACTransport_Unsigned(t *testing.T) {
+	endpoint := newTestHMACServer(t)
+
+	resp, err := http.Get(endpoint + "/document")
+	requireStatus(t, resp, err, http.StatusUnauthorized)
+}
+
+func TestHMACTransport_Replay(t *testing.T) {
+	endpoint := newTestHMACServer(t)
+
+	recorder := &recordingTransport{next: http.DefaultTransport}
+	client := &http.Client{Transport: newHMACTransport(recorder, "access", "secret")}
+
+	resp, err := client.Post(endpoint+"/document", "application/json", strings.NewReader(`{"content":[1,2]}`))
+	requireStatus(t, resp, err, http.StatusCreated)
+
+	// The captured request is rejected however it is sent again.
+	testCases := map[string]func(req *http.Request){
+		"replayed": nil,
+		"tampered-body": func(req *http.Request) {
+			req.Body = io.NopCloser(strings.NewReader(`{"content":[1,3]}`))
+			req.ContentLength = -1
+		},
+		"tampered-query": func(req *http.Request) {
+			req.URL.RawQuery = "force=true"
+		},
+		"tampered-method": func(req *http.Request) {
+			req.Method = http.MethodPut
+		},
+		"tampered-project": func(req *http.Request) {
+			req.Header.Set(projectHeader, "other")
+		},
+	}
+
+	for name, modify := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if resp := recorder.resend(t, modify); resp.StatusCode != http.StatusUnauthorized {
+				t.Errorf("expected 401, got %d", resp.StatusCode)
+			}
+		})
+	}
+
+	// Every request is signed with a new nonce.
+	resp, err = client.Get(endpoint + "/document")
+	requireStatus(t, resp, err, http.StatusOK)
+}
+
+func TestProtocolHMAC(t *testing.T) {
+	endpoint := newTestHMACServer(t)
+
+	h := newProtocolHarness(t, map[string]interface{}{
+		"endpoint":   endpoint,
+		"auth_mode":  "hmac",
+		"access_key": "access",
+		"secret_key": "secret",
+	})
+
+	state := h.create("example_regex", map[string]interface{}{"name": "test01"})
+	h.destroy("example_regex", state)
+
+	// Keys can come from the environment.
+	t.Setenv("EXAMPLE_ACCESS_KEY", "access")
+	t.Setenv("EXAMPLE_SECRET_KEY", "wrong")
+
+	h = newProtocolHarness(t, map[string]interface{}{
+		"endpoint":     endpoint,
+		"auth_mode":    "hmac",
+		"max_attempts": 1,
+	})
+
+	plan := h.plan("example_regex", nil, map[string]interface{}{"name": "test01"})
+	requireNoErrors(t, "PlanResourceChange", plan.Diagnostics)
+
+	apply := h.apply("example_regex", nil, map[string]interface{}{"name": "test01"}, plan)
+	requireError(t, "ApplyResourceChange", apply.Diagnostics, "invalid signature")
+}
@@ -0,0 +1,213 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// httpLogSubsystem is the tflog subsystem backend traffic is logged under.
+// Its level is set with TF_LOG_PROVIDER_EXAMPLE_HTTP, e.g. to DEBUG.
+const httpLogSubsystem = "http"
+
+// maxLoggedBodySize truncates logged request and response bodies.
+const maxLoggedBodySize = 16 * 1024
+
+const redacted = "***"
+
+// loggingTransport logs every request and response to the backend, with
+// credentials redacted from headers, query strings and JSON bodies.
+type loggingTransport struct {
+	next http.RoundTripper
+}
+
+func newLoggingTransport(next http.RoundTripper) *loggingTransport {
+	return &loggingTransport{next: next}
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := tflog.NewSubsystem(req.Context(), httpLogSubsystem, tflog.WithLevelFromEnv("TF_LOG_PROVIDER_EXAMPLE", httpLogSubsystem))
+
+	reqBody, err := peekRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]interface{}{
+		"http_method":          req.Method,
+		"http_url":             redactURL(req.URL),
+		"http_request_headers": redactHeaders(req.Header),
+	}
+	if len(reqBody) > 0 {
+		fields["http_request_body"] = redactBody(reqBody, req.Header.Get("Content-Type"))
+	}
+	tflog.SubsystemDebug(ctx, httpLogSubsystem, "sending backend request", fields)
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	fields = map[string]interface{}{
+		"http_method":      req.Method,
+		"http_url":         redactURL(req.URL),
+		"http_duration_ms": time.Since(start).Milliseconds(),
+	}
+
+	if err != nil {
+		fields["error"] = err.Error()
+		tflog.SubsystemDebug(ctx, httpLogSubsystem, "backend request failed", fields)
+
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	if err != nil {
+		return nil, err
+	}
+
+	fields["http_status"] = resp.StatusCode
+	fields["http_response_headers"] = redactHeaders(resp.Header)
+	if len(respBody) > 0 {
+		fields["http_response_body"] = redactBody(respBody, resp.Header.Get("Content-Type"))
+	}
+	tflog.SubsystemDebug(ctx, httpLogSubsystem, "received backend response", fields)
+
+	return resp, nil
+}
+
+// peekRequestBody returns a copy of the request body, leaving req.Body
+// readable for the next transport.
+func peekRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer body.Close()
+
+		return io.ReadAll(body)
+	}
+
+	b, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	req.Body = io.NopCloser(bytes.NewReader(b))
+
+	return b, err
+}
+
+// isSecretKey reports whether a header, query parameter or JSON field named
+// key holds a credential.
+func isSecretKey(key string) bool {
+	key = strings.ToLower(key)
+
+	switch {
+	case key == "authorization", key == "proxy-authorization", key == "token":
+		return true
+	case strings.Contains(key, "password"), strings.HasSuffix(key, "_token"), strings.Contains(key, "secret"):
+		return true
+	}
+
+	return false
+}
+
+func redactHeaders(header http.Header) map[string]string {
+	out := make(map[string]string, len(header))
+	for key, values := range header {
+		if isSecretKey(key) {
+			out[key] = redacted
+			continue
+		}
+		out[key] = strings.Join(values, ", ")
+	}
+
+	return out
+}
+
+func redactURL(u *url.URL) string {
+	query := u.Query()
+	if len(query) == 0 {
+		return u.String()
+	}
+
+	for key := range query {
+		if isSecretKey(key) {
+			query.Set(key, redacted)
+		}
+	}
+
+	c := *u
+	c.RawQuery = query.Encode()
+
+	return c.String()
+}
+
+// redactBody masks secret fields of a JSON or form encoded body. Other bodies
+// are logged as they are.
+func redactBody(body []byte, contentType string) string {
+	if strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			return truncate(string(body))
+		}
+
+		for key := range form {
+			if isSecretKey(key) {
+				form.Set(key, redacted)
+			}
+		}
+
+		return truncate(form.Encode())
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return truncate(string(body))
+	}
+
+	b, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return truncate(string(body))
+	}
+
+	return truncate(string(b))
+}
+
+func redactValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if isSecretKey(key) {
+				v[key] = redacted
+				continue
+			}
+			v[key] = redactValue(value)
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = redactValue(value)
+		}
+	}
+
+	return v
+}
+
+func truncate(s string) string {
+	if len(s) <= maxLoggedBodySize {
+		return s
+	}
+
+	return s[:maxLoggedBodySize] + "...(truncated)"
+}
@@ -0,0 +1,115 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+func TestLoggingTransport(t *testing.T) {
+	t.Setenv("TF_LOG_PROVIDER_EXAMPLE_HTTP", "DEBUG")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"name":"test01","admin_password":"hunter2"}` {
+			t.Errorf("unexpected request body %q", body)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"vm-1","token":"s3cr3t-token","nested":[{"password":"p4ss"}]}`))
+	}))
+	defer server.Close()
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, server.URL+"/regex?token=query-token&name=test01", strings.NewReader(`{"name":"test01","admin_password":"hunter2"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	req.Header.Set("Authorization", "Bearer header-token")
+
+	client := &http.Client{Transport: newLoggingTransport(http.DefaultTransport)}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if !strings.Contains(string(body), "s3cr3t-token") {
+		t.Errorf("expected response body to be passed through unchanged, got %q", body)
+	}
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	if err != nil {
+		t.Fatalf("unexpected error decoding logs: %s", err)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 log entries, got %d: %v", len(entries), entries)
+	}
+
+	for _, secret := range []string{"hunter2", "s3cr3t-token", "p4ss", "query-token", "header-token"} {
+		if strings.Contains(output.String(), secret) {
+			t.Errorf("expected %q to be redacted from logs", secret)
+		}
+	}
+
+	response := entries[1]
+	if response["@module"] != "provider."+httpLogSubsystem {
+		t.Errorf("expected module provider.%s, got %v", httpLogSubsystem, response["@module"])
+	}
+	if response["http_status"] != float64(http.StatusOK) {
+		t.Errorf("expected http_status 200, got %v", response["http_status"])
+	}
+	if _, ok := response["http_duration_ms"]; !ok {
+		t.Error("expected http_duration_ms field")
+	}
+	if !strings.Contains(fmt.Sprint(response["http_response_body"]), `"id":"vm-1"`) {
+		t.Errorf("expected response body in log, got %v", response["http_response_body"])
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	testCases := map[string]struct {
+		body        string
+		contentType string
+		expected    string
+	}{
+		"json": {
+			body:        `{"client_secret":"a","password":"b","Token":"c","name":"d"}`,
+			contentType: "application/json",
+			expected:    `{"Token":"***","client_secret":"***","name":"d","password":"***"}`,
+		},
+		"form": {
+			body:        "grant_type=client_credentials&client_secret=hunter2",
+			contentType: "application/x-www-form-urlencoded",
+			expected:    "client_secret=%2A%2A%2A&grant_type=client_credentials",
+		},
+		"plain": {
+			body:        "not json",
+			contentType: "text/plain",
+			expected:    "not json",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if got := redactBody([]byte(testCase.body), testCase.contentType); got != testCase.expected {
+				t.Errorf("expected %s, got %s", testCase.expected, got)
+			}
+		})
+	}
+}
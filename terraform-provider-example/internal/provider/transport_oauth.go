@@ -0,0 +1,121 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// defaultTokenPath is appended to the endpoint when token_url is not set.
+const defaultTokenPath = "/oauth/token"
+
+// oauthTransport authenticates requests with an access token obtained with
+// the OAuth2 client credentials grant. The token is cached and shared by all
+// requests until shortly before it expires. A 401 response drops the cached
+// token and the request is sent once more with a new one, in case the
+// backend revoked it early.
+type oauthTransport struct {
+	next   http.RoundTripper
+	config *clientcredentials.Config
+
+	// tokenCtx carries the HTTP client used for token requests. It is not
+	// tied to a single request, a token outlives the request that fetched it.
+	tokenCtx context.Context
+
+	mu     sync.Mutex
+	source oauth2.TokenSource
+}
+
+// newOAuthTransport returns a transport that authenticates requests to next
+// with tokens fetched by tokenClient from config.TokenURL.
+func newOAuthTransport(next http.RoundTripper, config *clientcredentials.Config, tokenClient *http.Client) *oauthTransport {
+	t := &oauthTransport{
+		next:     next,
+		config:   config,
+		tokenCtx: context.WithValue(context.Background(), oauth2.HTTPClient, tokenClient),
+	}
+	t.source = config.TokenSource(t.tokenCtx)
+
+	return t
+}
+
+func (t *oauthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.token()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.next.RoundTrip(authorize(req, token))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	// The body of the first attempt has been consumed and cannot be sent
+	// again.
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil
+	}
+
+	tflog.Debug(req.Context(), "access token rejected, requesting a new one")
+
+	t.invalidate(token)
+
+	token, err = t.token()
+	if err != nil {
+		return resp, nil
+	}
+
+	retry := authorize(req, token)
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return resp, nil
+		}
+		retry.Body = body
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	return t.next.RoundTrip(retry)
+}
+
+// token returns the cached token, fetching a new one if it expired.
+func (t *oauthTransport) token() (*oauth2.Token, error) {
+	t.mu.Lock()
+	source := t.source
+	t.mu.Unlock()
+
+	return source.Token()
+}
+
+// invalidate drops token from the cache, unless another request already
+// replaced it.
+func (t *oauthTransport) invalidate(token *oauth2.Token) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	current, err := t.source.Token()
+	if err == nil && current.AccessToken != token.AccessToken {
+		return
+	}
+
+	t.source = t.config.TokenSource(t.tokenCtx)
+}
+
+// authorize returns a copy of req carrying token. RoundTrippers must not
+// modify the request they are given.
+func authorize(req *http.Request, token *oauth2.Token) *http.Request {
+	req = req.Clone(req.Context())
+	token.SetAuthHeader(req)
+
+	return req
+}
@@ -0,0 +1,246 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"golang.org/x/oauth2/clientcredentials"
+
+	"terraform-service/server"
+)
+
+// testTokenServer issues tokens numbered in order at /token and accepts
+// requests to other paths carrying the latest one.
+type testTokenServer struct {
+	*httptest.Server
+
+	expiresIn int
+
+	mu     sync.Mutex
+	issued int
+	scope  string
+
+	tokenRequests int32
+}
+
+func newTestTokenServer(t *testing.T, expiresIn int) *testTokenServer {
+	t.Helper()
+
+	s := &testTokenServer{expiresIn: expiresIn}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func (s *testTokenServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.URL.Path == "/token" {
+		atomic.AddInt32(&s.tokenRequests, 1)
+
+		id, secret, _ := r.BasicAuth()
+		if id != "client" || secret != "secret" || r.FormValue("grant_type") != "client_credentials" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		s.issued++
+		s.scope = r.FormValue("scope")
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": fmt.Sprintf("token-%d", s.issued),
+			"token_type":   "Bearer",
+			"expires_in":   s.expiresIn,
+		})
+		return
+	}
+
+	if r.Header.Get("Authorization") != fmt.Sprintf("Bearer token-%d", s.issued) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	body, _ := io.ReadAll(r.Body)
+	_, _ = w.Write(body)
+}
+
+// revoke makes the server reject the current token.
+func (s *testTokenServer) revoke() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.issued++
+}
+
+func testOAuthClient(s *testTokenServer, scopes ...string) *http.Client {
+	config := &clientcredentials.Config{
+		ClientID:     "client",
+		ClientSecret: "secret",
+		TokenURL:     s.URL + "/token",
+		Scopes:       scopes,
+	}
+
+	return &http.Client{Transport: newOAuthTransport(http.DefaultTransport, config, http.DefaultClient)}
+}
+
+func TestOAuthTransport_CachesToken(t *testing.T) {
+	s := newTestTokenServer(t, 3600)
+	client := testOAuthClient(s, "vm:read", "vm:write")
+
+	for i := 0; i < 3; i++ {
+		resp, err := client.Get(s.URL + "/regex")
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected 200, got %d", resp.StatusCode)
+		}
+	}
+
+	if got := atomic.LoadInt32(&s.tokenRequests); got != 1 {
+		t.Errorf("expected a single token request, got %d", got)
+	}
+
+	if s.scope != "vm:read vm:write" {
+		t.Errorf("expected requested scopes, got %q", s.scope)
+	}
+}
+
+func TestOAuthTransport_RefreshesExpiredToken(t *testing.T) {
+	// Tokens are refreshed shortly before they expire, so a token that is
+	// only valid for a second is never reused.
+	s := newTestTokenServer(t, 1)
+	client := testOAuthClient(s)
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(s.URL + "/regex")
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		resp.Body.Close()
+	}
+
+	if got := atomic.LoadInt32(&s.tokenRequests); got != 2 {
+		t.Errorf("expected a token request per request, got %d", got)
+	}
+}
+
+func TestOAuthTransport_RevokedToken(t *testing.T) {
+	s := newTestTokenServer(t, 3600)
+	client := testOAuthClient(s)
+
+	resp, err := client.Get(s.URL + "/regex")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	resp.Body.Close()
+
+	s.revoke()
+
+	// The request is sent again, with its body, once a new token is issued.
+	resp, err = client.Post(s.URL+"/regex", "application/json", strings.NewReader(`{"name":"test01"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != http.StatusOK || string(body) != `{"name":"test01"}` {
+		t.Errorf("expected request to be retried with a new token, got %d %q", resp.StatusCode, body)
+	}
+
+	if got := atomic.LoadInt32(&s.tokenRequests); got != 2 {
+		t.Errorf("expected 2 token requests, got %d", got)
+	}
+}
+
+func TestOAuthTransport_InvalidClient(t *testing.T) {
+	s := newTestTokenServer(t, 3600)
+
+	config := &clientcredentials.Config{
+		ClientID:     "client",
+		ClientSecret: "wrong",
+		TokenURL:     s.URL + "/token",
+	}
+	client := &http.Client{Transport: newOAuthTransport(http.DefaultTransport, config, http.DefaultClient)}
+
+	_, err := client.Get(s.URL + "/regex")
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("expected token request to fail with 401, got %v", err)
+	}
+}
+
+func TestProtocolOAuth(t *testing.T) {
+	endpoint := newTestServerWithOptions(t, server.Options{
+		OAuthClients: map[string]string{"client": "secret"},
+	})
+
+	// The backend rejects unauthenticated requests.
+	h := newProtocolHarness(t, map[string]interface{}{"endpoint": endpoint, "max_attempts": 1})
+
+	plan := h.plan("example_regex", nil, map[string]interface{}{"name": "test01"})
+	requireNoErrors(t, "PlanResourceChange", plan.Diagnostics)
+
+	apply := h.apply("example_regex", nil, map[string]interface{}{"name": "test01"}, plan)
+	requireError(t, "ApplyResourceChange", apply.Diagnostics, "missing bearer token")
+
+	h = newProtocolHarness(t, map[string]interface{}{
+		"endpoint":      endpoint,
+		"client_id":     "client",
+		"client_secret": "secret",
+		"scopes":        []interface{}{"vm"},
+	})
+
+	state := h.create("example_regex", map[string]interface{}{"name": "test01"})
+	h.destroy("example_regex", state)
+
+	// Wrong credentials are reported when the token is requested.
+	h = newProtocolHarness(t, map[string]interface{}{
+		"endpoint":      endpoint,
+		"client_id":     "client",
+		"client_secret": "wrong",
+	})
+
+	plan = h.plan("example_regex", nil, map[string]interface{}{"name": "test01"})
+	requireNoErrors(t, "PlanResourceChange", plan.Diagnostics)
+
+	apply = h.apply("example_regex", nil, map[string]interface{}{"name": "test01"}, plan)
+	requireError(t, "ApplyResourceChange", apply.Diagnostics, "invalid_client")
+}
+
+// Tokens of an endpoint template are requested from the expanded endpoint.
+func TestProtocolOAuth_EndpointTemplate(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle("/east/", http.StripPrefix("/east", server.New(server.Options{
+		OAuthClients: map[string]string{"client": "secret"},
+	})))
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	h := newProtocolHarness(t, map[string]interface{}{
+		"endpoint":      srv.URL + "/{region}",
+		"region":        "east",
+		"client_id":     "client",
+		"client_secret": "secret",
+	})
+
+	state := h.create("example_regex", map[string]interface{}{"name": "test01"})
+	h.destroy("example_regex", state)
+}
@@ -0,0 +1,174 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// defaultMaxAttempts is used when the provider block does not set
+// max_attempts.
+const defaultMaxAttempts = 4
+
+// idempotencyKeyHeader marks a non-idempotent request as safe to retry; the
+// backend deduplicates requests carrying the same key.
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// retryTransport retries requests that failed with a 5xx status, a 429 or a
+// broken connection. Requests are only retried if they are idempotent or
+// carry an Idempotency-Key header.
+type retryTransport struct {
+	next http.RoundTripper
+
+	// maxAttempts caps the number of times a request is sent, including the
+	// first attempt.
+	maxAttempts int
+
+	// maxBackoff also caps the delay a Retry-After header asks for.
+	minBackoff time.Duration
+	maxBackoff time.Duration
+}
+
+func newRetryTransport(next http.RoundTripper, maxAttempts int) *retryTransport {
+	return &retryTransport{
+		next:        next,
+		maxAttempts: maxAttempts,
+		minBackoff:  time.Second,
+		maxBackoff:  30 * time.Second,
+	}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	retryable := isIdempotent(req) && (req.Body == nil || req.GetBody != nil)
+
+	backoff := t.minBackoff
+
+	for attempt := 1; ; attempt++ {
+		// RoundTrippers must not modify the request, every attempt is sent
+		// as a clone with a fresh body.
+		attemptReq := req.Clone(ctx)
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attemptReq.Body = body
+		}
+
+		resp, err := t.next.RoundTrip(attemptReq)
+
+		if !retryable || attempt >= t.maxAttempts || !shouldRetry(resp, err) {
+			return resp, err
+		}
+
+		// Retry-After is capped like the backoff, so that a bogus header
+		// cannot stall the apply for hours.
+		delay := withJitter(backoff, 0.2)
+		if d, ok := retryAfter(resp); ok {
+			delay = min(d, t.maxBackoff)
+		}
+
+		fields := map[string]interface{}{
+			"method":       req.Method,
+			"url":          req.URL.String(),
+			"attempt":      attempt,
+			"max_attempts": t.maxAttempts,
+			"delay":        delay.String(),
+		}
+		if err != nil {
+			fields["error"] = err.Error()
+		} else {
+			fields["status"] = resp.StatusCode
+
+			// Drain the body so the connection can be reused.
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		tflog.Warn(ctx, "retrying backend request", fields)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		backoff *= 2
+		if backoff > t.maxBackoff {
+			backoff = t.maxBackoff
+		}
+	}
+}
+
+// isIdempotent reports whether req may be sent more than once.
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	return req.Header.Get(idempotencyKeyHeader) != ""
+}
+
+// shouldRetry reports whether the outcome of an attempt is worth retrying.
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		var netErr net.Error
+
+		switch {
+		case errors.Is(err, syscall.ECONNRESET),
+			errors.Is(err, syscall.ECONNREFUSED),
+			errors.Is(err, io.EOF),
+			errors.Is(err, io.ErrUnexpectedEOF):
+			return true
+		case errors.As(err, &netErr) && netErr.Timeout():
+			return true
+		}
+
+		return false
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+
+	return resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented
+}
+
+// retryAfter parses the Retry-After header of a 429 or 503 response, which is
+// either a number of seconds or an HTTP date.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil || (resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable) {
+		return 0, false
+	}
+
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(v); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+
+	return 0, false
+}
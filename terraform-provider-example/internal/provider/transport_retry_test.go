@@ -0,0 +1,321 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testRetryClient(maxAttempts int) *http.Client {
+	transport := newRetryTransport(http.DefaultTransport, maxAttempts)
+	transport.minBackoff = time.Millisecond
+	transport.maxBackoff = 5 * time.Millisecond
+
+	return &http.Client{Transport: transport}
+}
+
+// testFlakyServer fails the first failures requests with handler and answers
+// the rest with 200 and the request body.
+func testFlakyServer(t *testing.T, failures int32, fail http.HandlerFunc) (*httptest.Server, *int32) {
+	t.Helper()
+
+	var requests int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) <= failures {
+			fail(w, r)
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &requests
+}
+
+func statusHandler(code int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(code)
+	}
+}
+
+func TestRetryTransport_ServerErrors(t *testing.T) {
+	server, requests := testFlakyServer(t, 2, statusHandler(http.StatusBadGateway))
+
+	resp, err := testRetryClient(4).Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected status 200, got %d", resp.StatusCode)
+	}
+
+	if got := atomic.LoadInt32(requests); got != 3 {
+		t.Errorf("expected 3 requests, got %d", got)
+	}
+}
+
+func TestRetryTransport_MaxAttempts(t *testing.T) {
+	server, requests := testFlakyServer(t, 10, statusHandler(http.StatusServiceUnavailable))
+
+	resp, err := testRetryClient(3).Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503, got %d", resp.StatusCode)
+	}
+
+	if got := atomic.LoadInt32(requests); got != 3 {
+		t.Errorf("expected 3 requests, got %d", got)
+	}
+}
+
+func TestRetryTransport_NotRetried(t *testing.T) {
+	testCases := map[string]int{
+		"client-error":    http.StatusBadRequest,
+		"not-found":       http.StatusNotFound,
+		"not-implemented": http.StatusNotImplemented,
+	}
+
+	for name, code := range testCases {
+		t.Run(name, func(t *testing.T) {
+			server, requests := testFlakyServer(t, 1, statusHandler(code))
+
+			resp, err := testRetryClient(4).Get(server.URL)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != code {
+				t.Errorf("expected status %d, got %d", code, resp.StatusCode)
+			}
+
+			if got := atomic.LoadInt32(requests); got != 1 {
+				t.Errorf("expected 1 request, got %d", got)
+			}
+		})
+	}
+}
+
+func TestRetryTransport_RetryAfter(t *testing.T) {
+	var first time.Time
+
+	server, requests := testFlakyServer(t, 1, func(w http.ResponseWriter, r *http.Request) {
+		first = time.Now()
+		w.Header().Set("Retry-After", "1")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	// Retry-After is capped by maxBackoff, not by the backoff itself.
+	transport := newRetryTransport(http.DefaultTransport, 2)
+	transport.minBackoff = time.Millisecond
+	transport.maxBackoff = 2 * time.Second
+
+	resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected status 200, got %d", resp.StatusCode)
+	}
+
+	if got := atomic.LoadInt32(requests); got != 2 {
+		t.Errorf("expected 2 requests, got %d", got)
+	}
+
+	// The transport backoff is a few milliseconds, so waiting this long
+	// means Retry-After was honoured.
+	if elapsed := time.Since(first); elapsed < 900*time.Millisecond {
+		t.Errorf("expected retry after about 1s, got %s", elapsed)
+	}
+}
+
+func TestRetryTransport_RetryAfterCapped(t *testing.T) {
+	for name, value := range map[string]string{
+		"seconds": "86400",
+		"date":    time.Now().Add(24 * time.Hour).UTC().Format(http.TimeFormat),
+	} {
+		t.Run(name, func(t *testing.T) {
+			server, requests := testFlakyServer(t, 1, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Retry-After", value)
+				w.WriteHeader(http.StatusServiceUnavailable)
+			})
+
+			start := time.Now()
+
+			resp, err := testRetryClient(2).Get(server.URL)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK || atomic.LoadInt32(requests) != 2 {
+				t.Errorf("expected a successful retry, got status %d after %d requests", resp.StatusCode, atomic.LoadInt32(requests))
+			}
+
+			// maxBackoff of the test client is 5ms.
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("expected Retry-After to be capped by maxBackoff, waited %s", elapsed)
+			}
+		})
+	}
+}
+
+func TestRetryTransport_ConnectionReset(t *testing.T) {
+	server, requests := testFlakyServer(t, 1, func(w http.ResponseWriter, r *http.Request) {
+		hijacker, ok := w.(http.Hijacker)
+		if !ok {
+			t.Errorf("expected a http.Hijacker, got %T", w)
+			return
+		}
+
+		conn, _, err := hijacker.Hijack()
+		if err != nil {
+			t.Errorf("unexpected error: %s", err)
+			return
+		}
+		conn.Close()
+	})
+
+	req, err := http.NewRequest(http.MethodPut, server.URL, bytes.NewReader([]byte("payload")))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	resp, err := testRetryClient(2).Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "payload" {
+		t.Errorf("expected request body to be resent, got %q", body)
+	}
+
+	if got := atomic.LoadInt32(requests); got != 2 {
+		t.Errorf("expected 2 requests, got %d", got)
+	}
+}
+
+func TestRetryTransport_Post(t *testing.T) {
+	testCases := map[string]struct {
+		idempotencyKey   string
+		expectedRequests int32
+		expectedStatus   int
+	}{
+		"without-idempotency-key": {
+			expectedRequests: 1,
+			expectedStatus:   http.StatusBadGateway,
+		},
+		"with-idempotency-key": {
+			idempotencyKey:   "key-1",
+			expectedRequests: 2,
+			expectedStatus:   http.StatusOK,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			server, requests := testFlakyServer(t, 1, statusHandler(http.StatusBadGateway))
+
+			req, err := http.NewRequest(http.MethodPost, server.URL, bytes.NewReader([]byte("{}")))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if testCase.idempotencyKey != "" {
+				req.Header.Set(idempotencyKeyHeader, testCase.idempotencyKey)
+			}
+
+			resp, err := testRetryClient(4).Do(req)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != testCase.expectedStatus {
+				t.Errorf("expected status %d, got %d", testCase.expectedStatus, resp.StatusCode)
+			}
+
+			if got := atomic.LoadInt32(requests); got != testCase.expectedRequests {
+				t.Errorf("expected %d requests, got %d", testCase.expectedRequests, got)
+			}
+		})
+	}
+}
+
+func TestRetryTransport_DoesNotModifyRequest(t *testing.T) {
+	server, requests := testFlakyServer(t, 2, statusHandler(http.StatusBadGateway))
+
+	req, err := http.NewRequest(http.MethodPost, server.URL, bytes.NewReader([]byte(`{"name":"test01"}`)))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	req.Header.Set(idempotencyKeyHeader, "key-1")
+
+	body := req.Body
+
+	resp, err := testRetryClient(4).Transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if got := atomic.LoadInt32(requests); got != 3 {
+		t.Errorf("expected 3 requests, got %d", got)
+	}
+
+	// Every attempt sends the whole body, the request of the caller keeps
+	// its own.
+	if got, _ := io.ReadAll(resp.Body); string(got) != `{"name":"test01"}` {
+		t.Errorf("expected the body to be sent again, got %q", got)
+	}
+
+	if req.Body != body {
+		t.Error("expected the body of the request not to be replaced")
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	header := func(code int, v string) *http.Response {
+		resp := &http.Response{StatusCode: code, Header: http.Header{}}
+		if v != "" {
+			resp.Header.Set("Retry-After", v)
+		}
+		return resp
+	}
+
+	if d, ok := retryAfter(header(http.StatusTooManyRequests, "3")); !ok || d != 3*time.Second {
+		t.Errorf("expected 3s, got %s %t", d, ok)
+	}
+
+	date := time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)
+	if d, ok := retryAfter(header(http.StatusServiceUnavailable, date)); !ok || d < 59*time.Minute {
+		t.Errorf("expected about 1h, got %s %t", d, ok)
+	}
+
+	if _, ok := retryAfter(header(http.StatusBadGateway, "3")); ok {
+		t.Error("expected Retry-After to be ignored for 502")
+	}
+
+	if _, ok := retryAfter(header(http.StatusTooManyRequests, "soon")); ok {
+		t.Error("expected invalid Retry-After to be ignored")
+	}
+}
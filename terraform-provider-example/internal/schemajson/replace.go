@@ -0,0 +1,101 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package schemajson
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+)
+
+// requiresReplace returns the attributes of every resource of p that have a
+// RequiresReplace, RequiresReplaceIf or RequiresReplaceIfConfigured plan
+// modifier, keyed by resource type. Replacements planned in ModifyPlan are
+// not visible in the schema and are not reported.
+func requiresReplace(ctx context.Context, p provider.Provider) map[string][]string {
+	var meta provider.MetadataResponse
+	p.Metadata(ctx, provider.MetadataRequest{}, &meta)
+
+	out := map[string][]string{}
+
+	for _, newResource := range p.Resources(ctx) {
+		r := newResource()
+
+		var metaResp resource.MetadataResponse
+		r.Metadata(ctx, resource.MetadataRequest{ProviderTypeName: meta.TypeName}, &metaResp)
+
+		var schemaResp resource.SchemaResponse
+		r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+		var paths []string
+		walkSchemaObject("", reflect.ValueOf(schemaResp.Schema), &paths)
+
+		if len(paths) > 0 {
+			sort.Strings(paths)
+			out[metaResp.TypeName] = paths
+		}
+	}
+
+	return out
+}
+
+// walkSchemaObject appends the replacing attributes of a schema, nested
+// object or single nested attribute or block. The framework has a type per
+// kind of attribute and block, they are inspected by their fields:
+// Attributes, Blocks, NestedObject and PlanModifiers.
+func walkSchemaObject(prefix string, v reflect.Value, paths *[]string) {
+	for _, field := range []string{"Attributes", "Blocks"} {
+		children := v.FieldByName(field)
+		if !children.IsValid() || children.Kind() != reflect.Map {
+			continue
+		}
+
+		iter := children.MapRange()
+		for iter.Next() {
+			walkSchemaAttribute(prefix+iter.Key().String(), iter.Value().Elem(), paths)
+		}
+	}
+}
+
+func walkSchemaAttribute(path string, v reflect.Value, paths *[]string) {
+	if v.Kind() != reflect.Struct {
+		return
+	}
+
+	if modifiers := v.FieldByName("PlanModifiers"); modifiers.IsValid() && modifiers.Kind() == reflect.Slice {
+		for i := 0; i < modifiers.Len(); i++ {
+			if isRequiresReplace(modifiers.Index(i)) {
+				*paths = append(*paths, path)
+				break
+			}
+		}
+	}
+
+	if nested := v.FieldByName("NestedObject"); nested.IsValid() {
+		walkSchemaObject(path+".", nested, paths)
+	}
+
+	walkSchemaObject(path+".", v, paths)
+}
+
+// isRequiresReplace reports whether a plan modifier is one of the
+// RequiresReplace modifiers of the framework, which share the type
+// requiresReplaceIfModifier in every planmodifier package.
+func isRequiresReplace(modifier reflect.Value) bool {
+	if modifier.Kind() == reflect.Interface {
+		modifier = modifier.Elem()
+	}
+	if !modifier.IsValid() {
+		return false
+	}
+
+	t := modifier.Type()
+
+	return strings.HasPrefix(t.Name(), "requiresReplace") &&
+		strings.HasPrefix(t.PkgPath(), "github.com/hashicorp/terraform-plugin-framework/")
+}
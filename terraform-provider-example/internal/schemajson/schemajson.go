@@ -0,0 +1,326 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package schemajson converts the schema a provider server returns over the
+// plugin protocol into the format of `terraform providers schema -json`, so
+// that schemas can be exported without the Terraform CLI.
+//
+// Snapshots add the attributes whose change replaces the resource, which
+// Terraform does not export, under a separate requires_replace key. Tools
+// reading the Terraform format ignore it.
+package schemajson
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// FormatVersion is the format_version Terraform reports for provider schemas.
+const FormatVersion = "1.0"
+
+// Snapshot is the exported schema of a provider.
+type Snapshot struct {
+	FormatVersion string                            `json:"format_version"`
+	Schemas       map[string]*tfjson.ProviderSchema `json:"provider_schemas"`
+
+	// RequiresReplace lists, by provider address and resource type, the
+	// attributes with a RequiresReplace plan modifier, e.g. "network.uuid".
+	RequiresReplace map[string]map[string][]string `json:"requires_replace,omitempty"`
+}
+
+// Export returns the schemas of p, keyed by address like Terraform does,
+// e.g. "registry.terraform.io/hashicorp/aws".
+func Export(ctx context.Context, p provider.Provider, address string) (*Snapshot, error) {
+	server, err := providerserver.NewProtocol6WithError(p)()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []string
+	for _, d := range resp.Diagnostics {
+		if d.Severity == tfprotov6.DiagnosticSeverityError {
+			errs = append(errs, d.Summary+": "+d.Detail)
+		}
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("provider returned errors: %s", strings.Join(errs, "; "))
+	}
+
+	schema := &tfjson.ProviderSchema{
+		ResourceSchemas:   make(map[string]*tfjson.Schema, len(resp.ResourceSchemas)),
+		DataSourceSchemas: make(map[string]*tfjson.Schema, len(resp.DataSourceSchemas)),
+		Functions:         make(map[string]*tfjson.FunctionSignature, len(resp.Functions)),
+	}
+
+	if len(resp.ActionSchemas) > 0 {
+		schema.ActionSchemas = make(map[string]*tfjson.ActionSchema, len(resp.ActionSchemas))
+	}
+
+	if len(resp.ListResourceSchemas) > 0 {
+		schema.ListResourceSchemas = make(map[string]*tfjson.Schema, len(resp.ListResourceSchemas))
+	}
+
+	if schema.ConfigSchema, err = convertSchema(resp.Provider); err != nil {
+		return nil, fmt.Errorf("provider: %w", err)
+	}
+
+	for name, s := range resp.ResourceSchemas {
+		if schema.ResourceSchemas[name], err = convertSchema(s); err != nil {
+			return nil, fmt.Errorf("resource %s: %w", name, err)
+		}
+	}
+
+	for name, s := range resp.DataSourceSchemas {
+		if schema.DataSourceSchemas[name], err = convertSchema(s); err != nil {
+			return nil, fmt.Errorf("data source %s: %w", name, err)
+		}
+	}
+
+	for name, a := range resp.ActionSchemas {
+		if schema.ActionSchemas[name], err = convertActionSchema(a); err != nil {
+			return nil, fmt.Errorf("action %s: %w", name, err)
+		}
+	}
+
+	for name, s := range resp.ListResourceSchemas {
+		if schema.ListResourceSchemas[name], err = convertSchema(s); err != nil {
+			return nil, fmt.Errorf("list resource %s: %w", name, err)
+		}
+	}
+
+	for name, f := range resp.Functions {
+		if schema.Functions[name], err = convertFunction(f); err != nil {
+			return nil, fmt.Errorf("function %s: %w", name, err)
+		}
+	}
+
+	return &Snapshot{
+		FormatVersion:   FormatVersion,
+		Schemas:         map[string]*tfjson.ProviderSchema{address: schema},
+		RequiresReplace: map[string]map[string][]string{address: requiresReplace(ctx, p)},
+	}, nil
+}
+
+func convertSchema(s *tfprotov6.Schema) (*tfjson.Schema, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	block, err := convertBlock(s.Block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tfjson.Schema{
+		Version: uint64(s.Version),
+		Block:   block,
+	}, nil
+}
+
+// convertActionSchema converts the schema of an action, which Terraform
+// exports without version.
+func convertActionSchema(a *tfprotov6.ActionSchema) (*tfjson.ActionSchema, error) {
+	if a == nil || a.Schema == nil {
+		return nil, nil
+	}
+
+	block, err := convertBlock(a.Schema.Block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tfjson.ActionSchema{Block: block}, nil
+}
+
+func convertBlock(b *tfprotov6.SchemaBlock) (*tfjson.SchemaBlock, error) {
+	block := &tfjson.SchemaBlock{
+		Description:     b.Description,
+		DescriptionKind: convertStringKind(b.DescriptionKind),
+		Deprecated:      b.Deprecated,
+	}
+
+	if len(b.Attributes) > 0 {
+		attributes, err := convertAttributes(b.Attributes)
+		if err != nil {
+			return nil, err
+		}
+		block.Attributes = attributes
+	}
+
+	if len(b.BlockTypes) > 0 {
+		block.NestedBlocks = make(map[string]*tfjson.SchemaBlockType, len(b.BlockTypes))
+	}
+
+	for _, nb := range b.BlockTypes {
+		nested, err := convertBlock(nb.Block)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", nb.TypeName, err)
+		}
+
+		block.NestedBlocks[nb.TypeName] = &tfjson.SchemaBlockType{
+			NestingMode: convertBlockNesting(nb.Nesting),
+			Block:       nested,
+			MinItems:    uint64(nb.MinItems),
+			MaxItems:    uint64(nb.MaxItems),
+		}
+	}
+
+	return block, nil
+}
+
+func convertAttributes(in []*tfprotov6.SchemaAttribute) (map[string]*tfjson.SchemaAttribute, error) {
+	out := make(map[string]*tfjson.SchemaAttribute, len(in))
+
+	for _, a := range in {
+		attribute := &tfjson.SchemaAttribute{
+			Description:     a.Description,
+			DescriptionKind: convertStringKind(a.DescriptionKind),
+			Deprecated:      a.Deprecated,
+			Required:        a.Required,
+			Optional:        a.Optional,
+			Computed:        a.Computed,
+			Sensitive:       a.Sensitive,
+		}
+
+		if a.NestedType != nil {
+			attributes, err := convertAttributes(a.NestedType.Attributes)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", a.Name, err)
+			}
+
+			attribute.AttributeNestedType = &tfjson.SchemaNestedAttributeType{
+				Attributes:  attributes,
+				NestingMode: convertObjectNesting(a.NestedType.Nesting),
+			}
+		} else {
+			typ, err := convertType(a.Type)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", a.Name, err)
+			}
+			attribute.AttributeType = typ
+		}
+
+		out[a.Name] = attribute
+	}
+
+	return out, nil
+}
+
+func convertFunction(f *tfprotov6.Function) (*tfjson.FunctionSignature, error) {
+	signature := &tfjson.FunctionSignature{
+		Description:        f.Description,
+		Summary:            f.Summary,
+		DeprecationMessage: f.DeprecationMessage,
+	}
+
+	var err error
+	if f.Return != nil {
+		if signature.ReturnType, err = convertType(f.Return.Type); err != nil {
+			return nil, fmt.Errorf("return: %w", err)
+		}
+	}
+
+	for _, p := range f.Parameters {
+		param, err := convertParameter(p)
+		if err != nil {
+			return nil, err
+		}
+		signature.Parameters = append(signature.Parameters, param)
+	}
+
+	if f.VariadicParameter != nil {
+		if signature.VariadicParameter, err = convertParameter(f.VariadicParameter); err != nil {
+			return nil, err
+		}
+	}
+
+	return signature, nil
+}
+
+func convertParameter(p *tfprotov6.FunctionParameter) (*tfjson.FunctionParameter, error) {
+	typ, err := convertType(p.Type)
+	if err != nil {
+		return nil, fmt.Errorf("parameter %s: %w", p.Name, err)
+	}
+
+	return &tfjson.FunctionParameter{
+		Name:        p.Name,
+		Description: p.Description,
+		IsNullable:  p.AllowNullValue,
+		Type:        typ,
+	}, nil
+}
+
+// convertType converts through the JSON type syntax both type systems share,
+// e.g. ["list","string"].
+func convertType(t tftypes.Type) (cty.Type, error) {
+	if t == nil {
+		return cty.NilType, nil
+	}
+
+	b, err := json.Marshal(t)
+	if err != nil {
+		return cty.NilType, err
+	}
+
+	var typ cty.Type
+	if err := typ.UnmarshalJSON(b); err != nil {
+		return cty.NilType, err
+	}
+
+	return typ, nil
+}
+
+func convertStringKind(k tfprotov6.StringKind) tfjson.SchemaDescriptionKind {
+	if k == tfprotov6.StringKindMarkdown {
+		return tfjson.SchemaDescriptionKindMarkdown
+	}
+
+	return tfjson.SchemaDescriptionKindPlain
+}
+
+func convertBlockNesting(n tfprotov6.SchemaNestedBlockNestingMode) tfjson.SchemaNestingMode {
+	switch n {
+	case tfprotov6.SchemaNestedBlockNestingModeSingle:
+		return tfjson.SchemaNestingModeSingle
+	case tfprotov6.SchemaNestedBlockNestingModeGroup:
+		return tfjson.SchemaNestingModeGroup
+	case tfprotov6.SchemaNestedBlockNestingModeList:
+		return tfjson.SchemaNestingModeList
+	case tfprotov6.SchemaNestedBlockNestingModeSet:
+		return tfjson.SchemaNestingModeSet
+	case tfprotov6.SchemaNestedBlockNestingModeMap:
+		return tfjson.SchemaNestingModeMap
+	}
+
+	return ""
+}
+
+func convertObjectNesting(n tfprotov6.SchemaObjectNestingMode) tfjson.SchemaNestingMode {
+	switch n {
+	case tfprotov6.SchemaObjectNestingModeSingle:
+		return tfjson.SchemaNestingModeSingle
+	case tfprotov6.SchemaObjectNestingModeList:
+		return tfjson.SchemaNestingModeList
+	case tfprotov6.SchemaObjectNestingModeSet:
+		return tfjson.SchemaNestingModeSet
+	case tfprotov6.SchemaObjectNestingModeMap:
+		return tfjson.SchemaNestingModeMap
+	}
+
+	return ""
+}
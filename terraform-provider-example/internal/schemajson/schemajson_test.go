@@ -0,0 +1,131 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package schemajson
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/zclconf/go-cty/cty"
+
+	"terraform-provider-example/internal/provider"
+)
+
+const testAddress = "test.com/test/example"
+
+// exportTestSchema exports the schema of the provider and parses it back,
+// the way tools reading `terraform providers schema -json` do.
+func exportTestSchema(t *testing.T) *tfjson.ProviderSchema {
+	t.Helper()
+
+	exported, err := Export(context.Background(), provider.New("test")(), testAddress)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	b, err := json.Marshal(exported)
+	if err != nil {
+		t.Fatalf("unable to encode schemas: %s", err)
+	}
+
+	var schemas tfjson.ProviderSchemas
+	if err := json.Unmarshal(b, &schemas); err != nil {
+		t.Fatalf("output is not in the format of terraform providers schema -json: %s", err)
+	}
+
+	schema, ok := schemas.Schemas[testAddress]
+	if !ok {
+		t.Fatalf("expected schema of %s, got %v", testAddress, schemas.Schemas)
+	}
+
+	return schema
+}
+
+func TestExport(t *testing.T) {
+	schema := exportTestSchema(t)
+
+	endpoint := schema.ConfigSchema.Block.Attributes["endpoint"]
+	if endpoint == nil || !endpoint.Optional || !endpoint.AttributeType.Equals(cty.String) || endpoint.DescriptionKind != tfjson.SchemaDescriptionKindMarkdown {
+		t.Errorf("expected optional markdown string endpoint, got %+v", endpoint)
+	}
+
+	if secret := schema.ConfigSchema.Block.Attributes["client_secret"]; secret == nil || !secret.Sensitive {
+		t.Errorf("expected sensitive client_secret, got %+v", secret)
+	}
+
+	regex := schema.ResourceSchemas["example_regex"]
+	if regex == nil {
+		t.Fatal("expected example_regex resource schema")
+	}
+
+	if name := regex.Block.Attributes["name"]; name == nil || !name.Required {
+		t.Errorf("expected required name, got %+v", name)
+	}
+
+	if tags := regex.Block.Attributes["tags"]; tags == nil || !tags.AttributeType.Equals(cty.Map(cty.String)) {
+		t.Errorf("expected map of strings tags, got %+v", tags)
+	}
+
+	if timeouts := regex.Block.NestedBlocks["timeouts"]; timeouts == nil || timeouts.NestingMode != tfjson.SchemaNestingModeSingle {
+		t.Errorf("expected single timeouts block, got %+v", timeouts)
+	}
+
+	network := schema.ResourceSchemas["example_server_networks"].Block.NestedBlocks["network"]
+	if network == nil || network.NestingMode != tfjson.SchemaNestingModeList || network.Block.Attributes["port"] == nil || !network.Block.Attributes["port"].Computed {
+		t.Errorf("expected network list block with computed port, got %+v", network)
+	}
+
+	setNested := schema.ResourceSchemas["example_set_nested"].Block.Attributes["set_nested"]
+	if setNested == nil || setNested.AttributeNestedType == nil || setNested.AttributeNestedType.NestingMode != tfjson.SchemaNestingModeSet {
+		t.Errorf("expected set nested attribute, got %+v", setNested)
+	}
+
+	if content := schema.ResourceSchemas["example_document"].Block.Attributes["content"]; content == nil || !content.AttributeType.Equals(cty.DynamicPseudoType) {
+		t.Errorf("expected dynamic content, got %+v", content)
+	}
+
+	if _, ok := schema.DataSourceSchemas["example_example"]; !ok {
+		t.Error("expected example_example data source schema")
+	}
+
+	resize := schema.ActionSchemas["example_vm_resize"]
+	if resize == nil || resize.Block.Attributes["flavor"] == nil || !resize.Block.Attributes["flavor"].Required {
+		t.Errorf("expected example_vm_resize action with required flavor, got %+v", resize)
+	}
+
+	list := schema.ListResourceSchemas["example_regex"]
+	if list == nil || list.Block.Attributes["tags"] == nil || !list.Block.Attributes["tags"].AttributeType.Equals(cty.Map(cty.String)) {
+		t.Errorf("expected example_regex list resource with tags filter, got %+v", list)
+	}
+
+	function := schema.Functions["example"]
+	if function == nil || !function.ReturnType.Equals(cty.String) || len(function.Parameters) != 1 || function.Parameters[0].Name != "input" {
+		t.Errorf("expected example function signature, got %+v", function)
+	}
+}
+
+func TestExport_RequiresReplace(t *testing.T) {
+	exported, err := Export(context.Background(), provider.New("test")(), testAddress)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := []string{"list_optional", "replace", "replace_if_configured"}
+	got := exported.RequiresReplace[testAddress]["example_modifier"]
+
+	if len(got) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected, got)
+		}
+	}
+
+	if paths, ok := exported.RequiresReplace[testAddress]["example_regex"]; ok {
+		t.Errorf("expected no attribute of example_regex to require replacement, got %v", paths)
+	}
+}
@@ -5,10 +5,13 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"log"
+	"os"
 
 	"terraform-provider-example/internal/provider"
+	"terraform-provider-example/internal/schemajson"
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 )
@@ -19,6 +22,9 @@ import (
 // ensure the documentation is formatted properly.
 //go:generate terraform fmt -recursive ./examples/
 
+// Generate the resources described by the OpenAPI description of terraform-service.
+//go:generate go run ./tools/resourcegen -spec ../terraform-service/server/openapi.json -out internal/provider
+
 // Run the docs generation tool, check its repository for more information on how it works and how docs
 // can be customized.
 //go:generate go run github.com/hashicorp/terraform-plugin-docs/cmd/tfplugindocs generate -provider-name scaffolding
@@ -32,17 +38,29 @@ var (
 	// https://goreleaser.com/cookbooks/using-main.version/
 )
 
+// address is the name of the provider in the registry.
+//
+// TODO: Update this string with the published name of your provider.
+// Also update the tfplugindocs generate command to either remove the
+// -provider-name flag or set its value to the updated provider name.
+const address = "test.com/test/example"
+
 func main() {
-	var debug bool
+	var debug, schemaJSON bool
 
 	flag.BoolVar(&debug, "debug", false, "set to true to run the provider with support for debuggers like delve")
+	flag.BoolVar(&schemaJSON, "schema-json", false, "print the provider schemas in the format of `terraform providers schema -json` and exit")
 	flag.Parse()
 
+	if schemaJSON {
+		if err := printSchemaJSON(); err != nil {
+			log.Fatal(err.Error())
+		}
+		return
+	}
+
 	opts := providerserver.ServeOpts{
-		// TODO: Update this string with the published name of your provider.
-		// Also update the tfplugindocs generate command to either remove the
-		// -provider-name flag or set its value to the updated provider name.
-		Address: "test.com/test/example",
+		Address: address,
 		Debug:   debug,
 	}
 
@@ -52,3 +70,14 @@ func main() {
 		log.Fatal(err.Error())
 	}
 }
+
+// printSchemaJSON writes the schemas of the provider to stdout, without
+// starting the plugin server or Terraform.
+func printSchemaJSON() error {
+	schemas, err := schemajson.Export(context.Background(), provider.New(version)(), address)
+	if err != nil {
+		return err
+	}
+
+	return json.NewEncoder(os.Stdout).Encode(schemas)
+}
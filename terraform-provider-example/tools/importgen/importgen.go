@@ -0,0 +1,134 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"terraform-provider-example/internal/provider"
+)
+
+// resourceType is a resource whose objects can be listed from the backend.
+type resourceType struct {
+	Name string
+	Path string
+	// nameAttribute is the attribute of the backend objects the resource
+	// names are derived from, the ID if empty.
+	nameAttribute string
+}
+
+var resourceTypes = map[string]resourceType{
+	"example_regex":      {Name: "example_regex", Path: "/regex", nameAttribute: "name"},
+	"example_set_nested": {Name: "example_set_nested", Path: "/set_nested"},
+}
+
+func resourceTypeNames() []string {
+	names := make([]string, 0, len(resourceTypes))
+	for name := range resourceTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// importBlock is the import block of a backend object.
+type importBlock struct {
+	Type string
+	Name string
+	Id   string
+}
+
+// list returns the import blocks of the objects of rt with all the tags, in
+// the order of the backend.
+func list(ctx context.Context, client *provider.Client, rt resourceType, tags []string) ([]importBlock, error) {
+	query := url.Values{"tag": tags}
+
+	path := rt.Path
+	if len(tags) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	var objects []map[string]interface{}
+	if err := client.Do(ctx, http.MethodGet, path, nil, &objects); err != nil {
+		return nil, fmt.Errorf("unable to list %s: %w", rt.Name, err)
+	}
+
+	blocks := make([]importBlock, 0, len(objects))
+	used := map[string]bool{}
+
+	for _, object := range objects {
+		id, _ := object["id"].(string)
+		if id == "" {
+			return nil, fmt.Errorf("%s object without id: %v", rt.Name, object)
+		}
+
+		name := id
+		if v, ok := object[rt.nameAttribute].(string); ok && v != "" {
+			name = v
+		}
+
+		blocks = append(blocks, importBlock{
+			Type: rt.Name,
+			Name: uniqueName(identifier(name), used),
+			Id:   client.QualifyID(client.Project, id),
+		})
+	}
+
+	return blocks, nil
+}
+
+var invalidIdentifierChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// identifier turns s into a Terraform resource name: '-' and characters not
+// allowed in names become '_', and names not starting with a letter or '_'
+// get a '_' prefix.
+func identifier(s string) string {
+	s = strings.ReplaceAll(invalidIdentifierChars.ReplaceAllString(s, "_"), "-", "_")
+
+	if s == "" || !(s[0] == '_' || (s[0] >= 'a' && s[0] <= 'z') || (s[0] >= 'A' && s[0] <= 'Z')) {
+		s = "_" + s
+	}
+
+	return strings.ToLower(s)
+}
+
+// uniqueName returns name, with a numeric suffix if it is already used.
+func uniqueName(name string, used map[string]bool) string {
+	unique := name
+	for i := 2; used[unique]; i++ {
+		unique = name + "_" + strconv.Itoa(i)
+	}
+	used[unique] = true
+
+	return unique
+}
+
+var importTemplate = template.Must(template.New("imports").Parse(`# Code generated by importgen from {{ .Endpoint }}.
+# Run terraform plan -generate-config-out=generated.tf to write the
+# configuration of the imported resources.
+{{ range .Blocks }}
+import {
+  to = {{ .Type }}.{{ .Name }}
+  id = {{ printf "%q" .Id }}
+}
+{{ end -}}
+`))
+
+// render writes the import blocks as Terraform configuration.
+func render(w io.Writer, endpoint string, blocks []importBlock) error {
+	return importTemplate.Execute(w, struct {
+		Endpoint string
+		Blocks   []importBlock
+	}{endpoint, blocks})
+}
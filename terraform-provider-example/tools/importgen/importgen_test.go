@@ -0,0 +1,98 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"terraform-provider-example/internal/provider"
+
+	"terraform-service/server"
+)
+
+func newTestClient(t *testing.T) *provider.Client {
+	t.Helper()
+
+	store := server.NewMemoryStore()
+	store.Regexes().Put("p1/vm-1", server.Regex{Id: "vm-1", Name: "web-1", Tags: map[string]string{"env": "prod"}})
+	store.Regexes().Put("p1/vm-2", server.Regex{Id: "vm-2", Name: "web-1", Tags: map[string]string{"env": "prod"}})
+	store.Regexes().Put("p1/vm-3", server.Regex{Id: "vm-3", Name: "db", Tags: map[string]string{"env": "dev"}})
+	store.Regexes().Put("p2/vm-4", server.Regex{Id: "vm-4", Name: "other", Tags: map[string]string{"env": "prod"}})
+	store.SetNesteds().Put("p1/nic-1", server.SetNested{Id: "nic-1"})
+
+	ts := httptest.NewServer(server.New(server.Options{Store: store}))
+	t.Cleanup(ts.Close)
+
+	client := provider.NewClient(ts.URL, http.DefaultClient)
+	client.Region = "eu"
+	client.Project = "p1"
+
+	return client
+}
+
+func TestImportBlocks(t *testing.T) {
+	client := newTestClient(t)
+
+	var blocks []importBlock
+	for _, name := range resourceTypeNames() {
+		b, err := list(context.Background(), client, resourceTypes[name], []string{"env=prod"})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		blocks = append(blocks, b...)
+	}
+
+	var buf bytes.Buffer
+	if err := render(&buf, "http://backend", blocks); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// Only objects of the project with the tag are listed, and names of
+	// VMs sharing a name are made unique.
+	expected := `# Code generated by importgen from http://backend.
+# Run terraform plan -generate-config-out=generated.tf to write the
+# configuration of the imported resources.
+
+import {
+  to = example_regex.web_1
+  id = "eu/p1/vm-1"
+}
+
+import {
+  to = example_regex.web_1_2
+  id = "eu/p1/vm-2"
+}
+`
+	if buf.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+
+	blocks, err := list(context.Background(), client, resourceTypes["example_set_nested"], nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(blocks) != 1 || blocks[0] != (importBlock{Type: "example_set_nested", Name: "nic_1", Id: "eu/p1/nic-1"}) {
+		t.Errorf("expected set_nested named after its id, got %v", blocks)
+	}
+}
+
+func TestIdentifier(t *testing.T) {
+	for s, expected := range map[string]string{
+		"Web-01":  "web_01",
+		"1st":     "_1st",
+		"测试 vm":   "_vm",
+		"a.b":     "a_b",
+		"":        "_",
+		"_hidden": "_hidden",
+	} {
+		if got := identifier(s); got != expected {
+			t.Errorf("identifier(%q): expected %q, got %q", s, expected, got)
+		}
+	}
+}
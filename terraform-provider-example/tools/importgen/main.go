@@ -0,0 +1,83 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Command importgen lists the objects of terraform-service that back
+// example_regex and example_set_nested, and writes an import block for each
+// of them:
+//
+//	go run ./tools/importgen -endpoint http://127.0.0.1:29999 -project p1 -tag env=prod > imports.tf
+//	terraform plan -generate-config-out=generated.tf
+//
+// Terraform then writes the configuration of the imported resources to
+// generated.tf. Requests are not authenticated.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"terraform-provider-example/internal/provider"
+)
+
+// tagFlags collects the repeated -tag flag.
+type tagFlags []string
+
+func (t *tagFlags) String() string { return strings.Join(*t, ",") }
+
+func (t *tagFlags) Set(v string) error {
+	*t = append(*t, v)
+	return nil
+}
+
+func main() {
+	var tags tagFlags
+
+	endpoint := flag.String("endpoint", envOr("EXAMPLE_ENDPOINT", "http://127.0.0.1:29999"), "base URL of terraform-service")
+	region := flag.String("region", os.Getenv("EXAMPLE_REGION"), "region of the backend, IDs are qualified with it")
+	project := flag.String("project", os.Getenv("EXAMPLE_PROJECT"), "project to list objects of")
+	types := flag.String("types", strings.Join(resourceTypeNames(), ","), "comma separated resource types to list")
+	flag.Var(&tags, "tag", "only list objects with the tag key=value, or with the tag key; may be repeated")
+	flag.Parse()
+
+	if flag.NArg() != 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	client := provider.NewClient(*endpoint, http.DefaultClient)
+	client.Region = *region
+	client.Project = *project
+
+	var blocks []importBlock
+	for _, name := range strings.Split(*types, ",") {
+		rt, ok := resourceTypes[strings.TrimSpace(name)]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "unknown resource type %q, expected one of %s\n", name, strings.Join(resourceTypeNames(), ", "))
+			os.Exit(2)
+		}
+
+		b, err := list(context.Background(), client, rt, tags)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		blocks = append(blocks, b...)
+	}
+
+	if err := render(os.Stdout, *endpoint, blocks); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+
+	return fallback
+}
@@ -0,0 +1,542 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// generate returns the source of the resource, formatted with gofmt.
+func generate(r *resource, source string) ([]byte, error) {
+	var buf bytes.Buffer
+
+	err := resourceTemplate.Execute(&buf, struct {
+		*resource
+		Source  string
+		Imports []string
+	}{r, source, r.imports()})
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("generated invalid code for %s: %w\n%s", r.Name, err, buf.Bytes())
+	}
+
+	return b, nil
+}
+
+func (r *resource) imports() []string {
+	imports := map[string]bool{
+		"context":  true,
+		"net/http": true,
+		"net/url":  true,
+		"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts":         true,
+		"github.com/hashicorp/terraform-plugin-framework/diag":                               true,
+		"github.com/hashicorp/terraform-plugin-framework/resource":                           true,
+		"github.com/hashicorp/terraform-plugin-framework/resource/schema":                    true,
+		"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier":       true,
+		"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier": true,
+		"github.com/hashicorp/terraform-plugin-framework/types":                              true,
+	}
+
+	if r.Async {
+		imports["fmt"] = true
+	}
+
+	if r.List {
+		imports["github.com/hashicorp/terraform-plugin-framework/list"] = true
+	}
+
+	if len(r.Objects) > 0 {
+		imports["github.com/hashicorp/terraform-plugin-framework/attr"] = true
+	}
+
+	attrs := append([]*attribute{}, r.Attributes...)
+	for _, o := range r.Objects {
+		attrs = append(attrs, o.Attributes...)
+	}
+	for _, a := range attrs {
+		if a.Custom != nil && a.Custom.Import != "" {
+			imports[a.Custom.Import] = true
+		}
+		if a.Default != "" {
+			imports["github.com/hashicorp/terraform-plugin-framework/resource/schema/"+a.Type.defaultPackage()] = true
+		}
+	}
+
+	// The standard library first, then a blank line and the rest, like
+	// goimports groups them.
+	var std, other []string
+	for path := range imports {
+		if strings.Contains(strings.SplitN(path, "/", 2)[0], ".") {
+			other = append(other, path)
+		} else {
+			std = append(std, path)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(other)
+
+	return append(append(std, ""), other...)
+}
+
+// readOnly reports whether the attribute is only set by the backend or the
+// provider.
+func (a *attribute) readOnly() bool {
+	return !a.Required && !a.Optional
+}
+
+// Field is the name of the attribute in models.
+func (a *attribute) Field() string {
+	return camelCase(a.Name)
+}
+
+// ModelType is the type of the attribute in models.
+func (a *attribute) ModelType() string {
+	switch {
+	case a.Custom != nil:
+		return a.Custom.ValueType
+	case a.Object != nil, a.Collection != "":
+		return "types." + a.Collection
+	default:
+		return "types." + a.Type.Name
+	}
+}
+
+// APIType is the type and JSON tag of the attribute in API models.
+func (a *attribute) APIType() string {
+	tag := func(t string, omitempty bool) string {
+		if omitempty {
+			return t + " `json:\"" + a.Name + ",omitempty\"`"
+		}
+		return t + " `json:\"" + a.Name + "\"`"
+	}
+
+	switch {
+	case a.Custom != nil:
+		return tag(a.Custom.GoType, true)
+	case a.Object != nil:
+		return tag("[]"+lowerCamelCase(a.Object.Name)+"APIModel", true)
+	case a.Collection == "Map":
+		return tag("map[string]"+a.Type.GoType, true)
+	case a.Collection != "":
+		return tag("[]"+a.Type.GoType, true)
+	case a.Optional:
+		return tag("*"+a.Type.GoType, true)
+	default:
+		return tag(a.Type.GoType, a.Computed)
+	}
+}
+
+// AttrType is the attr.Type of the attribute, in object type maps.
+func (a *attribute) AttrType() string {
+	switch {
+	case a.Custom != nil:
+		return a.Custom.Type
+	case a.Collection != "":
+		return "types." + a.Collection + "Type{ElemType: types." + a.Type.Name + "Type}"
+	default:
+		return "types." + a.Type.Name + "Type"
+	}
+}
+
+// elementType is the element type of lists, sets and maps.
+func (a *attribute) elementType() string {
+	if a.Object != nil {
+		return "types.ObjectType{AttrTypes: " + lowerCamelCase(a.Object.Name) + "ModelTypeMap}"
+	}
+
+	return "types." + a.Type.Name + "Type"
+}
+
+// Schema is the schema attribute.
+func (a *attribute) Schema() string {
+	var b strings.Builder
+
+	kind := "String"
+	switch {
+	case a.Object != nil:
+		kind = a.Collection + "Nested"
+	case a.Collection != "":
+		kind = a.Collection
+	case a.Type != nil:
+		kind = a.Type.Name
+	}
+
+	fmt.Fprintf(&b, "schema.%sAttribute{\n", kind)
+	fmt.Fprintf(&b, "MarkdownDescription: %s,\n", strconv.Quote(a.Description))
+
+	if a.Required {
+		b.WriteString("Required: true,\n")
+	}
+	if a.Optional {
+		b.WriteString("Optional: true,\n")
+	}
+	if a.Computed {
+		b.WriteString("Computed: true,\n")
+	}
+	if a.Default != "" {
+		fmt.Fprintf(&b, "Default: %s,\n", a.Default)
+	}
+
+	switch {
+	case a.Custom != nil:
+		fmt.Fprintf(&b, "CustomType: %s,\n", a.Custom.Type)
+	case a.Object != nil:
+		b.WriteString("NestedObject: schema.NestedAttributeObject{\nAttributes: map[string]schema.Attribute{\n")
+		for _, nested := range a.Object.Attributes {
+			fmt.Fprintf(&b, "%q: %s,\n", nested.Name, nested.Schema())
+		}
+		b.WriteString("},\n},\n")
+	case a.Collection != "":
+		fmt.Fprintf(&b, "ElementType: %s,\n", a.elementType())
+	}
+
+	b.WriteString("}")
+
+	return b.String()
+}
+
+// ToAPI copies the attribute from the model s to the API model m, appending
+// to diags.
+func (a *attribute) ToAPI() string {
+	field := a.Field()
+
+	switch {
+	case a.readOnly():
+		return ""
+	case a.Custom != nil:
+		return "m." + field + " = " + fmt.Sprintf(a.Custom.ToAPI, "s."+field, "")
+	case a.Object != nil:
+		model := camelCase(a.Object.Name) + "Model"
+		return fmt.Sprintf(`var %[1]s []%[2]s
+diags.Append(s.%[3]s.ElementsAs(ctx, &%[1]s, false)...)
+for _, e := range %[1]s {
+	v, d := e.toAPIModel(ctx)
+	diags.Append(d...)
+	m.%[3]s = append(m.%[3]s, v)
+}`, lowerCamelCase(a.Name), model, field)
+	case a.Collection != "":
+		return "diags.Append(s." + field + ".ElementsAs(ctx, &m." + field + ", false)...)"
+	case a.Optional:
+		return "m." + field + " = s." + field + ".Value" + a.Type.Name + "Pointer()"
+	default:
+		return "m." + field + " = s." + field + ".Value" + a.Type.Name + "()"
+	}
+}
+
+// FromAPI copies the attribute from the API model m to the model s,
+// appending to diags. Empty lists, sets and maps of the backend keep a
+// null attribute null, so that an omitted attribute stays distinct from
+// one configured as empty. Attributes computed by the provider are null
+// until the customizeState hook sets them.
+func (a *attribute) FromAPI() string {
+	field := a.Field()
+
+	switch {
+	case a.ProviderComputed:
+		return "s." + field + " = types." + a.Type.Name + "Null()"
+	case a.Custom != nil:
+		return "s." + field + " = " + fmt.Sprintf(a.Custom.FromAPI, "s."+field, "m."+field)
+	case a.Object != nil:
+		model := camelCase(a.Object.Name) + "Model"
+		return fmt.Sprintf(`if len(m.%[1]s) == 0 && (s.%[1]s.IsNull() || s.%[1]s.IsUnknown()) {
+	s.%[1]s = types.%[2]sNull(%[3]s)
+} else {
+	%[4]s := make([]%[5]s, len(m.%[1]s))
+	for i := range m.%[1]s {
+		diags.Append(%[4]s[i].fromAPIModel(ctx, m.%[1]s[i])...)
+	}
+
+	v, d := types.%[2]sValueFrom(ctx, %[3]s, %[4]s)
+	diags.Append(d...)
+	s.%[1]s = v
+}`, field, a.Collection, a.elementType(), lowerCamelCase(a.Name), model)
+	case a.Collection != "":
+		return fmt.Sprintf(`if len(m.%[1]s) == 0 && (s.%[1]s.IsNull() || s.%[1]s.IsUnknown()) {
+	s.%[1]s = types.%[2]sNull(%[3]s)
+} else {
+	v, d := types.%[2]sValueFrom(ctx, %[3]s, m.%[1]s)
+	diags.Append(d...)
+	s.%[1]s = v
+}`, field, a.Collection, a.elementType())
+	case a.Optional:
+		return "s." + field + " = types." + a.Type.Name + "PointerValue(m." + field + ")"
+	default:
+		return "s." + field + " = types." + a.Type.Name + "Value(m." + field + ")"
+	}
+}
+
+var resourceTemplate = template.Must(template.New("resource").Funcs(template.FuncMap{
+	"camel":      camelCase,
+	"lowerCamel": lowerCamelCase,
+}).Parse(`// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Code generated by resourcegen from {{ .Source }}. DO NOT EDIT.
+
+package provider
+
+import (
+{{- range .Imports }}
+	{{- if eq . "" }}
+{{ else }}
+	"{{ . }}"
+	{{- end }}
+{{- end }}
+)
+
+{{- $resource := printf "Resource%s" (camel .Name) }}
+{{- $model := printf "Resource%sModel" (camel .Name) }}
+{{- $apiModel := printf "%sAPIModel" (lowerCamel .Name) }}
+
+func New{{ $resource }}() resource.Resource {
+	return newCRUDResource[{{ $model }}]("{{ .Name }}", {{ $resource }}{})
+}
+{{ if .List }}
+func NewListResource{{ camel .Name }}() list.ListResource {
+	return newCRUDListResource[{{ $model }}]("{{ .Name }}", {{ $resource }}{})
+}
+{{ end }}
+// {{ $resource }} implements the schema and the backend calls of the
+// resource.
+type {{ $resource }} struct{}
+
+var _ crudOperations[{{ $model }}] = {{ $resource }}{}
+{{- if .List }}
+var _ crudLister[{{ $model }}] = {{ $resource }}{}
+{{- end }}
+
+type (
+	// {{ $model }} describes the resource data model.
+	{{ $model }} struct {
+		Id types.String ` + "`tfsdk:\"id\"`" + `
+		Project types.String ` + "`tfsdk:\"project\"`" + `
+		{{- range .Attributes }}
+		{{ .Field }} {{ .ModelType }} ` + "`tfsdk:\"{{ .Name }}\"`" + `
+		{{- end }}
+		{{- if .Tags }}
+		Tags types.Map ` + "`tfsdk:\"tags\"`" + `
+		TagsAll types.Map ` + "`tfsdk:\"tags_all\"`" + `
+		{{- end }}
+		Timeouts timeouts.Value ` + "`tfsdk:\"timeouts\"`" + `
+	}
+{{ range .Objects }}
+	{{ camel .Name }}Model struct {
+		{{- range .Attributes }}
+		{{ .Field }} {{ .ModelType }} ` + "`tfsdk:\"{{ .Name }}\"`" + `
+		{{- end }}
+	}
+{{ end }}
+	// {{ $apiModel }} is the terraform-service representation of the resource.
+	{{ $apiModel }} struct {
+		Id string ` + "`json:\"id,omitempty\"`" + `
+		{{- range .Attributes }}
+		{{- if not .ProviderComputed }}
+		{{ .Field }} {{ .APIType }}
+		{{- end }}
+		{{- end }}
+		{{- if .Tags }}
+		Tags map[string]string ` + "`json:\"tags,omitempty\"`" + `
+		{{- end }}
+	}
+{{ range .Objects }}
+	{{ lowerCamel .Name }}APIModel struct {
+		{{- range .Attributes }}
+		{{- if not .ProviderComputed }}
+		{{ .Field }} {{ .APIType }}
+		{{- end }}
+		{{- end }}
+	}
+{{ end }}
+)
+{{ range .Objects }}
+var {{ lowerCamel .Name }}ModelTypeMap = map[string]attr.Type{
+	{{- range .Attributes }}
+	"{{ .Name }}": {{ .AttrType }},
+	{{- end }}
+}
+{{ end }}
+func (r {{ $resource }}) schema(ctx context.Context) schema.Schema {
+	return schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: {{ printf "%q" .Description }},
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "ID",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			{{- range .Attributes }}
+			"{{ .Name }}": {{ .Schema }},
+			{{- end }}
+			"project": projectAttribute(),
+			{{- if .Tags }}
+			"tags": tagsAttribute(),
+			"tags_all": tagsAllAttribute(),
+			{{- end }}
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+			}),
+		},
+	}
+}
+
+func (r {{ $resource }}) create(ctx context.Context, client *Client, data *{{ $model }}) (string, error) {
+	body, diags := data.toAPIModel(ctx)
+	if err := errorFromDiags(diags); err != nil {
+		return "", err
+	}
+
+	var created {{ $apiModel }}
+{{ if .Async }}
+	// Creating the object is asynchronous, the backend answers with an operation
+	op, err := client.StartOperation(ctx, http.MethodPost, "{{ .Path }}", body)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := client.WaitForOperation(ctx, op.URL); err != nil {
+		return createdResourceID(op, err), err
+	}
+
+	err = client.Do(ctx, http.MethodGet, "{{ .Path }}/"+url.PathEscape(op.ResourceId), nil, &created)
+	if err != nil {
+		return "", fmt.Errorf("read created {{ .Name }}: %w", err)
+	}
+{{ else }}
+	if err := client.Do(ctx, http.MethodPost, "{{ .Path }}", body, &created); err != nil {
+		return "", err
+	}
+{{ end }}
+	return created.Id, errorFromDiags(data.fromAPIModel(ctx, created))
+}
+
+func (r {{ $resource }}) read(ctx context.Context, client *Client, id string, data *{{ $model }}) error {
+	var current {{ $apiModel }}
+	if err := client.Do(ctx, http.MethodGet, "{{ .Path }}/"+url.PathEscape(id), nil, &current); err != nil {
+		return err
+	}
+{{ if .Tags }}
+	data.Tags = client.refreshTags(data.Tags, current.Tags)
+{{ end }}
+	return errorFromDiags(data.fromAPIModel(ctx, current))
+}
+
+func (r {{ $resource }}) update(ctx context.Context, client *Client, id string, data *{{ $model }}) error {
+	body, diags := data.toAPIModel(ctx)
+	if err := errorFromDiags(diags); err != nil {
+		return err
+	}
+
+	var updated {{ $apiModel }}
+	if err := client.Do(ctx, http.MethodPut, "{{ .Path }}/"+url.PathEscape(id), body, &updated); err != nil {
+		return err
+	}
+
+	return errorFromDiags(data.fromAPIModel(ctx, updated))
+}
+
+func (r {{ $resource }}) delete(ctx context.Context, client *Client, id string, data *{{ $model }}) error {
+	return client.Do(ctx, http.MethodDelete, "{{ .Path }}/"+url.PathEscape(id), nil, nil)
+}
+{{ if .List }}
+func (r {{ $resource }}) list(ctx context.Context, client *Client, query url.Values, push func(id, displayName string, fill func(data *{{ $model }}) error) bool) error {
+	path := "{{ .Path }}"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	var objects []{{ $apiModel }}
+	if err := client.Do(ctx, http.MethodGet, path, nil, &objects); err != nil {
+		return err
+	}
+
+	for _, current := range objects {
+		fill := func(data *{{ $model }}) error {
+			{{- if .Tags }}
+			data.Tags = client.refreshTags(data.Tags, current.Tags)
+			{{- end }}
+			return errorFromDiags(data.fromAPIModel(ctx, current))
+		}
+
+		if !push(current.Id, {{ .DisplayName }}, fill) {
+			break
+		}
+	}
+
+	return nil
+}
+{{ end }}
+func (s *{{ $model }}) toAPIModel(ctx context.Context) ({{ $apiModel }}, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	m := {{ $apiModel }}{
+		Id: s.Id.ValueString(),
+		{{- if .Tags }}
+		Tags: expandTags(s.TagsAll),
+		{{- end }}
+	}
+	{{- range .Attributes }}
+	{{- with .ToAPI }}
+	{{ . }}
+	{{- end }}
+	{{- end }}
+
+	return m, diags
+}
+
+// fromAPIModel copies the backend object into the model, then runs the
+// customizeState hook if the resource has one.
+func (s *{{ $model }}) fromAPIModel(ctx context.Context, m {{ $apiModel }}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	{{ range .Attributes }}
+	{{ .FromAPI }}
+	{{- end }}
+	{{- if .Tags }}
+	s.TagsAll = flattenTags(m.Tags)
+	{{- end }}
+
+	if c, ok := interface{}(s).(stateCustomizer); ok && !diags.HasError() {
+		diags.Append(c.customizeState(ctx)...)
+	}
+
+	return diags
+}
+{{ range .Objects }}
+func (s *{{ camel .Name }}Model) toAPIModel(ctx context.Context) ({{ lowerCamel .Name }}APIModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	var m {{ lowerCamel .Name }}APIModel
+	{{- range .Attributes }}
+	{{- with .ToAPI }}
+	{{ . }}
+	{{- end }}
+	{{- end }}
+
+	return m, diags
+}
+
+func (s *{{ camel .Name }}Model) fromAPIModel(ctx context.Context, m {{ lowerCamel .Name }}APIModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+	{{ range .Attributes }}
+	{{ .FromAPI }}
+	{{- end }}
+
+	return diags
+}
+{{ end }}`))
@@ -0,0 +1,73 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Command resourcegen generates resources of the provider from the OpenAPI
+// description of terraform-service. It is run by go generate:
+//
+//	go run ./tools/resourcegen -spec ../terraform-service/server/openapi.json -out internal/provider
+//
+// Every collection whose path has x-terraform-resource becomes a resource
+// in resource_<name>_gen.go: the schema, the models, the attribute type
+// maps of nested objects and the CRUD operations, and the list resource for
+// terraform query if the collection lists its objects. Properties with a
+// default become optional and computed attributes with that default. Read-only
+// properties marked with x-terraform-provider-computed are not returned by
+// the backend and are left null in the API model conversion. Hand-written
+// files of the provider package customise generated resources by
+// implementing schemaCustomizer and stateCustomizer, which also computes
+// such attributes.
+//
+// The server_networks and document collections have no x-terraform-resource
+// and their resources are hand-written: example_server_networks models the
+// networks and security_groups arrays as network and security_group blocks
+// planned by uuid, and example_document stores a dynamic attribute as raw
+// JSON. Neither maps one to one to the properties of the collection.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	spec := flag.String("spec", "", "OpenAPI description of terraform-service")
+	out := flag.String("out", ".", "directory of the provider package")
+	flag.Parse()
+
+	if *spec == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(*spec, *out); err != nil {
+		fmt.Fprintln(os.Stderr, "resourcegen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(spec, out string) error {
+	doc, err := readOpenAPIDocument(spec)
+	if err != nil {
+		return err
+	}
+
+	rs, err := resources(doc)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range rs {
+		b, err := generate(r, filepath.ToSlash(spec))
+		if err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(filepath.Join(out, "resource_"+r.Name+"_gen.go"), b, 0o644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
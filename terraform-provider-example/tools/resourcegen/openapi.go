@@ -0,0 +1,167 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// The subset of OpenAPI 3 the generator reads.
+type (
+	openAPIDocument struct {
+		Paths      map[string]*openAPIPathItem `json:"paths"`
+		Components struct {
+			Schemas map[string]*openAPISchema `json:"schemas"`
+		} `json:"components"`
+	}
+
+	openAPIPathItem struct {
+		// Resource names the Terraform resource generated from the
+		// collection at this path, without the provider prefix.
+		Resource string `json:"x-terraform-resource"`
+
+		Get    *openAPIOperation `json:"get"`
+		Post   *openAPIOperation `json:"post"`
+		Put    *openAPIOperation `json:"put"`
+		Delete *openAPIOperation `json:"delete"`
+	}
+
+	openAPIOperation struct {
+		RequestBody *struct {
+			Content map[string]openAPIMediaType `json:"content"`
+		} `json:"requestBody"`
+		Responses map[string]struct {
+			Content map[string]openAPIMediaType `json:"content"`
+		} `json:"responses"`
+	}
+
+	openAPIMediaType struct {
+		Schema *openAPISchema `json:"schema"`
+	}
+
+	openAPISchema struct {
+		Ref                  string            `json:"$ref"`
+		Type                 string            `json:"type"`
+		Description          string            `json:"description"`
+		Required             []string          `json:"required"`
+		Properties           openAPIProperties `json:"properties"`
+		Items                *openAPISchema    `json:"items"`
+		AdditionalProperties *openAPISchema    `json:"-"`
+		UniqueItems          bool              `json:"uniqueItems"`
+		ReadOnly             bool              `json:"readOnly"`
+
+		// Default is the value the backend assumes when the property is
+		// omitted, the attribute defaults to it as well.
+		Default json.RawMessage `json:"default"`
+
+		// CustomType names a custom type of the provider the property is
+		// converted to, see customTypes.
+		CustomType string `json:"x-terraform-custom-type"`
+
+		// ProviderComputed marks read-only properties the backend does not
+		// return. They are computed by the customizeState hook of the
+		// resource.
+		ProviderComputed bool `json:"x-terraform-provider-computed"`
+	}
+)
+
+func (s *openAPISchema) UnmarshalJSON(b []byte) error {
+	type plain openAPISchema
+
+	var v struct {
+		*plain
+		AdditionalProperties json.RawMessage `json:"additionalProperties"`
+	}
+	v.plain = (*plain)(s)
+
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	// additionalProperties is either a schema or a boolean.
+	if len(v.AdditionalProperties) > 0 && v.AdditionalProperties[0] == '{' {
+		return json.Unmarshal(v.AdditionalProperties, &s.AdditionalProperties)
+	}
+
+	return nil
+}
+
+// openAPIProperties are the properties of an object schema, in the order of
+// the document so that generated code follows it.
+type openAPIProperties struct {
+	Names   []string
+	Schemas map[string]*openAPISchema
+}
+
+func (p *openAPIProperties) UnmarshalJSON(b []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return fmt.Errorf("properties must be an object")
+	}
+
+	p.Schemas = map[string]*openAPISchema{}
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		name, ok := tok.(string)
+		if !ok {
+			return fmt.Errorf("expected property name, got %v", tok)
+		}
+
+		var s openAPISchema
+		if err := dec.Decode(&s); err != nil {
+			return fmt.Errorf("property %s: %w", name, err)
+		}
+
+		p.Names = append(p.Names, name)
+		p.Schemas[name] = &s
+	}
+
+	_, err := dec.Token()
+	return err
+}
+
+func readOpenAPIDocument(name string) (*openAPIDocument, error) {
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc openAPIDocument
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, fmt.Errorf("unable to parse %s: %w", name, err)
+	}
+
+	return &doc, nil
+}
+
+// resolve follows the reference of s to a schema of the components.
+func (doc *openAPIDocument) resolve(s *openAPISchema) (string, *openAPISchema, error) {
+	if s == nil || s.Ref == "" {
+		return "", s, nil
+	}
+
+	name := strings.TrimPrefix(s.Ref, "#/components/schemas/")
+
+	resolved, ok := doc.Components.Schemas[name]
+	if !ok || name == s.Ref {
+		return "", nil, fmt.Errorf("unresolved reference %s", s.Ref)
+	}
+
+	return name, resolved, nil
+}
+
+// jsonSchema returns the schema of the application/json content.
+func jsonSchema(content map[string]openAPIMediaType) *openAPISchema {
+	return content["application/json"].Schema
+}
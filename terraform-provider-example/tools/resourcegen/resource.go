@@ -0,0 +1,381 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// resource is a Terraform resource generated from a collection of
+// terraform-service: GET, PUT and DELETE on Path/{id}, and POST on Path,
+// which either creates the object or starts an operation creating it.
+// Collections answering GET on Path with their objects also get a list
+// resource, with the tags filter if the objects have tags.
+type resource struct {
+	Name        string
+	Path        string
+	Description string
+	Async       bool
+	List        bool
+
+	Attributes []*attribute
+	Objects    []*object
+	Tags       bool
+}
+
+// object is a nested object, of a list or set attribute.
+type object struct {
+	Name       string
+	Attributes []*attribute
+}
+
+type attribute struct {
+	Name        string
+	Description string
+	Required    bool
+	Optional    bool
+	Computed    bool
+
+	// Type is the scalar type of the attribute, or of its elements if
+	// Collection is set.
+	Type       *scalarType
+	Collection string
+	Object     *object
+	Custom     *customType
+
+	// Default is the Go expression of the static default, e.g.
+	// "booldefault.StaticBool(false)".
+	Default string
+
+	// ProviderComputed attributes are not part of the API model, see
+	// openAPISchema.ProviderComputed.
+	ProviderComputed bool
+}
+
+// scalarType maps a JSON type to the framework.
+type scalarType struct {
+	Name   string // e.g. "String", as in types.String and schema.StringAttribute
+	GoType string
+}
+
+// defaultPackage is the package of the static defaults of the type, e.g.
+// "booldefault".
+func (t *scalarType) defaultPackage() string {
+	return strings.ToLower(t.Name) + "default"
+}
+
+var scalarTypes = map[string]*scalarType{
+	"string":  {Name: "String", GoType: "string"},
+	"integer": {Name: "Int64", GoType: "int64"},
+	"number":  {Name: "Float64", GoType: "float64"},
+	"boolean": {Name: "Bool", GoType: "bool"},
+}
+
+// customType is a custom type of the provider an attribute is converted
+// to, named by x-terraform-custom-type.
+type customType struct {
+	// ValueType is the type of the model field, Type the attr.Type of the
+	// schema, GoType the type of the API model field.
+	ValueType string
+	Type      string
+	GoType    string
+	Import    string
+
+	// ToAPI and FromAPI convert between the model and the API model, with
+	// %[1]s the model field and %[2]s the API model field.
+	ToAPI   string
+	FromAPI string
+}
+
+var customTypes = map[string]*customType{
+	"NormalizedJSON": {
+		ValueType: "NormalizedJSON",
+		Type:      "NormalizedJSONType{}",
+		GoType:    "json.RawMessage",
+		Import:    "encoding/json",
+		ToAPI:     "%[1]s.ValueRaw()",
+		FromAPI:   "newNormalizedJSONFromRaw(%[2]s)",
+	},
+}
+
+// resources returns the resources of the paths of doc marked with
+// x-terraform-resource, sorted by name.
+func resources(doc *openAPIDocument) ([]*resource, error) {
+	var out []*resource
+
+	for path, item := range doc.Paths {
+		if item.Resource == "" {
+			continue
+		}
+
+		r, err := newResource(doc, path, item)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		out = append(out, r)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Name < out[j].Name
+	})
+
+	return out, nil
+}
+
+func newResource(doc *openAPIDocument, path string, item *openAPIPathItem) (*resource, error) {
+	detail := doc.Paths[path+"/{id}"]
+	if item.Post == nil || detail == nil || detail.Get == nil || detail.Put == nil || detail.Delete == nil {
+		return nil, fmt.Errorf("resources need POST %[1]s and GET, PUT and DELETE %[1]s/{id}", path)
+	}
+
+	r := &resource{
+		Name: item.Resource,
+		Path: path,
+	}
+
+	created, async := item.Post.Responses["201"], false
+	if _, ok := item.Post.Responses["202"]; ok {
+		async = true
+	} else if jsonSchema(created.Content) == nil {
+		return nil, fmt.Errorf("POST %s must answer 201 with the object or 202 with an operation", path)
+	}
+	r.Async = async
+
+	if item.Get != nil {
+		_, objects, err := doc.resolve(jsonSchema(item.Get.Responses["200"].Content))
+		if err != nil {
+			return nil, err
+		}
+		r.List = objects != nil && objects.Type == "array"
+	}
+
+	_, s, err := doc.resolve(jsonSchema(detail.Get.Responses["200"].Content))
+	if err != nil {
+		return nil, err
+	}
+	if s == nil || s.Type != "object" {
+		return nil, fmt.Errorf("GET %s/{id} must answer 200 with an object", path)
+	}
+
+	if id := s.Properties.Schemas["id"]; id == nil || id.Type != "string" || !id.ReadOnly {
+		return nil, fmt.Errorf("resources need a read-only string id")
+	}
+
+	r.Description = s.Description
+
+	for _, name := range s.Properties.Names {
+		p := s.Properties.Schemas[name]
+
+		// id, tags and the project are common to all resources.
+		switch name {
+		case "id":
+			continue
+		case "tags":
+			r.Tags = true
+			continue
+		}
+
+		a, err := r.newAttribute(doc, name, p, contains(s.Required, name), false)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+
+		r.Attributes = append(r.Attributes, a)
+	}
+
+	return r, nil
+}
+
+// DisplayName is the Go expression of the display name of the API model
+// current in list results: its name, or its ID if it has none.
+func (r *resource) DisplayName() string {
+	for _, a := range r.Attributes {
+		if a.Name == "name" && a.Required && a.Collection == "" && a.Custom == nil && a.Type.Name == "String" {
+			return "current." + a.Field()
+		}
+	}
+
+	return "current.Id"
+}
+
+func (r *resource) newAttribute(doc *openAPIDocument, name string, p *openAPISchema, required, nested bool) (*attribute, error) {
+	a := &attribute{
+		Name:             name,
+		Description:      p.Description,
+		Required:         required && !p.ReadOnly,
+		Optional:         !required && !p.ReadOnly,
+		Computed:         p.ReadOnly,
+		ProviderComputed: p.ProviderComputed,
+	}
+
+	if p.ProviderComputed && (!p.ReadOnly || scalarTypes[p.Type] == nil) {
+		return nil, fmt.Errorf("x-terraform-provider-computed is only supported for read-only scalars")
+	}
+
+	if len(p.Default) > 0 {
+		if err := a.setDefault(p); err != nil {
+			return nil, err
+		}
+		return a, nil
+	}
+
+	if p.CustomType != "" {
+		a.Custom = customTypes[p.CustomType]
+		if a.Custom == nil {
+			return nil, fmt.Errorf("unknown custom type %s", p.CustomType)
+		}
+		return a, nil
+	}
+
+	switch p.Type {
+	case "array":
+		a.Collection = "List"
+		if p.UniqueItems {
+			a.Collection = "Set"
+		}
+
+		objectName, items, err := doc.resolve(p.Items)
+		if err != nil {
+			return nil, err
+		}
+		if items == nil {
+			return nil, fmt.Errorf("arrays need items")
+		}
+
+		if items.Type != "object" {
+			a.Type = scalarTypes[items.Type]
+			break
+		}
+
+		if nested {
+			return nil, fmt.Errorf("objects nested in nested objects are not supported")
+		}
+		if objectName == "" {
+			return nil, fmt.Errorf("nested objects must be components")
+		}
+
+		a.Object, err = r.newObject(doc, objectName, items)
+		if err != nil {
+			return nil, err
+		}
+
+		return a, nil
+	case "object":
+		if p.AdditionalProperties == nil {
+			return nil, fmt.Errorf("objects need additionalProperties or a custom type")
+		}
+
+		a.Collection = "Map"
+		a.Type = scalarTypes[p.AdditionalProperties.Type]
+	default:
+		a.Type = scalarTypes[p.Type]
+	}
+
+	if a.Type == nil {
+		return nil, fmt.Errorf("unsupported type")
+	}
+
+	return a, nil
+}
+
+// setDefault makes a an optional and computed scalar with the default of p.
+func (a *attribute) setDefault(p *openAPISchema) error {
+	a.Type = scalarTypes[p.Type]
+	if a.Type == nil || p.CustomType != "" || !a.Optional {
+		return fmt.Errorf("defaults are only supported for optional scalars")
+	}
+
+	var value string
+	var err error
+	switch a.Type.Name {
+	case "Bool":
+		var v bool
+		err = json.Unmarshal(p.Default, &v)
+		value = strconv.FormatBool(v)
+	case "String":
+		var v string
+		err = json.Unmarshal(p.Default, &v)
+		value = strconv.Quote(v)
+	case "Int64":
+		var v int64
+		err = json.Unmarshal(p.Default, &v)
+		value = strconv.FormatInt(v, 10)
+	case "Float64":
+		var v float64
+		err = json.Unmarshal(p.Default, &v)
+		value = strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	if err != nil {
+		return fmt.Errorf("default %s does not match type %s", p.Default, p.Type)
+	}
+
+	a.Computed = true
+	a.Default = fmt.Sprintf("%s.Static%s(%s)", a.Type.defaultPackage(), a.Type.Name, value)
+
+	return nil
+}
+
+func (r *resource) newObject(doc *openAPIDocument, name string, s *openAPISchema) (*object, error) {
+	for _, o := range r.Objects {
+		if o.Name == name {
+			return o, nil
+		}
+	}
+
+	o := &object{Name: name}
+
+	for _, attrName := range s.Properties.Names {
+		a, err := r.newAttribute(doc, attrName, s.Properties.Schemas[attrName], contains(s.Required, attrName), true)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", name, attrName, err)
+		}
+
+		o.Attributes = append(o.Attributes, a)
+	}
+
+	r.Objects = append(r.Objects, o)
+
+	return o, nil
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// camelCase converts snake_case names of the document to Go names, e.g.
+// "user_data_json" to "UserDataJson", like the hand-written resources do.
+func camelCase(name string) string {
+	var b strings.Builder
+
+	for _, part := range strings.Split(name, "_") {
+		if part == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+
+	return b.String()
+}
+
+// lowerCamelCase converts names to unexported Go names, e.g. "SetNested" to
+// "setNested".
+func lowerCamelCase(name string) string {
+	name = camelCase(name)
+	if name == "" {
+		return name
+	}
+
+	return strings.ToLower(name[:1]) + name[1:]
+}
@@ -0,0 +1,164 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testSpec = "../../../terraform-service/server/openapi.json"
+
+func TestGenerate_UpToDate(t *testing.T) {
+	out := t.TempDir()
+
+	if err := run(testSpec, out); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	generated, err := filepath.Glob(filepath.Join(out, "*.go"))
+	if err != nil || len(generated) == 0 {
+		t.Fatalf("expected generated files, got %v", generated)
+	}
+
+	for _, name := range generated {
+		got, _ := os.ReadFile(name)
+		committed, err := os.ReadFile(filepath.Join("../../internal/provider", filepath.Base(name)))
+		if err != nil {
+			t.Errorf("%s is not in the provider package, run go generate: %s", filepath.Base(name), err)
+			continue
+		}
+
+		// The generated files name the document relative to the module.
+		got = bytes.Replace(got, []byte(testSpec), []byte("../terraform-service/server/openapi.json"), 1)
+
+		if !bytes.Equal(got, committed) {
+			t.Errorf("%s is out of date with the OpenAPI description, run go generate", filepath.Base(name))
+		}
+	}
+}
+
+func TestGenerate_NestedObjects(t *testing.T) {
+	doc, err := readOpenAPIDocument(testSpec)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	doc.Paths["/server_networks"].Resource = "server_networks"
+
+	rs, err := resources(doc)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	sources := map[string]string{}
+	for _, r := range rs {
+		b, err := generate(r, "openapi.json")
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		sources[r.Name] = string(b)
+	}
+
+	for name, expected := range map[string][]string{
+		"set_nested": {
+			"SetNested types.Set `tfsdk:\"set_nested\"`",
+			"SetNested []nicAPIModel `json:\"set_nested,omitempty\"`",
+			"var nicModelTypeMap = map[string]attr.Type{",
+			`"enable_gateway": types.BoolType,`,
+			`"set_nested": schema.SetNestedAttribute{`,
+			`op, err := client.StartOperation(ctx, http.MethodPost, "/set_nested", body)`,
+			`return createdResourceID(op, err), err`,
+			// Properties with a default are optional and computed.
+			"EnableGateway *bool `json:\"enable_gateway,omitempty\"`",
+			`Optional: true, Computed: true, Default: booldefault.StaticBool(false),`,
+			// Properties computed by the provider are not sent or read.
+			"Mac types.String `tfsdk:\"mac\"`",
+			`s.Mac = types.StringNull()`,
+			// Collections listing their objects get a list resource,
+			// objects without name are displayed by their ID.
+			`return newCRUDListResource[ResourceSetNestedModel]("set_nested", ResourceSetNested{})`,
+			`if !push(current.Id, current.Id, fill) {`,
+		},
+		"server_networks": {
+			"Networks types.List `tfsdk:\"networks\"`",
+			"SecurityGroups []string `json:\"security_groups,omitempty\"`",
+			`"security_groups": schema.SetAttribute{`,
+			"Port string `json:\"port,omitempty\"`",
+			`"primary": types.BoolType,`,
+		},
+	} {
+		source := strings.Join(strings.Fields(sources[name]), " ")
+
+		for _, e := range expected {
+			if !strings.Contains(source, strings.Join(strings.Fields(e), " ")) {
+				t.Errorf("expected %s to contain %s", name, e)
+			}
+		}
+	}
+}
+
+func TestResources_Invalid(t *testing.T) {
+	testCases := map[string]struct {
+		schema   string
+		expected string
+	}{
+		"nested-in-nested": {
+			schema:   `{"type": "array", "items": {"$ref": "#/components/schemas/Outer"}}`,
+			expected: "objects nested in nested objects are not supported",
+		},
+		"unknown-custom-type": {
+			schema:   `{"type": "string", "x-terraform-custom-type": "Duration"}`,
+			expected: "unknown custom type Duration",
+		},
+		"free-form-object": {
+			schema:   `{"type": "object"}`,
+			expected: "objects need additionalProperties or a custom type",
+		},
+		"invalid-default": {
+			schema:   `{"type": "integer", "default": "one"}`,
+			expected: `default "one" does not match type integer`,
+		},
+		"default-of-list": {
+			schema:   `{"type": "array", "items": {"type": "string"}, "default": []}`,
+			expected: "defaults are only supported for optional scalars",
+		},
+		"provider-computed-writable": {
+			schema:   `{"type": "string", "x-terraform-provider-computed": true}`,
+			expected: "x-terraform-provider-computed is only supported for read-only scalars",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			spec := filepath.Join(t.TempDir(), "openapi.json")
+			err := os.WriteFile(spec, []byte(`{
+				"paths": {
+					"/vm": {"x-terraform-resource": "vm", "post": {"responses": {"202": {}}}},
+					"/vm/{id}": {
+						"get": {"responses": {"200": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Vm"}}}}}},
+						"put": {"responses": {}},
+						"delete": {"responses": {}}
+					}
+				},
+				"components": {"schemas": {
+					"Vm": {"type": "object", "properties": {"id": {"type": "string", "readOnly": true}, "field": `+testCase.schema+`}},
+					"Outer": {"type": "object", "properties": {"inner": {"type": "array", "items": {"$ref": "#/components/schemas/Inner"}}}},
+					"Inner": {"type": "object", "properties": {"name": {"type": "string"}}}
+				}}
+			}`), 0o600)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = run(spec, t.TempDir())
+			if err == nil || !strings.Contains(err.Error(), testCase.expected) {
+				t.Errorf("expected error %q, got %v", testCase.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,268 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/zclconf/go-cty/cty"
+
+	"terraform-provider-example/internal/schemajson"
+)
+
+// breakingChange is a change of the schema that breaks configurations or
+// state written against the old schema.
+type breakingChange struct {
+	// Path locates the change, e.g. "resource example_set_nested: set_nested.enable_gateway".
+	Path    string
+	Message string
+}
+
+func (c breakingChange) String() string {
+	return c.Path + ": " + c.Message
+}
+
+// checker collects the breaking changes between two snapshots.
+type checker struct {
+	changes []breakingChange
+}
+
+func (c *checker) report(path, format string, a ...interface{}) {
+	c.changes = append(c.changes, breakingChange{Path: path, Message: fmt.Sprintf(format, a...)})
+}
+
+// compare returns the breaking changes from before to after, sorted.
+func compare(before, after *schemajson.Snapshot) []breakingChange {
+	c := &checker{}
+
+	for address, beforeSchema := range before.Schemas {
+		afterSchema, ok := after.Schemas[address]
+		if !ok {
+			c.report(address, "provider removed")
+			continue
+		}
+
+		c.provider(beforeSchema, afterSchema)
+
+		for typeName, paths := range after.RequiresReplace[address] {
+			if _, ok := beforeSchema.ResourceSchemas[typeName]; !ok {
+				continue
+			}
+
+			replaced := map[string]bool{}
+			for _, p := range before.RequiresReplace[address][typeName] {
+				replaced[p] = true
+			}
+
+			for _, p := range paths {
+				if !replaced[p] {
+					c.report("resource "+typeName+": "+p, "changes now require replacement")
+				}
+			}
+		}
+	}
+
+	sort.Slice(c.changes, func(i, j int) bool {
+		return c.changes[i].String() < c.changes[j].String()
+	})
+
+	return c.changes
+}
+
+func (c *checker) provider(before, after *tfjson.ProviderSchema) {
+	if before.ConfigSchema != nil && after.ConfigSchema != nil {
+		c.block("provider:", before.ConfigSchema.Block, after.ConfigSchema.Block, false)
+	}
+
+	c.schemas("resource", before.ResourceSchemas, after.ResourceSchemas)
+	c.schemas("data source", before.DataSourceSchemas, after.DataSourceSchemas)
+	c.schemas("list resource", before.ListResourceSchemas, after.ListResourceSchemas)
+
+	for name, beforeAction := range before.ActionSchemas {
+		afterAction, ok := after.ActionSchemas[name]
+		if !ok {
+			c.report("action "+name, "removed")
+			continue
+		}
+
+		c.block("action "+name+":", beforeAction.Block, afterAction.Block, false)
+	}
+
+	for name, beforeFunction := range before.Functions {
+		afterFunction, ok := after.Functions[name]
+		if !ok {
+			c.report("function "+name, "removed")
+			continue
+		}
+
+		c.function("function "+name, beforeFunction, afterFunction)
+	}
+}
+
+func (c *checker) schemas(kind string, before, after map[string]*tfjson.Schema) {
+	for name, beforeSchema := range before {
+		afterSchema, ok := after[name]
+		if !ok {
+			c.report(kind+" "+name, "removed")
+			continue
+		}
+
+		c.block(kind+" "+name+":", beforeSchema.Block, afterSchema.Block, false)
+	}
+}
+
+// block compares the attributes and nested blocks of a block. Elements of
+// sets are identified by their whole value, so inSet reports any attribute
+// added or removed below a set, which changes the identity of existing
+// elements.
+func (c *checker) block(prefix string, before, after *tfjson.SchemaBlock, inSet bool) {
+	if before == nil || after == nil {
+		return
+	}
+
+	for name, beforeAttr := range before.Attributes {
+		path := join(prefix, name)
+
+		afterAttr, ok := after.Attributes[name]
+		if !ok {
+			c.report(path, "attribute removed")
+			continue
+		}
+
+		c.attribute(path, beforeAttr, afterAttr, inSet)
+	}
+
+	for name, afterAttr := range after.Attributes {
+		if _, ok := before.Attributes[name]; ok {
+			continue
+		}
+
+		path := join(prefix, name)
+
+		switch {
+		case afterAttr.Required:
+			c.report(path, "new required attribute")
+		case inSet:
+			c.report(path, "attribute added to set elements, which changes their identity")
+		}
+	}
+
+	for name, beforeBlock := range before.NestedBlocks {
+		path := join(prefix, name)
+
+		afterBlock, ok := after.NestedBlocks[name]
+		if !ok {
+			c.report(path, "block removed")
+			continue
+		}
+
+		if beforeBlock.NestingMode != afterBlock.NestingMode {
+			c.report(path, "nesting mode changed from %s to %s", beforeBlock.NestingMode, afterBlock.NestingMode)
+			continue
+		}
+
+		if afterBlock.MinItems > beforeBlock.MinItems {
+			c.report(path, "min_items increased from %d to %d", beforeBlock.MinItems, afterBlock.MinItems)
+		}
+
+		if afterBlock.MaxItems != 0 && (beforeBlock.MaxItems == 0 || afterBlock.MaxItems < beforeBlock.MaxItems) {
+			c.report(path, "max_items decreased from %s to %d", maxItems(beforeBlock.MaxItems), afterBlock.MaxItems)
+		}
+
+		c.block(path, beforeBlock.Block, afterBlock.Block, inSet || afterBlock.NestingMode == tfjson.SchemaNestingModeSet)
+	}
+
+	for name, afterBlock := range after.NestedBlocks {
+		if _, ok := before.NestedBlocks[name]; ok {
+			continue
+		}
+
+		path := join(prefix, name)
+
+		switch {
+		case afterBlock.MinItems > 0:
+			c.report(path, "new block with min_items %d", afterBlock.MinItems)
+		case inSet:
+			c.report(path, "block added to set elements, which changes their identity")
+		}
+	}
+}
+
+func (c *checker) attribute(path string, before, after *tfjson.SchemaAttribute, inSet bool) {
+	if !before.Required && after.Required {
+		c.report(path, "changed from optional to required")
+	}
+
+	if (before.Required || before.Optional) && !after.Required && !after.Optional {
+		c.report(path, "no longer configurable")
+	}
+
+	switch {
+	case before.AttributeNestedType != nil && after.AttributeNestedType != nil:
+		beforeNested, afterNested := before.AttributeNestedType, after.AttributeNestedType
+
+		if beforeNested.NestingMode != afterNested.NestingMode {
+			c.report(path, "nesting mode changed from %s to %s", beforeNested.NestingMode, afterNested.NestingMode)
+			return
+		}
+
+		c.block(path, &tfjson.SchemaBlock{Attributes: beforeNested.Attributes}, &tfjson.SchemaBlock{Attributes: afterNested.Attributes},
+			inSet || afterNested.NestingMode == tfjson.SchemaNestingModeSet)
+	case before.AttributeNestedType != nil || after.AttributeNestedType != nil:
+		c.report(path, "type changed from %s to %s", attributeType(before), attributeType(after))
+	case !before.AttributeType.Equals(after.AttributeType):
+		c.report(path, "type changed from %s to %s", attributeType(before), attributeType(after))
+	}
+}
+
+func (c *checker) function(path string, before, after *tfjson.FunctionSignature) {
+	if len(before.Parameters) != len(after.Parameters) {
+		c.report(path, "number of parameters changed from %d to %d", len(before.Parameters), len(after.Parameters))
+	} else {
+		for i, p := range before.Parameters {
+			if !p.Type.Equals(after.Parameters[i].Type) {
+				c.report(path, "type of parameter %s changed from %s to %s", p.Name, p.Type.FriendlyName(), after.Parameters[i].Type.FriendlyName())
+			}
+		}
+	}
+
+	if (before.VariadicParameter == nil) != (after.VariadicParameter == nil) ||
+		before.VariadicParameter != nil && !before.VariadicParameter.Type.Equals(after.VariadicParameter.Type) {
+		c.report(path, "variadic parameter changed")
+	}
+
+	if !before.ReturnType.Equals(after.ReturnType) {
+		c.report(path, "return type changed from %s to %s", before.ReturnType.FriendlyName(), after.ReturnType.FriendlyName())
+	}
+}
+
+func join(prefix, name string) string {
+	if prefix[len(prefix)-1] == ':' {
+		return prefix + " " + name
+	}
+
+	return prefix + "." + name
+}
+
+func attributeType(a *tfjson.SchemaAttribute) string {
+	if a.AttributeNestedType != nil {
+		return string(a.AttributeNestedType.NestingMode) + " of objects"
+	}
+
+	if a.AttributeType == cty.NilType {
+		return "unknown type"
+	}
+
+	return a.AttributeType.FriendlyName()
+}
+
+func maxItems(n uint64) string {
+	if n == 0 {
+		return "unlimited"
+	}
+
+	return fmt.Sprint(n)
+}
@@ -0,0 +1,248 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"terraform-provider-example/internal/provider"
+	"terraform-provider-example/internal/schemajson"
+)
+
+const testAddress = "test.com/test/example"
+
+// exportTestSnapshot exports the schema of the provider and parses it back,
+// so that old and new snapshots never share values.
+func exportTestSnapshot(t *testing.T) *schemajson.Snapshot {
+	t.Helper()
+
+	exported, err := schemajson.Export(context.Background(), provider.New("test")(), testAddress)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	b, err := json.Marshal(exported)
+	if err != nil {
+		t.Fatalf("unable to encode schemas: %s", err)
+	}
+
+	var s schemajson.Snapshot
+	if err := json.Unmarshal(b, &s); err != nil {
+		t.Fatalf("unable to parse schemas: %s", err)
+	}
+
+	return &s
+}
+
+func TestCompare_Provider(t *testing.T) {
+	before := exportTestSnapshot(t)
+
+	if changes := compare(before, exportTestSnapshot(t)); len(changes) != 0 {
+		t.Fatalf("expected no breaking changes between identical schemas, got %v", changes)
+	}
+
+	// Making enable_gateway required breaks every configuration that
+	// leaves it unset.
+	after := exportTestSnapshot(t)
+	gateway := after.Schemas[testAddress].ResourceSchemas["example_set_nested"].Block.Attributes["set_nested"].AttributeNestedType.Attributes["enable_gateway"]
+	gateway.Optional = false
+	gateway.Computed = false
+	gateway.Required = true
+
+	requireChanges(t, compare(before, after), []string{
+		"resource example_set_nested: set_nested.enable_gateway: changed from optional to required",
+	})
+}
+
+func TestCompare(t *testing.T) {
+	testCases := map[string]struct {
+		before, after string
+		expected      []string
+	}{
+		"compatible": {
+			before: `{"resource_schemas": {"example_vm": {"block": {"attributes": {
+				"name": {"type": "string", "required": true}
+			}}}}}`,
+			after: `{"resource_schemas": {"example_vm": {"block": {"attributes": {
+				"name": {"type": "string", "required": true},
+				"tags": {"type": ["map", "string"], "optional": true}
+			}}}, "example_nic": {"block": {}}}}`,
+		},
+		"resource-removed": {
+			before:   `{"resource_schemas": {"example_vm": {"block": {}}}, "data_source_schemas": {"example_vm": {"block": {}}}}`,
+			after:    `{}`,
+			expected: []string{"data source example_vm: removed", "resource example_vm: removed"},
+		},
+		"attribute-removed": {
+			before: `{"resource_schemas": {"example_vm": {"block": {"attributes": {
+				"name": {"type": "string", "required": true}
+			}}}}}`,
+			after:    `{"resource_schemas": {"example_vm": {"block": {}}}}`,
+			expected: []string{"resource example_vm: name: attribute removed"},
+		},
+		"attribute-required": {
+			before: `{"resource_schemas": {"example_vm": {"block": {"attributes": {
+				"name": {"type": "string", "optional": true}
+			}}}}}`,
+			after: `{"resource_schemas": {"example_vm": {"block": {"attributes": {
+				"name": {"type": "string", "required": true},
+				"flavor": {"type": "string", "required": true}
+			}}}}}`,
+			expected: []string{
+				"resource example_vm: flavor: new required attribute",
+				"resource example_vm: name: changed from optional to required",
+			},
+		},
+		"attribute-computed": {
+			before: `{"resource_schemas": {"example_vm": {"block": {"attributes": {
+				"name": {"type": "string", "optional": true}
+			}}}}}`,
+			after: `{"resource_schemas": {"example_vm": {"block": {"attributes": {
+				"name": {"type": "string", "computed": true}
+			}}}}}`,
+			expected: []string{"resource example_vm: name: no longer configurable"},
+		},
+		"type-changed": {
+			before: `{"provider": {"block": {"attributes": {
+				"max_attempts": {"type": "number", "optional": true}
+			}}}, "resource_schemas": {"example_vm": {"block": {"attributes": {
+				"tags": {"type": ["map", "string"], "optional": true}
+			}}}}}`,
+			after: `{"provider": {"block": {"attributes": {
+				"max_attempts": {"type": "string", "optional": true}
+			}}}, "resource_schemas": {"example_vm": {"block": {"attributes": {
+				"tags": {"type": ["list", "string"], "optional": true}
+			}}}}}`,
+			expected: []string{
+				"provider: max_attempts: type changed from number to string",
+				"resource example_vm: tags: type changed from map of string to list of string",
+			},
+		},
+		"nested-block": {
+			before: `{"resource_schemas": {"example_vm": {"block": {"block_types": {
+				"network": {"nesting_mode": "list", "block": {}},
+				"disk": {"nesting_mode": "list", "max_items": 4, "block": {}}
+			}}}}}`,
+			after: `{"resource_schemas": {"example_vm": {"block": {"block_types": {
+				"network": {"nesting_mode": "set", "block": {}},
+				"disk": {"nesting_mode": "list", "min_items": 1, "max_items": 2, "block": {}},
+				"boot": {"nesting_mode": "single", "min_items": 1, "block": {}}
+			}}}}}`,
+			expected: []string{
+				"resource example_vm: boot: new block with min_items 1",
+				"resource example_vm: disk: max_items decreased from 4 to 2",
+				"resource example_vm: disk: min_items increased from 0 to 1",
+				"resource example_vm: network: nesting mode changed from list to set",
+			},
+		},
+		"set-element-identity": {
+			before: `{"resource_schemas": {"example_vm": {"block": {"attributes": {
+				"nic": {"nested_type": {"nesting_mode": "set", "attributes": {
+					"uuid": {"type": "string", "required": true},
+					"mac": {"type": "string", "computed": true}
+				}}, "optional": true}
+			}}}}}`,
+			after: `{"resource_schemas": {"example_vm": {"block": {"attributes": {
+				"nic": {"nested_type": {"nesting_mode": "set", "attributes": {
+					"uuid": {"type": "string", "required": true},
+					"port": {"type": "string", "computed": true}
+				}}, "optional": true}
+			}}}}}`,
+			expected: []string{
+				"resource example_vm: nic.mac: attribute removed",
+				"resource example_vm: nic.port: attribute added to set elements, which changes their identity",
+			},
+		},
+		"actions and list resources": {
+			before: `{
+				"action_schemas": {
+					"example_vm_resize": {"block": {"attributes": {"flavor": {"type": "string", "required": true}}}},
+					"example_vm_stop": {"block": {}}
+				},
+				"list_resource_schemas": {
+					"example_vm": {"block": {"attributes": {"tags": {"type": ["map", "string"], "optional": true}}}}
+				}
+			}`,
+			after: `{
+				"action_schemas": {
+					"example_vm_resize": {"block": {"attributes": {"flavor": {"type": "number", "required": true}}}}
+				},
+				"list_resource_schemas": {
+					"example_vm": {"block": {}}
+				}
+			}`,
+			expected: []string{
+				"action example_vm_resize: flavor: type changed from string to number",
+				"action example_vm_stop: removed",
+				"list resource example_vm: tags: attribute removed",
+			},
+		},
+		"function": {
+			before: `{"functions": {
+				"parse": {"parameters": [{"name": "input", "type": "string"}], "return_type": "string"},
+				"format": {"return_type": "string"}
+			}}`,
+			after: `{"functions": {
+				"parse": {"parameters": [{"name": "input", "type": "number"}], "return_type": ["list", "string"]}
+			}}`,
+			expected: []string{
+				"function format: removed",
+				"function parse: return type changed from string to list of string",
+				"function parse: type of parameter input changed from string to number",
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			requireChanges(t, compare(testSnapshot(t, testCase.before, nil), testSnapshot(t, testCase.after, nil)), testCase.expected)
+		})
+	}
+}
+
+func TestCompare_RequiresReplace(t *testing.T) {
+	schema := `{"resource_schemas": {"example_vm": {"block": {"attributes": {
+		"name": {"type": "string", "required": true},
+		"image": {"type": "string", "required": true}
+	}}}}}`
+
+	before := testSnapshot(t, schema, map[string][]string{"example_vm": {"image"}})
+	after := testSnapshot(t, schema, map[string][]string{"example_vm": {"image", "name"}})
+
+	requireChanges(t, compare(before, after), []string{"resource example_vm: name: changes now require replacement"})
+	requireChanges(t, compare(after, before), nil)
+}
+
+// testSnapshot returns a snapshot of a provider with the given schema.
+func testSnapshot(t *testing.T, schema string, requiresReplace map[string][]string) *schemajson.Snapshot {
+	t.Helper()
+
+	s := &schemajson.Snapshot{
+		FormatVersion:   schemajson.FormatVersion,
+		RequiresReplace: map[string]map[string][]string{testAddress: requiresReplace},
+	}
+
+	b := `{"format_version": "1.0", "provider_schemas": {"` + testAddress + `": ` + schema + `}}`
+	if err := json.Unmarshal([]byte(b), s); err != nil {
+		t.Fatalf("invalid test schema: %s", err)
+	}
+
+	return s
+}
+
+func requireChanges(t *testing.T, changes []breakingChange, expected []string) {
+	t.Helper()
+
+	got := make([]string, len(changes))
+	for i, c := range changes {
+		got[i] = c.String()
+	}
+
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected breaking changes:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(got, "\n"))
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Command schemacheck compares two snapshots of the provider schema, as
+// written by `terraform-provider-example -schema-json`, and reports the
+// changes that break existing configurations or state:
+//
+//	go run ./tools/schemacheck old.json new.json
+//
+// It exits with status 1 if any breaking change is found.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"terraform-provider-example/internal/schemajson"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: schemacheck old.json new.json")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	before, err := readSnapshot(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	after, err := readSnapshot(flag.Arg(1))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	changes := compare(before, after)
+	if len(changes) == 0 {
+		fmt.Println("no breaking changes")
+		return
+	}
+
+	for _, c := range changes {
+		fmt.Println(c)
+	}
+	os.Exit(1)
+}
+
+func readSnapshot(name string) (*schemajson.Snapshot, error) {
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var s schemajson.Snapshot
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, fmt.Errorf("unable to parse %s: %w", name, err)
+	}
+
+	return &s, nil
+}
@@ -1,27 +1,107 @@
 package main
 
 import (
-	"github.com/gin-gonic/gin"
-	"terraform-service/handler"
+	"crypto/tls"
+	"crypto/x509"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"terraform-service/server"
 )
 
 func main() {
-	r := gin.Default()
+	addr := flag.String("addr", ":29999", "监听地址")
+	tlsCert := flag.String("tls-cert", "", "服务端证书文件，设置后使用 HTTPS")
+	tlsKey := flag.String("tls-key", "", "服务端私钥文件")
+	clientCA := flag.String("client-ca", "", "签发客户端证书的 CA 文件，设置后要求客户端提供证书")
+	flag.Parse()
+
+	// 任务耗时与失败概率可以通过环境变量调整
+	opts := server.Options{
+		OperationDuration:    envDuration("TERRAFORM_SERVICE_JOB_SECONDS", 3*time.Second),
+		OperationFailureRate: envFloat("TERRAFORM_SERVICE_JOB_FAILURE_RATE", 0),
+		Logger:               os.Stdout,
+	}
+
+	// 设置了客户端凭证时启用 OAuth2 认证
+	if id := os.Getenv("TERRAFORM_SERVICE_CLIENT_ID"); id != "" {
+		opts.OAuthClients = map[string]string{id: os.Getenv("TERRAFORM_SERVICE_CLIENT_SECRET")}
+	}
+
+	// 设置了 access key 时接受 HMAC 签名的请求
+	if key := os.Getenv("TERRAFORM_SERVICE_ACCESS_KEY"); key != "" {
+		opts.HMACKeys = map[string]string{key: os.Getenv("TERRAFORM_SERVICE_SECRET_KEY")}
+	}
+
+	h := server.New(opts)
+
+	srv := &http.Server{
+		Addr:    *addr,
+		Handler: h,
+	}
+
+	if *tlsCert == "" {
+		if *clientCA != "" {
+			log.Fatal("--client-ca requires --tls-cert and --tls-key")
+		}
+
+		log.Fatal(srv.ListenAndServe())
+	}
+
+	tlsConfig, err := serverTLSConfig(*clientCA)
+	if err != nil {
+		log.Fatal(err)
+	}
+	srv.TLSConfig = tlsConfig
 
-	r.GET("/ping", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"message": "pong",
-		})
-	})
+	log.Fatal(srv.ListenAndServeTLS(*tlsCert, *tlsKey))
+}
+
+// serverTLSConfig 返回服务端的 TLS 配置，clientCA 不为空时要求并校验客户端证书
+func serverTLSConfig(clientCA string) (*tls.Config, error) {
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if clientCA == "" {
+		return config, nil
+	}
+
+	data, err := os.ReadFile(clientCA)
+	if err != nil {
+		return nil, err
+	}
 
-	// 测试 schema Attribute 的 computed 属性
-	computed := r.Group("/computed")
-	{
-		computed.GET("/detail", handler.ComputedDetail)
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("%s does not contain any PEM encoded certificate", clientCA)
 	}
 
-	err := r.Run(":29999")
+	config.ClientCAs = pool
+	config.ClientAuth = tls.RequireAndVerifyClientCert
+
+	return config, nil
+}
+
+func envDuration(key string, def time.Duration) time.Duration {
+	v, err := strconv.ParseFloat(os.Getenv(key), 64)
+	if err != nil || v < 0 {
+		return def
+	}
+
+	return time.Duration(v * float64(time.Second))
+}
+
+func envFloat(key string, def float64) float64 {
+	v, err := strconv.ParseFloat(os.Getenv(key), 64)
 	if err != nil {
-		panic(err)
+		return def
 	}
+
+	return v
 }
@@ -1,12 +1,16 @@
-package handler
+package server
 
 import (
 	"github.com/gin-gonic/gin"
 	"net/http"
 )
 
-func ComputedDetail(c *gin.Context) {
+func (s *service) ComputedDetail(c *gin.Context) {
 	id := c.Query("id")
+	if id == "" {
+		abortWithErrors(c, http.StatusBadRequest, FieldError{Field: "id", Message: "id is required"})
+		return
+	}
 
 	c.JSON(http.StatusOK, map[string]interface{}{
 		"id":                    id,
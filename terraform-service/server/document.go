@@ -0,0 +1,126 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Document 对应 provider 中的 example_document 资源
+// Content 可以是任意 JSON，后端原样保存，不做结构校验
+type Document struct {
+	Id      string            `json:"id"`
+	Content json.RawMessage   `json:"content"`
+	Tags    map[string]string `json:"tags,omitempty"`
+}
+
+func (s *service) DocumentList(c *gin.Context) {
+	c.JSON(http.StatusOK, filterByTags(c, s.storeFor(c).Documents().List(), func(item Document) map[string]string {
+		return item.Tags
+	}))
+}
+
+func (s *service) DocumentCreate(c *gin.Context) {
+	if s.replayDocument(c) {
+		return
+	}
+
+	var req Document
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithMessage(c, http.StatusBadRequest, "invalid request body: %s", err)
+		return
+	}
+
+	if !validateDocument(c, req) {
+		return
+	}
+
+	// 保存文档是同步操作
+	req.Id = newID("doc")
+	store := s.storeFor(c)
+	store.Documents().Put(req.Id, req)
+
+	if key := c.GetHeader("Idempotency-Key"); key != "" {
+		store.IdempotencyKeys().Put(key, req.Id)
+	}
+
+	c.JSON(http.StatusCreated, req)
+}
+
+// replayDocument 在请求携带的 Idempotency-Key 已经创建过文档时，返回该文档，
+// 客户端重试同一个创建请求时不会重复创建文档
+func (s *service) replayDocument(c *gin.Context) bool {
+	key := c.GetHeader("Idempotency-Key")
+	if key == "" {
+		return false
+	}
+
+	id, ok := s.storeFor(c).IdempotencyKeys().Get(key)
+	if !ok {
+		return false
+	}
+
+	item, ok := s.storeFor(c).Documents().Get(id)
+	if !ok {
+		return false
+	}
+
+	c.JSON(http.StatusCreated, item)
+
+	return true
+}
+
+func (s *service) DocumentDetail(c *gin.Context) {
+	item, ok := s.storeFor(c).Documents().Get(c.Param("id"))
+	if !ok {
+		abortWithMessage(c, http.StatusNotFound, "document %q not found", c.Param("id"))
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+func (s *service) DocumentUpdate(c *gin.Context) {
+	id := c.Param("id")
+	if _, ok := s.storeFor(c).Documents().Get(id); !ok {
+		abortWithMessage(c, http.StatusNotFound, "document %q not found", id)
+		return
+	}
+
+	var req Document
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithMessage(c, http.StatusBadRequest, "invalid request body: %s", err)
+		return
+	}
+
+	if !validateDocument(c, req) {
+		return
+	}
+
+	req.Id = id
+	s.storeFor(c).Documents().Put(id, req)
+
+	c.JSON(http.StatusOK, req)
+}
+
+func (s *service) DocumentDelete(c *gin.Context) {
+	if !s.storeFor(c).Documents().Delete(c.Param("id")) {
+		abortWithMessage(c, http.StatusNotFound, "document %q not found", c.Param("id"))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// validateDocument 只要求 content 不为空，null 也视为空
+func validateDocument(c *gin.Context, req Document) bool {
+	content := bytes.TrimSpace(req.Content)
+	if len(content) == 0 || bytes.Equal(content, []byte("null")) {
+		abortWithErrors(c, http.StatusUnprocessableEntity, FieldError{Field: "content", Message: "content is required"})
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,54 @@
+package server
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestDocument(t *testing.T) {
+	h := New(Options{})
+
+	doc := decode[Document](t, request(t, h, http.MethodPost, "/document", map[string]interface{}{"content": []int{1, 2}}, nil), http.StatusCreated)
+	if doc.Id == "" || string(doc.Content) != "[1,2]" {
+		t.Fatalf("unexpected document %+v", doc)
+	}
+
+	// content 原样保存，不会重新格式化
+	updated := decode[Document](t, request(t, h, http.MethodPut, "/document/"+doc.Id, map[string]interface{}{"content": map[string]int{"b": 1, "a": 2}}, nil), http.StatusOK)
+	if got := decode[Document](t, request(t, h, http.MethodGet, "/document/"+doc.Id, nil, nil), http.StatusOK); !reflect.DeepEqual(got, updated) {
+		t.Errorf("expected %+v, got %+v", updated, got)
+	}
+
+	for _, body := range []interface{}{map[string]interface{}{}, map[string]interface{}{"content": nil}} {
+		if fields := fieldErrors(t, request(t, h, http.MethodPost, "/document", body, nil), http.StatusUnprocessableEntity); !reflect.DeepEqual(fields, []string{"content"}) {
+			t.Errorf("expected an error of content for %v, got %v", body, fields)
+		}
+	}
+
+	if w := request(t, h, http.MethodDelete, "/document/"+doc.Id, nil, nil); w.Code != http.StatusNoContent {
+		t.Errorf("expected 204, got %d", w.Code)
+	}
+}
+
+// TestDocument_IdempotencyKey 文档同步创建，重试时直接返回之前创建的文档
+func TestDocument_IdempotencyKey(t *testing.T) {
+	h := New(Options{})
+	header := http.Header{"Idempotency-Key": {"create-doc"}}
+
+	first := decode[Document](t, request(t, h, http.MethodPost, "/document", map[string]interface{}{"content": "first"}, header), http.StatusCreated)
+	replayed := decode[Document](t, request(t, h, http.MethodPost, "/document", map[string]interface{}{"content": "second"}, header), http.StatusCreated)
+
+	if !reflect.DeepEqual(replayed, first) {
+		t.Errorf("expected document %+v to be replayed, got %+v", first, replayed)
+	}
+	if items := decode[[]Document](t, request(t, h, http.MethodGet, "/document", nil, nil), http.StatusOK); len(items) != 1 {
+		t.Errorf("expected a single document, got %+v", items)
+	}
+
+	// 之前的文档删除后，相同的 Idempotency-Key 会创建新文档
+	request(t, h, http.MethodDelete, "/document/"+first.Id, nil, nil)
+	if doc := decode[Document](t, request(t, h, http.MethodPost, "/document", map[string]interface{}{"content": "third"}, header), http.StatusCreated); doc.Id == first.Id {
+		t.Errorf("expected a new document, got %+v", doc)
+	}
+}
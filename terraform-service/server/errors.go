@@ -0,0 +1,30 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FieldError 描述一条被后端拒绝的字段错误
+// Field 使用与 provider 属性一致的路径写法，例如 "set_nested[0].fixed_ip"，
+// 与具体字段无关的错误 Field 为空
+type FieldError struct {
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// ErrorResponse 是所有非 2xx 响应的统一结构
+type ErrorResponse struct {
+	Errors []FieldError `json:"errors"`
+}
+
+// abortWithErrors 以统一的错误结构结束请求
+func abortWithErrors(c *gin.Context, code int, errs ...FieldError) {
+	c.AbortWithStatusJSON(code, ErrorResponse{Errors: errs})
+}
+
+// abortWithMessage 返回一条与字段无关的错误
+func abortWithMessage(c *gin.Context, code int, format string, args ...interface{}) {
+	abortWithErrors(c, code, FieldError{Message: fmt.Sprintf(format, args...)})
+}
@@ -0,0 +1,8 @@
+//go:build !unix && !windows
+
+package server
+
+// lockFile 在不支持文件锁的平台上什么也不做，只有单个进程使用文件时数据才是一致的
+func lockFile(path string) (unlock func(), err error) {
+	return func() {}, nil
+}
@@ -0,0 +1,232 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// fileSnapshot 是 fileStore 写入文件的内容
+type fileSnapshot struct {
+	Regexes         map[string]Regex          `json:"regexes"`
+	SetNesteds      map[string]SetNested      `json:"set_nesteds"`
+	ServerNetworks  map[string]ServerNetworks `json:"server_networks"`
+	Documents       map[string]Document       `json:"documents"`
+	Operations      map[string]Operation      `json:"operations"`
+	IdempotencyKeys map[string]string         `json:"idempotency_keys"`
+	Tokens          map[string]Token          `json:"tokens"`
+}
+
+// fileStore 在内存存储的基础上，每次修改后把全部数据写回文件。
+// 同一个文件可以被多个进程共享，例如 terraform 为每个 provider 别名各启动一个进程：
+// 修改前先锁住文件并重新读取，修改只作用在文件的最新内容上，不会覆盖其它进程写入的对象
+type fileStore struct {
+	memory *memoryStore
+	path   string
+	mu     sync.Mutex
+
+	// version 是内存中数据对应的文件状态，读取前据此判断文件是否被其它进程修改过
+	version fileVersion
+}
+
+// fileVersion 通过修改时间和大小识别文件的一次写入
+type fileVersion struct {
+	modTime time.Time
+	size    int64
+}
+
+func statVersion(path string) (fileVersion, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileVersion{}, err
+	}
+
+	return fileVersion{modTime: info.ModTime(), size: info.Size()}, nil
+}
+
+// NewFileStore 返回保存在 path 中的存储，文件不存在时从空数据开始。
+// 数据在每次修改后整体写回文件，进程重启后仍然保留，
+// 适合 provider 的 mock 模式在多次 terraform 命令之间共享数据。
+// 多个进程同时使用同一个文件时，通过 path + ".lock" 上的文件锁串行修改
+func NewFileStore(path string) (Store, error) {
+	s := &fileStore{
+		memory: NewMemoryStore().(*memoryStore),
+		path:   path,
+	}
+
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// load 从文件读取全部数据替换内存中的数据，文件不存在时保留内存中的数据
+func (s *fileStore) load() error {
+	version, err := statVersion(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return err
+	}
+
+	var snapshot fileSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return err
+	}
+
+	s.memory.regexes.load(snapshot.Regexes)
+	s.memory.setNesteds.load(snapshot.SetNesteds)
+	s.memory.serverNetworks.load(snapshot.ServerNetworks)
+	s.memory.documents.load(snapshot.Documents)
+	s.memory.operations.load(snapshot.Operations)
+	s.memory.idempotencyKeys.load(snapshot.IdempotencyKeys)
+	s.memory.tokens.load(snapshot.Tokens)
+	s.version = version
+
+	return nil
+}
+
+// refresh 在文件被其它进程修改过时重新读取。
+// 文件总是通过重命名整体替换，读取时不需要加文件锁
+func (s *fileStore) refresh() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if version, err := statVersion(s.path); err == nil && version != s.version {
+		_ = s.load()
+	}
+}
+
+// modify 在文件锁内读取文件的最新内容，执行 fn，fn 返回 true 时写回文件。
+// 无法加锁或读取文件时退化为只修改内存中的数据，不影响当前请求
+func (s *fileStore) modify(fn func() bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err == nil {
+		if unlock, err := lockFile(s.path + ".lock"); err == nil {
+			defer unlock()
+		}
+	}
+
+	_ = s.load()
+
+	if !fn() {
+		return false
+	}
+
+	s.save()
+
+	return true
+}
+
+func (s *fileStore) Regexes() Collection[Regex] {
+	return &fileCollection[Regex]{Collection: s.memory.regexes, store: s}
+}
+
+func (s *fileStore) SetNesteds() Collection[SetNested] {
+	return &fileCollection[SetNested]{Collection: s.memory.setNesteds, store: s}
+}
+
+func (s *fileStore) ServerNetworks() Collection[ServerNetworks] {
+	return &fileCollection[ServerNetworks]{Collection: s.memory.serverNetworks, store: s}
+}
+
+func (s *fileStore) Documents() Collection[Document] {
+	return &fileCollection[Document]{Collection: s.memory.documents, store: s}
+}
+
+func (s *fileStore) Operations() Collection[Operation] {
+	return &fileCollection[Operation]{Collection: s.memory.operations, store: s}
+}
+
+func (s *fileStore) IdempotencyKeys() Collection[string] {
+	return &fileCollection[string]{Collection: s.memory.idempotencyKeys, store: s}
+}
+
+func (s *fileStore) Tokens() Collection[Token] {
+	return &fileCollection[Token]{Collection: s.memory.tokens, store: s}
+}
+
+// save 先写临时文件再重命名，写到一半退出时不会损坏原文件，
+// 其它进程也不会读到写了一半的文件。调用方需要持有 s.mu 和文件锁。
+// 写文件失败时只保留内存中的数据，不影响当前请求
+func (s *fileStore) save() {
+	snapshot := fileSnapshot{
+		Regexes:         s.memory.regexes.snapshot(),
+		SetNesteds:      s.memory.setNesteds.snapshot(),
+		ServerNetworks:  s.memory.serverNetworks.snapshot(),
+		Documents:       s.memory.documents.snapshot(),
+		Operations:      s.memory.operations.snapshot(),
+		IdempotencyKeys: s.memory.idempotencyKeys.snapshot(),
+		Tokens:          s.memory.tokens.snapshot(),
+	}
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return
+	}
+
+	if err := os.Rename(tmp, s.path); err != nil {
+		return
+	}
+
+	if version, err := statVersion(s.path); err == nil {
+		s.version = version
+	}
+}
+
+// fileCollection 读取前检查文件是否被其它进程修改，修改时通过 fileStore.modify 写文件
+type fileCollection[T any] struct {
+	Collection[T]
+	store *fileStore
+}
+
+func (c *fileCollection[T]) Get(id string) (T, bool) {
+	c.store.refresh()
+	return c.Collection.Get(id)
+}
+
+func (c *fileCollection[T]) List() []T {
+	c.store.refresh()
+	return c.Collection.List()
+}
+
+func (c *fileCollection[T]) Keys() []string {
+	c.store.refresh()
+	return c.Collection.Keys()
+}
+
+func (c *fileCollection[T]) Put(id string, item T) {
+	c.store.modify(func() bool {
+		c.Collection.Put(id, item)
+		return true
+	})
+}
+
+func (c *fileCollection[T]) Delete(id string) bool {
+	return c.store.modify(func() bool {
+		return c.Collection.Delete(id)
+	})
+}
+
+func (c *fileCollection[T]) Update(id string, fn func(item *T)) bool {
+	return c.store.modify(func() bool {
+		return c.Collection.Update(id, fn)
+	})
+}
@@ -0,0 +1,117 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func newTestFileStore(t *testing.T, path string) Store {
+	t.Helper()
+
+	store, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("NewFileStore: %s", err)
+	}
+
+	return store
+}
+
+func TestFileStore_Persist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "store.json")
+
+	store := newTestFileStore(t, path)
+	store.Regexes().Put("vm-1", Regex{Id: "vm-1", Name: "test01"})
+	store.IdempotencyKeys().Put("key", "op-1")
+
+	// 文件不存在时先创建所在目录
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("expected the store to be written: %s", err)
+	}
+
+	reopened := newTestFileStore(t, path)
+	if item, ok := reopened.Regexes().Get("vm-1"); !ok || item.Name != "test01" {
+		t.Errorf("expected vm-1 after reopening, got %+v", item)
+	}
+	if id, ok := reopened.IdempotencyKeys().Get("key"); !ok || id != "op-1" {
+		t.Errorf("expected the idempotency key after reopening, got %q", id)
+	}
+
+	if !reopened.Regexes().Delete("vm-1") {
+		t.Fatalf("expected vm-1 to be deleted")
+	}
+	if _, ok := newTestFileStore(t, path).Regexes().Get("vm-1"); ok {
+		t.Errorf("expected the deletion to be persisted")
+	}
+}
+
+func TestFileStore_Corrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.json")
+	if err := os.WriteFile(path, []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewFileStore(path); err == nil {
+		t.Errorf("expected an error for a corrupt file")
+	}
+}
+
+// TestFileStore_Shared 模拟两个进程共享同一个文件：
+// 一方的修改对另一方可见，并且修改不会覆盖另一方写入的对象
+func TestFileStore_Shared(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.json")
+	a := newTestFileStore(t, path)
+	b := newTestFileStore(t, path)
+
+	a.Regexes().Put("vm-a", Regex{Id: "vm-a", Name: "a"})
+	if item, ok := b.Regexes().Get("vm-a"); !ok || item.Name != "a" {
+		t.Errorf("expected b to read vm-a, got %+v", item)
+	}
+
+	b.Regexes().Put("vm-b", Regex{Id: "vm-b", Name: "b"})
+	a.Documents().Put("doc-a", Document{Id: "doc-a", Content: []byte(`{}`)})
+
+	for name, store := range map[string]Store{"a": a, "b": b, "reopened": newTestFileStore(t, path)} {
+		if keys := store.Regexes().Keys(); len(keys) != 2 {
+			t.Errorf("expected vm-a and vm-b in %s, got %v", name, keys)
+		}
+		if _, ok := store.Documents().Get("doc-a"); !ok {
+			t.Errorf("expected doc-a in %s", name)
+		}
+	}
+
+	// 另一方删除的对象不能再更新
+	if !b.Regexes().Delete("vm-a") {
+		t.Fatalf("expected b to delete vm-a")
+	}
+	if a.Regexes().Update("vm-a", func(item *Regex) { item.Name = "updated" }) {
+		t.Errorf("expected no update of vm-a deleted by b")
+	}
+}
+
+// TestFileStore_ConcurrentUpdate 在两个共享文件的存储上并发修改同一个对象，
+// 文件锁保证每次修改都基于文件的最新内容，不会丢失修改
+func TestFileStore_ConcurrentUpdate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.json")
+	stores := []Store{newTestFileStore(t, path), newTestFileStore(t, path)}
+	stores[0].Operations().Put("op", Operation{Id: "op"})
+
+	const updates = 50
+
+	var wg sync.WaitGroup
+	for _, store := range stores {
+		for i := 0; i < updates; i++ {
+			wg.Add(1)
+			go func(store Store) {
+				defer wg.Done()
+				store.Operations().Update("op", func(op *Operation) { op.Progress++ })
+			}(store)
+		}
+	}
+	wg.Wait()
+
+	if op, _ := newTestFileStore(t, path).Operations().Get("op"); op.Progress != len(stores)*updates {
+		t.Errorf("expected %d updates, got %d", len(stores)*updates, op.Progress)
+	}
+}
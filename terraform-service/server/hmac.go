@@ -0,0 +1,159 @@
+package server
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HMAC 签名请求使用的认证方案与请求头，需要与 provider 的 hmacTransport 保持一致
+const (
+	hmacScheme      = "EXAMPLE-HMAC-SHA256"
+	hmacDateHeader  = "X-Example-Date"
+	hmacNonceHeader = "X-Example-Nonce"
+)
+
+// defaultHMACMaxSkew 是 Options.HMACMaxSkew 为 0 时允许的时钟偏差
+const defaultHMACMaxSkew = 5 * time.Minute
+
+// nonceCache 记录时间窗口内见过的 nonce，用来拒绝重放的请求。
+// 超出窗口的请求会因为时间戳被拒绝，所以只需要保留窗口内的 nonce
+type nonceCache struct {
+	mu   sync.Mutex
+	seen map[string]time.Time
+}
+
+// add 记录 nonce，nonce 已经存在时返回 false
+func (n *nonceCache) add(nonce string, now time.Time, window time.Duration) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.seen == nil {
+		n.seen = map[string]time.Time{}
+	}
+
+	for k, t := range n.seen {
+		if now.Sub(t) > window {
+			delete(n.seen, k)
+		}
+	}
+
+	if _, ok := n.seen[nonce]; ok {
+		return false
+	}
+	n.seen[nonce] = now
+
+	return true
+}
+
+// hmacAuth 校验 HMAC 签名：签名覆盖请求方法、路径、排序后的查询参数、ProjectHeader、时间戳、
+// nonce 与请求体的 SHA-256，时间戳偏差超过 HMACMaxSkew 或 nonce 重复的请求被拒绝
+func (s *service) hmacAuth(c *gin.Context) {
+	params, ok := strings.CutPrefix(c.GetHeader("Authorization"), hmacScheme+" ")
+	if !ok {
+		c.Header("WWW-Authenticate", hmacScheme)
+		abortWithMessage(c, http.StatusUnauthorized, "missing request signature")
+		return
+	}
+
+	var accessKey, signature string
+	for _, p := range strings.Split(params, ",") {
+		k, v, _ := strings.Cut(strings.TrimSpace(p), "=")
+		switch k {
+		case "Credential":
+			accessKey = v
+		case "Signature":
+			signature = v
+		}
+	}
+
+	secretKey, ok := s.opts.HMACKeys[accessKey]
+	if !ok {
+		abortWithMessage(c, http.StatusUnauthorized, "unknown access key %q", accessKey)
+		return
+	}
+
+	maxSkew := s.opts.HMACMaxSkew
+	if maxSkew <= 0 {
+		maxSkew = defaultHMACMaxSkew
+	}
+
+	date, err := time.Parse(time.RFC3339, c.GetHeader(hmacDateHeader))
+	if err != nil {
+		abortWithMessage(c, http.StatusUnauthorized, "missing or invalid %s header", hmacDateHeader)
+		return
+	}
+
+	now := time.Now()
+	if skew := now.Sub(date); skew > maxSkew || skew < -maxSkew {
+		abortWithMessage(c, http.StatusUnauthorized, "request timestamp is outside the allowed skew of %s", maxSkew)
+		return
+	}
+
+	// 嵌入到 provider 中时请求来自客户端，GET 请求的 Body 可能为 nil
+	var body []byte
+	if c.Request.Body != nil {
+		body, err = io.ReadAll(c.Request.Body)
+		if err != nil {
+			abortWithMessage(c, http.StatusBadRequest, "unable to read request body: %s", err)
+			return
+		}
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	expected := hmacSignature(secretKey, c.Request, body)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		abortWithMessage(c, http.StatusUnauthorized, "invalid signature")
+		return
+	}
+
+	// 签名正确之后才记录 nonce，伪造的请求不能占用合法客户端的 nonce
+	nonce := c.GetHeader(hmacNonceHeader)
+	if nonce == "" || !s.nonces.add(accessKey+"/"+nonce, now, 2*maxSkew) {
+		abortWithMessage(c, http.StatusUnauthorized, "replayed request")
+		return
+	}
+
+	c.Next()
+}
+
+// hmacCanonicalRequest 返回被签名的字符串，各部分以换行分隔
+func hmacCanonicalRequest(req *http.Request, body []byte) string {
+	bodyHash := sha256.Sum256(body)
+
+	return strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.Query().Encode(),
+		req.Header.Get(ProjectHeader),
+		req.Header.Get(hmacDateHeader),
+		req.Header.Get(hmacNonceHeader),
+		hex.EncodeToString(bodyHash[:]),
+	}, "\n")
+}
+
+// hmacSignature 返回十六进制编码的 HMAC-SHA256 签名
+func hmacSignature(secretKey string, req *http.Request, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secretKey))
+	mac.Write([]byte(hmacCanonicalRequest(req, body)))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// authenticate 按 Authorization 头的方案选择 HMAC 签名或 Bearer 令牌认证
+func (s *service) authenticate(c *gin.Context) {
+	if len(s.opts.HMACKeys) > 0 && (len(s.opts.OAuthClients) == 0 || strings.HasPrefix(c.GetHeader("Authorization"), hmacScheme+" ")) {
+		s.hmacAuth(c)
+		return
+	}
+
+	s.bearerAuth(c)
+}
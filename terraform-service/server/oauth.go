@@ -0,0 +1,106 @@
+package server
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultTokenLifetime 是 Options.TokenLifetime 为 0 时令牌的有效期
+const defaultTokenLifetime = time.Hour
+
+// Token 是 /oauth/token 签发的访问令牌
+type Token struct {
+	AccessToken string    `json:"access_token"`
+	ClientId    string    `json:"client_id"`
+	Scopes      []string  `json:"scopes,omitempty"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// tokenResponse 与 tokenError 的格式遵循 RFC 6749 第 5 节
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+	Scope       string `json:"scope,omitempty"`
+}
+
+type tokenError struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+// OAuthToken 实现 client credentials 授权，客户端凭证可以放在 Basic 认证头中，
+// 也可以放在表单的 client_id 与 client_secret 中
+func (s *service) OAuthToken(c *gin.Context) {
+	if c.PostForm("grant_type") != "client_credentials" {
+		c.JSON(http.StatusBadRequest, tokenError{Error: "unsupported_grant_type", ErrorDescription: "only client_credentials is supported"})
+		return
+	}
+
+	clientId, clientSecret, ok := c.Request.BasicAuth()
+	if !ok {
+		clientId, clientSecret = c.PostForm("client_id"), c.PostForm("client_secret")
+	}
+
+	secret, ok := s.opts.OAuthClients[clientId]
+	if !ok || subtle.ConstantTimeCompare([]byte(secret), []byte(clientSecret)) != 1 {
+		c.Header("WWW-Authenticate", `Basic realm="terraform-service"`)
+		c.JSON(http.StatusUnauthorized, tokenError{Error: "invalid_client", ErrorDescription: "unknown client or wrong secret"})
+		return
+	}
+
+	lifetime := s.opts.TokenLifetime
+	if lifetime <= 0 {
+		lifetime = defaultTokenLifetime
+	}
+
+	token := Token{
+		AccessToken: newToken(),
+		ClientId:    clientId,
+		Scopes:      strings.Fields(c.PostForm("scope")),
+		ExpiresAt:   time.Now().Add(lifetime),
+	}
+	s.store.Tokens().Put(token.AccessToken, token)
+
+	// 令牌响应不能被缓存
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, tokenResponse{
+		AccessToken: token.AccessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(lifetime.Seconds()),
+		Scope:       strings.Join(token.Scopes, " "),
+	})
+}
+
+// bearerAuth 要求请求携带 /oauth/token 签发且未过期的令牌
+func (s *service) bearerAuth(c *gin.Context) {
+	accessToken, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !ok || accessToken == "" {
+		c.Header("WWW-Authenticate", `Bearer realm="terraform-service"`)
+		abortWithMessage(c, http.StatusUnauthorized, "missing bearer token")
+		return
+	}
+
+	token, ok := s.store.Tokens().Get(accessToken)
+	if !ok || time.Now().After(token.ExpiresAt) {
+		c.Header("WWW-Authenticate", `Bearer realm="terraform-service", error="invalid_token"`)
+		abortWithMessage(c, http.StatusUnauthorized, "invalid or expired bearer token")
+		return
+	}
+
+	c.Next()
+}
+
+// newToken 生成随机的访问令牌
+func newToken() string {
+	b := make([]byte, 24)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestOAuthToken(t *testing.T) {
+	h := New(Options{OAuthClients: map[string]string{"client": "secret"}})
+
+	token := func(form url.Values) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/oauth/token", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		return w
+	}
+
+	if w := token(url.Values{"grant_type": {"password"}}); w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an unsupported grant type, got %d", w.Code)
+	}
+	if w := token(url.Values{"grant_type": {"client_credentials"}, "client_id": {"client"}, "client_secret": {"wrong"}}); w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 for a wrong secret, got %d", w.Code)
+	}
+
+	resp := decode[tokenResponse](t, token(url.Values{"grant_type": {"client_credentials"}, "client_id": {"client"}, "client_secret": {"secret"}, "scope": {"read write"}}), http.StatusOK)
+	if resp.TokenType != "Bearer" || resp.ExpiresIn != 3600 || resp.Scope != "read write" {
+		t.Errorf("unexpected token %+v", resp)
+	}
+
+	// 除 /ping 与 /oauth/token 以外的接口需要令牌
+	if w := request(t, h, http.MethodGet, "/ping", nil, nil); w.Code != http.StatusOK {
+		t.Errorf("expected /ping without a token, got %d", w.Code)
+	}
+	for header, code := range map[string]int{
+		"":                           http.StatusUnauthorized,
+		"Bearer invalid":             http.StatusUnauthorized,
+		"Bearer " + resp.AccessToken: http.StatusOK,
+	} {
+		if w := request(t, h, http.MethodGet, "/regex", nil, http.Header{"Authorization": {header}}); w.Code != code {
+			t.Errorf("expected %d with %q, got %d", code, header, w.Code)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package server
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 异步操作的状态
+const (
+	OperationPending   = "pending"
+	OperationRunning   = "running"
+	OperationSucceeded = "succeeded"
+	OperationFailed    = "failed"
+)
+
+// 每个异步操作拆分为固定的步数推进进度
+const operationSteps = 5
+
+// Operation 描述一个耗时的后端任务，例如创建虚机、挂载网卡
+type Operation struct {
+	Id         string       `json:"id"`
+	Status     string       `json:"status"`
+	Progress   int          `json:"progress"`
+	Message    string       `json:"message,omitempty"`
+	ResourceId string       `json:"resource_id,omitempty"`
+	Errors     []FieldError `json:"errors,omitempty"`
+}
+
+func (s *service) OperationDetail(c *gin.Context) {
+	op, ok := s.storeFor(c).Operations().Get(c.Param("id"))
+	if !ok {
+		abortWithMessage(c, http.StatusNotFound, "operation %q not found", c.Param("id"))
+		return
+	}
+
+	c.JSON(http.StatusOK, op)
+}
+
+// replayOperation 在请求携带的 Idempotency-Key 已经出现过时，返回之前的任务，
+// 客户端重试同一个创建请求时不会重复创建对象
+func (s *service) replayOperation(c *gin.Context) bool {
+	key := c.GetHeader("Idempotency-Key")
+	if key == "" {
+		return false
+	}
+
+	id, ok := s.storeFor(c).IdempotencyKeys().Get(key)
+	if !ok {
+		return false
+	}
+
+	op, ok := s.storeFor(c).Operations().Get(id)
+	if !ok {
+		return false
+	}
+
+	c.Header("Location", "/operations/"+op.Id)
+	c.JSON(http.StatusAccepted, op)
+
+	return true
+}
+
+// acceptOperation 启动一个模拟任务并返回 202，Location 指向任务地址
+// 任务成功后才会执行 apply，把对象写入请求所属项目的存储。
+// 任务耗时与失败概率来自 Options，也可以通过请求头 X-Job-Seconds 和 X-Job-Fail
+// 针对单个请求指定
+func (s *service) acceptOperation(c *gin.Context, resourceId string, apply func(store Store)) {
+	duration := s.opts.OperationDuration
+	if v, err := strconv.ParseFloat(c.GetHeader("X-Job-Seconds"), 64); err == nil && v >= 0 {
+		duration = time.Duration(v * float64(time.Second))
+	}
+
+	fail := rand.Float64() < s.opts.OperationFailureRate
+	if v, err := strconv.ParseBool(c.GetHeader("X-Job-Fail")); err == nil {
+		fail = v
+	}
+
+	op := Operation{
+		Id:         newID("op"),
+		Status:     OperationPending,
+		ResourceId: resourceId,
+	}
+	store := s.storeFor(c)
+	store.Operations().Put(op.Id, op)
+
+	if key := c.GetHeader("Idempotency-Key"); key != "" {
+		store.IdempotencyKeys().Put(key, op.Id)
+	}
+
+	go runOperation(store, op.Id, duration, fail, apply)
+
+	c.Header("Location", "/operations/"+op.Id)
+	c.JSON(http.StatusAccepted, op)
+}
+
+// runOperation 在后台推进任务，请求结束后 gin.Context 会被复用，所以只使用 store
+func runOperation(store Store, id string, duration time.Duration, fail bool, apply func(store Store)) {
+	for step := 1; step <= operationSteps; step++ {
+		time.Sleep(duration / operationSteps)
+
+		if step < operationSteps {
+			store.Operations().Update(id, func(op *Operation) {
+				op.Status = OperationRunning
+				op.Progress = step * 100 / operationSteps
+				op.Message = "step " + strconv.Itoa(step) + " of " + strconv.Itoa(operationSteps)
+			})
+			continue
+		}
+
+		if fail {
+			store.Operations().Update(id, func(op *Operation) {
+				op.Status = OperationFailed
+				op.Message = "operation failed"
+				op.Errors = []FieldError{{Message: "simulated failure of operation " + id}}
+			})
+			return
+		}
+
+		apply(store)
+
+		store.Operations().Update(id, func(op *Operation) {
+			op.Status = OperationSucceeded
+			op.Progress = 100
+			op.Message = "done"
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestOperation(t *testing.T) {
+	h := New(Options{OperationDuration: time.Hour})
+
+	// X-Job-Seconds 覆盖 Options 中的任务耗时
+	header := http.Header{"X-Job-Seconds": {"0.05"}}
+	w := request(t, h, http.MethodPost, "/regex", Regex{Name: "test01"}, header)
+
+	op := decode[Operation](t, w, http.StatusAccepted)
+	if op.Status != OperationPending || op.ResourceId == "" || w.Header().Get("Location") != "/operations/"+op.Id {
+		t.Fatalf("unexpected operation %+v at %q", op, w.Header().Get("Location"))
+	}
+
+	// 任务成功之前对象不存在
+	if w := request(t, h, http.MethodGet, "/regex/"+op.ResourceId, nil, nil); w.Code != http.StatusNotFound {
+		t.Errorf("expected no VM before the operation succeeds, got %d", w.Code)
+	}
+
+	if op = waitOperation(t, h, nil, op.Id); op.Status != OperationSucceeded || op.Progress != 100 {
+		t.Fatalf("expected the operation to succeed, got %+v", op)
+	}
+	if w := request(t, h, http.MethodGet, "/regex/"+op.ResourceId, nil, nil); w.Code != http.StatusOK {
+		t.Errorf("expected the VM after the operation succeeds, got %d", w.Code)
+	}
+
+	if w := request(t, h, http.MethodGet, "/operations/op-missing", nil, nil); w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for a missing operation, got %d", w.Code)
+	}
+}
+
+func TestOperation_Fail(t *testing.T) {
+	h := New(Options{})
+
+	w := request(t, h, http.MethodPost, "/regex", Regex{Name: "test01"}, http.Header{"X-Job-Fail": {"true"}})
+	op := waitOperation(t, h, nil, decode[Operation](t, w, http.StatusAccepted).Id)
+
+	if op.Status != OperationFailed || len(op.Errors) != 1 {
+		t.Fatalf("expected the operation to fail, got %+v", op)
+	}
+	if w := request(t, h, http.MethodGet, "/regex/"+op.ResourceId, nil, nil); w.Code != http.StatusNotFound {
+		t.Errorf("expected no VM after the operation fails, got %d", w.Code)
+	}
+}
+
+// TestOperation_IdempotencyKey 重试携带相同 Idempotency-Key 的创建请求时返回之前的任务，
+// 不会重复创建对象
+func TestOperation_IdempotencyKey(t *testing.T) {
+	h := New(Options{})
+	header := http.Header{"Idempotency-Key": {"create-test01"}, ProjectHeader: {"p1"}}
+
+	first := decode[Operation](t, request(t, h, http.MethodPost, "/regex", Regex{Name: "test01"}, header), http.StatusAccepted)
+	waitOperation(t, h, header, first.Id)
+
+	// 重试时虚机已经存在，如果没有返回之前的任务，名称重复会返回 409
+	w := request(t, h, http.MethodPost, "/regex", Regex{Name: "test01"}, header)
+	if replayed := decode[Operation](t, w, http.StatusAccepted); replayed.Id != first.Id || replayed.Status != OperationSucceeded || w.Header().Get("Location") != "/operations/"+first.Id {
+		t.Errorf("expected operation %s to be replayed, got %+v", first.Id, replayed)
+	}
+
+	if items := decode[[]Regex](t, request(t, h, http.MethodGet, "/regex", nil, header), http.StatusOK); len(items) != 1 {
+		t.Errorf("expected a single VM, got %+v", items)
+	}
+
+	// Idempotency-Key 同样按项目隔离
+	other := http.Header{"Idempotency-Key": {"create-test01"}, ProjectHeader: {"p2"}}
+	if op := decode[Operation](t, request(t, h, http.MethodPost, "/regex", Regex{Name: "test01"}, other), http.StatusAccepted); op.Id == first.Id {
+		t.Errorf("expected a new operation in p2, got %s", op.Id)
+	}
+}
@@ -0,0 +1,124 @@
+package server
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProjectHeader 指定请求所属的项目，不同项目的对象互相不可见
+const ProjectHeader = "X-Example-Project"
+
+// projectKey 是 gin.Context 中保存项目名称的键
+const projectKey = "project"
+
+var projectPattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]{0,62}$`)
+
+// project 校验 ProjectHeader 并记录到 gin.Context 中，之后的接口通过 storeFor 访问所属项目的数据
+func (s *service) project(c *gin.Context) {
+	project := c.GetHeader(ProjectHeader)
+	if project != "" && !projectPattern.MatchString(project) {
+		abortWithMessage(c, http.StatusBadRequest, "invalid %s header %q: project names contain only letters, digits, '.', '_' and '-', and are at most 63 characters", ProjectHeader, project)
+		return
+	}
+
+	c.Set(projectKey, project)
+	c.Next()
+}
+
+// storeFor 返回请求所属项目的存储
+func (s *service) storeFor(c *gin.Context) Store {
+	return projectStore{Store: s.store, project: c.GetString(projectKey)}
+}
+
+// projectStore 在底层存储中以 "项目/ID" 作为键保存对象。
+// 未指定项目的请求直接使用原始 ID，与引入项目之前保存的数据兼容。
+// 访问令牌不属于任何项目
+type projectStore struct {
+	Store
+	project string
+}
+
+func (s projectStore) Regexes() Collection[Regex] {
+	return projectCollection[Regex]{Collection: s.Store.Regexes(), project: s.project}
+}
+
+func (s projectStore) SetNesteds() Collection[SetNested] {
+	return projectCollection[SetNested]{Collection: s.Store.SetNesteds(), project: s.project}
+}
+
+func (s projectStore) ServerNetworks() Collection[ServerNetworks] {
+	return projectCollection[ServerNetworks]{Collection: s.Store.ServerNetworks(), project: s.project}
+}
+
+func (s projectStore) Documents() Collection[Document] {
+	return projectCollection[Document]{Collection: s.Store.Documents(), project: s.project}
+}
+
+func (s projectStore) Operations() Collection[Operation] {
+	return projectCollection[Operation]{Collection: s.Store.Operations(), project: s.project}
+}
+
+func (s projectStore) IdempotencyKeys() Collection[string] {
+	return projectCollection[string]{Collection: s.Store.IdempotencyKeys(), project: s.project}
+}
+
+// projectCollection 只能访问所属项目的对象
+type projectCollection[T any] struct {
+	Collection[T]
+	project string
+}
+
+func (c projectCollection[T]) key(id string) string {
+	if c.project == "" {
+		return id
+	}
+
+	return c.project + "/" + id
+}
+
+func (c projectCollection[T]) Get(id string) (T, bool) {
+	return c.Collection.Get(c.key(id))
+}
+
+func (c projectCollection[T]) Put(id string, item T) {
+	c.Collection.Put(c.key(id), item)
+}
+
+func (c projectCollection[T]) Delete(id string) bool {
+	return c.Collection.Delete(c.key(id))
+}
+
+func (c projectCollection[T]) Update(id string, fn func(item *T)) bool {
+	return c.Collection.Update(c.key(id), fn)
+}
+
+func (c projectCollection[T]) Keys() []string {
+	var ids []string
+	for _, key := range c.Collection.Keys() {
+		project, id, ok := strings.Cut(key, "/")
+		if !ok {
+			project, id = "", key
+		}
+
+		if project == c.project {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+func (c projectCollection[T]) List() []T {
+	items := []T{}
+	for _, id := range c.Keys() {
+		// 对象可能在 Keys 之后被删除
+		if item, ok := c.Get(id); ok {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
@@ -0,0 +1,93 @@
+package server
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestProjectCollection(t *testing.T) {
+	store := NewMemoryStore()
+	p1 := projectStore{Store: store, project: "p1"}.Regexes()
+	p2 := projectStore{Store: store, project: "p2"}.Regexes()
+	none := projectStore{Store: store}.Regexes()
+
+	// 不同项目可以使用相同的 ID
+	p1.Put("vm-1", Regex{Id: "vm-1", Name: "p1"})
+	p2.Put("vm-1", Regex{Id: "vm-1", Name: "p2"})
+	none.Put("vm-2", Regex{Id: "vm-2", Name: "none"})
+
+	if item, ok := p1.Get("vm-1"); !ok || item.Name != "p1" {
+		t.Errorf("expected vm-1 of p1, got %+v", item)
+	}
+	if item, ok := p2.Get("vm-1"); !ok || item.Name != "p2" {
+		t.Errorf("expected vm-1 of p2, got %+v", item)
+	}
+	if _, ok := p1.Get("vm-2"); ok {
+		t.Errorf("expected vm-2 to be invisible in p1")
+	}
+
+	// 未指定项目时直接使用原始 ID
+	if keys := store.Regexes().Keys(); !reflect.DeepEqual(keys, []string{"p1/vm-1", "p2/vm-1", "vm-2"}) {
+		t.Errorf("unexpected keys %v of the underlying store", keys)
+	}
+	if keys := none.Keys(); !reflect.DeepEqual(keys, []string{"vm-2"}) {
+		t.Errorf("expected only vm-2 without a project, got %v", keys)
+	}
+	if items := p2.List(); len(items) != 1 || items[0].Name != "p2" {
+		t.Errorf("expected only vm-1 of p2, got %+v", items)
+	}
+	p3 := projectStore{Store: store, project: "p3"}.Regexes()
+	if items := p3.List(); items == nil || len(items) != 0 {
+		t.Errorf("expected an empty list in p3, got %#v", items)
+	}
+
+	if !p1.Update("vm-1", func(item *Regex) { item.Name = "updated" }) {
+		t.Errorf("expected vm-1 of p1 to be updated")
+	}
+	if item, _ := p2.Get("vm-1"); item.Name != "p2" {
+		t.Errorf("expected vm-1 of p2 to be unchanged, got %+v", item)
+	}
+
+	if !p1.Delete("vm-1") || p1.Delete("vm-1") {
+		t.Errorf("expected vm-1 of p1 to be deleted once")
+	}
+	if _, ok := p2.Get("vm-1"); !ok {
+		t.Errorf("expected vm-1 of p2 to be kept")
+	}
+}
+
+func TestProject(t *testing.T) {
+	h := New(Options{})
+	p1 := http.Header{ProjectHeader: {"p1"}}
+	p2 := http.Header{ProjectHeader: {"p2"}}
+
+	vm := createRegex(t, h, p1, Regex{Name: "test01"})
+
+	// 其它项目看不到该虚机，也可以使用相同的名称
+	if w := request(t, h, http.MethodGet, "/regex/"+vm.Id, nil, p2); w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 in p2, got %d", w.Code)
+	}
+	if items := decode[[]Regex](t, request(t, h, http.MethodGet, "/regex", nil, p2), http.StatusOK); len(items) != 0 {
+		t.Errorf("expected no VM in p2, got %+v", items)
+	}
+	createRegex(t, h, p2, Regex{Name: "test01"})
+
+	if items := decode[[]Regex](t, request(t, h, http.MethodGet, "/regex", nil, p1), http.StatusOK); len(items) != 1 || items[0].Id != vm.Id {
+		t.Errorf("expected only %s in p1, got %+v", vm.Id, items)
+	}
+	if items := decode[[]Regex](t, request(t, h, http.MethodGet, "/regex", nil, nil), http.StatusOK); len(items) != 0 {
+		t.Errorf("expected no VM without a project, got %+v", items)
+	}
+
+	if w := request(t, h, http.MethodDelete, "/regex/"+vm.Id, nil, p2); w.Code != http.StatusNotFound {
+		t.Errorf("expected no deletion from p2, got %d", w.Code)
+	}
+
+	for _, project := range []string{"-p1", "p1/vm", strings.Repeat("a", 64)} {
+		if w := request(t, h, http.MethodGet, "/regex", nil, http.Header{ProjectHeader: {project}}); w.Code != http.StatusBadRequest {
+			t.Errorf("expected 400 for project %q, got %d", project, w.Code)
+		}
+	}
+}
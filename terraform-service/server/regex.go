@@ -0,0 +1,156 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Regex 对应 provider 中的 example_regex 资源（虚机）
+type Regex struct {
+	Id    string  `json:"id"`
+	Name  string  `json:"name"`
+	Alias *string `json:"alias,omitempty"`
+	// UserDataJson 保存时会被重新格式化：去掉空白并按键名排序
+	UserDataJson json.RawMessage   `json:"user_data_json,omitempty"`
+	Tags         map[string]string `json:"tags,omitempty"`
+	// PowerState 与 Flavor 只能通过 /regex/:id/actions 下的操作修改
+	PowerState string `json:"power_state,omitempty"`
+	Flavor     string `json:"flavor,omitempty"`
+}
+
+var regexNamePattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9-]*[a-zA-Z0-9]$`)
+
+func (s *service) RegexList(c *gin.Context) {
+	c.JSON(http.StatusOK, filterByTags(c, s.storeFor(c).Regexes().List(), func(item Regex) map[string]string {
+		return item.Tags
+	}))
+}
+
+func (s *service) RegexCreate(c *gin.Context) {
+	if s.replayOperation(c) {
+		return
+	}
+
+	var req Regex
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithMessage(c, http.StatusBadRequest, "invalid request body: %s", err)
+		return
+	}
+
+	req.Id = newID("vm")
+	if !s.validateRegex(c, req) {
+		return
+	}
+	req.UserDataJson = normalizeJSON(req.UserDataJson)
+	req.PowerState = PowerStateRunning
+	req.Flavor = defaultFlavor
+
+	// 创建虚机是异步操作
+	s.acceptOperation(c, req.Id, func(store Store) {
+		store.Regexes().Put(req.Id, req)
+	})
+}
+
+func (s *service) RegexDetail(c *gin.Context) {
+	item, ok := s.storeFor(c).Regexes().Get(c.Param("id"))
+	if !ok {
+		abortWithMessage(c, http.StatusNotFound, "regex %q not found", c.Param("id"))
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+func (s *service) RegexUpdate(c *gin.Context) {
+	id := c.Param("id")
+	current, ok := s.storeFor(c).Regexes().Get(id)
+	if !ok {
+		abortWithMessage(c, http.StatusNotFound, "regex %q not found", id)
+		return
+	}
+
+	var req Regex
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithMessage(c, http.StatusBadRequest, "invalid request body: %s", err)
+		return
+	}
+
+	req.Id = id
+	if !s.validateRegex(c, req) {
+		return
+	}
+	req.UserDataJson = normalizeJSON(req.UserDataJson)
+	req.PowerState = current.PowerState
+	req.Flavor = current.Flavor
+
+	s.storeFor(c).Regexes().Put(id, req)
+
+	c.JSON(http.StatusOK, req)
+}
+
+func (s *service) RegexDelete(c *gin.Context) {
+	if !s.storeFor(c).Regexes().Delete(c.Param("id")) {
+		abortWithMessage(c, http.StatusNotFound, "regex %q not found", c.Param("id"))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// normalizeJSON 重新编码 JSON，对象的键按字母排序
+func normalizeJSON(data json.RawMessage) json.RawMessage {
+	if len(data) == 0 {
+		return data
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return data
+	}
+
+	normalized, err := json.Marshal(v)
+	if err != nil {
+		return data
+	}
+
+	return normalized
+}
+
+// validateRegex 校验虚机参数，校验失败时已写入错误响应
+func (s *service) validateRegex(c *gin.Context, req Regex) bool {
+	var errs []FieldError
+
+	switch {
+	case req.Name == "":
+		errs = append(errs, FieldError{Field: "name", Message: "name is required"})
+	case utf8.RuneCountInString(req.Name) > 25 || !regexNamePattern.MatchString(req.Name):
+		errs = append(errs, FieldError{Field: "name", Message: "name must start with a letter, contain only letters, digits and '-', not end with '-' and be at most 25 characters"})
+	}
+
+	if req.Alias != nil {
+		alias := *req.Alias
+		if utf8.RuneCountInString(alias) > 32 || strings.HasPrefix(alias, ".") || strings.HasSuffix(alias, ".") {
+			errs = append(errs, FieldError{Field: "alias", Message: "alias must be at most 32 characters and must not start or end with '.'"})
+		}
+	}
+
+	if len(errs) > 0 {
+		abortWithErrors(c, http.StatusUnprocessableEntity, errs...)
+		return false
+	}
+
+	// 虚机名称全局唯一
+	for _, item := range s.storeFor(c).Regexes().List() {
+		if item.Id != req.Id && item.Name == req.Name {
+			abortWithErrors(c, http.StatusConflict, FieldError{Field: "name", Message: "name " + req.Name + " is already in use by " + item.Id})
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 虚机的电源状态
+const (
+	PowerStateRunning = "running"
+	PowerStateStopped = "stopped"
+)
+
+// 虚机规格，创建时为 defaultFlavor，之后只能通过 resize 修改
+var flavors = map[string]bool{
+	"small":  true,
+	"medium": true,
+	"large":  true,
+}
+
+const defaultFlavor = "small"
+
+// RegexActionRequest 是虚机操作的请求体，只有 resize 需要
+type RegexActionRequest struct {
+	Flavor string `json:"flavor,omitempty"`
+}
+
+// RegexAction 对虚机执行 reboot、stop、start 或 resize。这些操作不修改虚机的配置，
+// 与创建一样是异步任务，任务成功后才会修改虚机的状态
+func (s *service) RegexAction(c *gin.Context) {
+	if s.replayOperation(c) {
+		return
+	}
+
+	id := c.Param("id")
+	item, ok := s.storeFor(c).Regexes().Get(id)
+	if !ok {
+		abortWithMessage(c, http.StatusNotFound, "regex %q not found", id)
+		return
+	}
+
+	var req RegexActionRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			abortWithMessage(c, http.StatusBadRequest, "invalid request body: %s", err)
+			return
+		}
+	}
+
+	var apply func(item *Regex)
+
+	switch action := c.Param("action"); action {
+	case "reboot":
+		if item.PowerState == PowerStateStopped {
+			abortWithMessage(c, http.StatusConflict, "regex %q is stopped, start it instead of rebooting it", id)
+			return
+		}
+		apply = func(item *Regex) {}
+	case "stop":
+		if item.PowerState == PowerStateStopped {
+			abortWithMessage(c, http.StatusConflict, "regex %q is already stopped", id)
+			return
+		}
+		apply = func(item *Regex) { item.PowerState = PowerStateStopped }
+	case "start":
+		if item.PowerState != PowerStateStopped {
+			abortWithMessage(c, http.StatusConflict, "regex %q is already running", id)
+			return
+		}
+		apply = func(item *Regex) { item.PowerState = PowerStateRunning }
+	case "resize":
+		if !flavors[req.Flavor] {
+			abortWithErrors(c, http.StatusUnprocessableEntity, FieldError{Field: "flavor", Message: "flavor must be one of small, medium and large"})
+			return
+		}
+		// 调整规格需要重启虚机，完成后虚机处于运行状态
+		apply = func(item *Regex) {
+			item.Flavor = req.Flavor
+			item.PowerState = PowerStateRunning
+		}
+	default:
+		abortWithMessage(c, http.StatusNotFound, "unknown action %q, expected reboot, stop, start or resize", action)
+		return
+	}
+
+	s.acceptOperation(c, id, func(store Store) {
+		store.Regexes().Update(id, apply)
+	})
+}
@@ -0,0 +1,134 @@
+package server
+
+import (
+	"net/http"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// createRegex 异步创建虚机并等待任务成功
+func createRegex(t *testing.T, h http.Handler, header http.Header, req Regex) Regex {
+	t.Helper()
+
+	op := decode[Operation](t, request(t, h, http.MethodPost, "/regex", req, header), http.StatusAccepted)
+	if op = waitOperation(t, h, header, op.Id); op.Status != OperationSucceeded {
+		t.Fatalf("expected the creation of %s to succeed, got %+v", req.Name, op)
+	}
+
+	return decode[Regex](t, request(t, h, http.MethodGet, "/regex/"+op.ResourceId, nil, header), http.StatusOK)
+}
+
+func TestRegex(t *testing.T) {
+	h := New(Options{})
+
+	vm := createRegex(t, h, nil, Regex{Name: "test01", UserDataJson: []byte(`{"b": 1, "a": 2}`)})
+	if vm.PowerState != PowerStateRunning || vm.Flavor != defaultFlavor || string(vm.UserDataJson) != `{"a":2,"b":1}` {
+		t.Errorf("unexpected VM %+v", vm)
+	}
+
+	alias := "web"
+	updated := decode[Regex](t, request(t, h, http.MethodPut, "/regex/"+vm.Id, Regex{Name: "test02", Alias: &alias}, nil), http.StatusOK)
+	if updated.Id != vm.Id || updated.Name != "test02" || updated.Alias == nil || *updated.Alias != alias || updated.PowerState != PowerStateRunning {
+		t.Errorf("unexpected updated VM %+v", updated)
+	}
+
+	if w := request(t, h, http.MethodDelete, "/regex/"+vm.Id, nil, nil); w.Code != http.StatusNoContent {
+		t.Errorf("expected 204, got %d", w.Code)
+	}
+	for _, method := range []string{http.MethodGet, http.MethodDelete} {
+		if w := request(t, h, method, "/regex/"+vm.Id, nil, nil); w.Code != http.StatusNotFound {
+			t.Errorf("expected %s of a deleted VM to return 404, got %d", method, w.Code)
+		}
+	}
+}
+
+func TestRegex_Validation(t *testing.T) {
+	h := New(Options{})
+	createRegex(t, h, nil, Regex{Name: "test01"})
+
+	alias := ".web"
+	for _, test := range []struct {
+		req    Regex
+		code   int
+		fields []string
+	}{
+		{Regex{}, http.StatusUnprocessableEntity, []string{"name"}},
+		{Regex{Name: "1vm"}, http.StatusUnprocessableEntity, []string{"name"}},
+		{Regex{Name: "vm-", Alias: &alias}, http.StatusUnprocessableEntity, []string{"name", "alias"}},
+		// 虚机名称不能重复
+		{Regex{Name: "test01"}, http.StatusConflict, []string{"name"}},
+	} {
+		if fields := fieldErrors(t, request(t, h, http.MethodPost, "/regex", test.req, nil), test.code); !reflect.DeepEqual(fields, test.fields) {
+			t.Errorf("expected errors of %v for %+v, got %v", test.fields, test.req, fields)
+		}
+	}
+}
+
+func TestRegex_TagFilter(t *testing.T) {
+	h := New(Options{})
+	createRegex(t, h, nil, Regex{Name: "web", Tags: map[string]string{"env": "prod", "role": "web"}})
+	createRegex(t, h, nil, Regex{Name: "db", Tags: map[string]string{"env": "prod"}})
+	createRegex(t, h, nil, Regex{Name: "dev", Tags: map[string]string{"env": "dev"}})
+
+	for query, names := range map[string][]string{
+		"":                          {"db", "dev", "web"},
+		"?tag=env=prod":             {"db", "web"},
+		"?tag=role":                 {"web"},
+		"?tag=env=prod&tag=role=db": nil,
+	} {
+		var got []string
+		for _, item := range decode[[]Regex](t, request(t, h, http.MethodGet, "/regex"+query, nil, nil), http.StatusOK) {
+			got = append(got, item.Name)
+		}
+		sort.Strings(got)
+
+		if !reflect.DeepEqual(got, names) {
+			t.Errorf("expected %v for %q, got %v", names, query, got)
+		}
+	}
+}
+
+func TestRegexAction(t *testing.T) {
+	h := New(Options{})
+	vm := createRegex(t, h, nil, Regex{Name: "test01"})
+
+	action := func(name string) int {
+		return request(t, h, http.MethodPost, "/regex/"+vm.Id+"/actions/"+name, nil, nil).Code
+	}
+	run := func(name string, body interface{}) {
+		t.Helper()
+
+		w := request(t, h, http.MethodPost, "/regex/"+vm.Id+"/actions/"+name, body, nil)
+		if op := waitOperation(t, h, nil, decode[Operation](t, w, http.StatusAccepted).Id); op.Status != OperationSucceeded {
+			t.Fatalf("expected %s to succeed, got %+v", name, op)
+		}
+	}
+
+	run("stop", nil)
+	for _, name := range []string{"stop", "reboot"} {
+		if code := action(name); code != http.StatusConflict {
+			t.Errorf("expected %s of a stopped VM to return 409, got %d", name, code)
+		}
+	}
+
+	run("start", nil)
+	if code := action("start"); code != http.StatusConflict {
+		t.Errorf("expected start of a running VM to return 409, got %d", code)
+	}
+
+	if fields := fieldErrors(t, request(t, h, http.MethodPost, "/regex/"+vm.Id+"/actions/resize", RegexActionRequest{Flavor: "huge"}, nil), http.StatusUnprocessableEntity); !reflect.DeepEqual(fields, []string{"flavor"}) {
+		t.Errorf("expected an error of flavor, got %v", fields)
+	}
+
+	run("stop", nil)
+	run("resize", RegexActionRequest{Flavor: "large"})
+
+	if got := decode[Regex](t, request(t, h, http.MethodGet, "/regex/"+vm.Id, nil, nil), http.StatusOK); got.Flavor != "large" || got.PowerState != PowerStateRunning {
+		t.Errorf("expected a running large VM, got %+v", got)
+	}
+
+	if code := action("unknown"); code != http.StatusNotFound {
+		t.Errorf("expected an unknown action to return 404, got %d", code)
+	}
+}
@@ -0,0 +1,145 @@
+// Package server 实现配合 terraform-provider-example 使用的后端服务，
+// 既可以由 main 启动为独立进程，也可以在测试中通过 httptest.NewServer 嵌入
+package server
+
+import (
+	_ "embed"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OpenAPI 是服务接口的 OpenAPI 3 描述，provider 据此生成资源代码
+//
+//go:embed openapi.json
+var OpenAPI []byte
+
+// Options 是服务的配置，零值即可使用
+type Options struct {
+	// Store 为空时使用 NewMemoryStore
+	Store Store
+
+	// OperationDuration 是每个异步任务的耗时，为 0 时任务立即完成
+	OperationDuration time.Duration
+
+	// OperationFailureRate 是异步任务随机失败的概率，取值 0 到 1
+	OperationFailureRate float64
+
+	// Logger 不为空时把访问日志写入其中
+	Logger io.Writer
+
+	// OAuthClients 是 client_id 到 client_secret 的映射。不为空时除 /ping 与
+	// /oauth/token 以外的接口都需要 /oauth/token 签发的 Bearer 令牌
+	OAuthClients map[string]string
+
+	// TokenLifetime 是访问令牌的有效期，为 0 时为一小时
+	TokenLifetime time.Duration
+
+	// HMACKeys 是 access key 到 secret key 的映射。不为空时接口也接受 HMAC 签名的请求，
+	// 与 OAuthClients 同时设置时两种认证方式都可以使用
+	HMACKeys map[string]string
+
+	// HMACMaxSkew 是签名时间戳与服务端时钟允许的最大偏差，为 0 时为五分钟
+	HMACMaxSkew time.Duration
+}
+
+// service 持有各个接口共享的状态
+type service struct {
+	store Store
+	opts  Options
+
+	// nonces 记录最近的 HMAC 签名请求，只保存在内存中
+	nonces nonceCache
+}
+
+// New 返回注册了全部路由的 http.Handler
+func New(opts Options) http.Handler {
+	if opts.Store == nil {
+		opts.Store = NewMemoryStore()
+	}
+
+	s := &service{
+		store: opts.Store,
+		opts:  opts,
+	}
+
+	r := gin.New()
+	if opts.Logger != nil {
+		r.Use(gin.LoggerWithWriter(opts.Logger))
+	}
+	r.Use(gin.Recovery())
+
+	r.GET("/ping", func(c *gin.Context) {
+		c.JSON(200, gin.H{
+			"message": "pong",
+		})
+	})
+
+	r.POST("/oauth/token", s.OAuthToken)
+
+	r.GET("/openapi.json", func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json", OpenAPI)
+	})
+
+	// 之后注册的接口都需要认证
+	if len(opts.OAuthClients) > 0 || len(opts.HMACKeys) > 0 {
+		r.Use(s.authenticate)
+	}
+
+	// 之后注册的接口按 X-Example-Project 请求头隔离数据
+	r.Use(s.project)
+
+	// 测试 schema Attribute 的 computed 属性
+	computed := r.Group("/computed")
+	{
+		computed.GET("/detail", s.ComputedDetail)
+	}
+
+	// 虚机，对应 example_regex
+	regex := r.Group("/regex")
+	{
+		regex.GET("", s.RegexList)
+		regex.POST("", s.RegexCreate)
+		regex.GET("/:id", s.RegexDetail)
+		regex.PUT("/:id", s.RegexUpdate)
+		regex.DELETE("/:id", s.RegexDelete)
+		regex.POST("/:id/actions/:action", s.RegexAction)
+	}
+
+	// 网卡挂载，对应 example_set_nested
+	setNested := r.Group("/set_nested")
+	{
+		setNested.GET("", s.SetNestedList)
+		setNested.POST("", s.SetNestedCreate)
+		setNested.GET("/:id", s.SetNestedDetail)
+		setNested.PUT("/:id", s.SetNestedUpdate)
+		setNested.DELETE("/:id", s.SetNestedDelete)
+	}
+
+	// 虚机网络配置，对应 example_server_networks
+	serverNetworks := r.Group("/server_networks")
+	{
+		serverNetworks.GET("", s.ServerNetworksList)
+		serverNetworks.POST("", s.ServerNetworksCreate)
+		serverNetworks.GET("/:id", s.ServerNetworksDetail)
+		serverNetworks.PUT("/:id", s.ServerNetworksUpdate)
+		serverNetworks.DELETE("/:id", s.ServerNetworksDelete)
+	}
+
+	// 任意结构的文档，对应 example_document
+	document := r.Group("/document")
+	{
+		document.GET("", s.DocumentList)
+		document.POST("", s.DocumentCreate)
+		document.GET("/:id", s.DocumentDetail)
+		document.PUT("/:id", s.DocumentUpdate)
+		document.DELETE("/:id", s.DocumentDelete)
+	}
+
+	// 异步操作
+	r.GET("/operations/:id", s.OperationDetail)
+
+	return r
+}
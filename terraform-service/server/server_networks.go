@@ -0,0 +1,184 @@
+package server
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ServerNetworks 对应 provider 中的 example_server_networks 资源（虚机网络配置）
+// Networks 保持请求中的顺序，第一个网卡为主网卡
+type ServerNetworks struct {
+	Id             string            `json:"id"`
+	Networks       []Network         `json:"networks"`
+	SecurityGroups []string          `json:"security_groups"`
+	Tags           map[string]string `json:"tags,omitempty"`
+}
+
+type Network struct {
+	Uuid    string `json:"uuid"`
+	FixedIp string `json:"fixed_ip,omitempty"`
+	Port    string `json:"port"`
+	Mac     string `json:"mac"`
+	Primary bool   `json:"primary"`
+}
+
+// 每台虚机最多挂载的网卡与安全组数量
+const (
+	maxNetworks       = 8
+	maxSecurityGroups = 5
+)
+
+func (s *service) ServerNetworksList(c *gin.Context) {
+	c.JSON(http.StatusOK, filterByTags(c, s.storeFor(c).ServerNetworks().List(), func(item ServerNetworks) map[string]string {
+		return item.Tags
+	}))
+}
+
+func (s *service) ServerNetworksCreate(c *gin.Context) {
+	if s.replayOperation(c) {
+		return
+	}
+
+	var req ServerNetworks
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithMessage(c, http.StatusBadRequest, "invalid request body: %s", err)
+		return
+	}
+
+	if !validateServerNetworks(c, req) {
+		return
+	}
+
+	req.Id = newID("srv")
+	assignPorts(&req, nil)
+
+	// 挂载网卡是异步操作
+	s.acceptOperation(c, req.Id, func(store Store) {
+		store.ServerNetworks().Put(req.Id, req)
+	})
+}
+
+func (s *service) ServerNetworksDetail(c *gin.Context) {
+	item, ok := s.storeFor(c).ServerNetworks().Get(c.Param("id"))
+	if !ok {
+		abortWithMessage(c, http.StatusNotFound, "server_networks %q not found", c.Param("id"))
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+func (s *service) ServerNetworksUpdate(c *gin.Context) {
+	id := c.Param("id")
+	current, ok := s.storeFor(c).ServerNetworks().Get(id)
+	if !ok {
+		abortWithMessage(c, http.StatusNotFound, "server_networks %q not found", id)
+		return
+	}
+
+	var req ServerNetworks
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithMessage(c, http.StatusBadRequest, "invalid request body: %s", err)
+		return
+	}
+
+	if !validateServerNetworks(c, req) {
+		return
+	}
+
+	req.Id = id
+	assignPorts(&req, current.Networks)
+	s.storeFor(c).ServerNetworks().Put(id, req)
+
+	c.JSON(http.StatusOK, req)
+}
+
+func (s *service) ServerNetworksDelete(c *gin.Context) {
+	if !s.storeFor(c).ServerNetworks().Delete(c.Param("id")) {
+		abortWithMessage(c, http.StatusNotFound, "server_networks %q not found", c.Param("id"))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// assignPorts 为每个网卡分配端口与 MAC 地址，并把第一个网卡标记为主网卡。
+// 已经挂载的网络沿用原来的端口，调整网卡顺序不会重新分配
+func assignPorts(req *ServerNetworks, current []Network) {
+	existing := make(map[string]Network, len(current))
+	for _, network := range current {
+		existing[network.Uuid] = network
+	}
+
+	for i := range req.Networks {
+		network := &req.Networks[i]
+		network.Primary = i == 0
+
+		if prev, ok := existing[network.Uuid]; ok {
+			network.Port = prev.Port
+			network.Mac = prev.Mac
+			continue
+		}
+
+		network.Port = newID("port")
+		network.Mac = newMAC()
+	}
+
+	if req.SecurityGroups == nil {
+		req.SecurityGroups = []string{}
+	}
+}
+
+// newMAC 生成本地管理的单播 MAC 地址
+func newMAC() string {
+	b := make([]byte, 6)
+	_, _ = rand.Read(b)
+	b[0] = b[0]&0xfe | 0x02
+
+	return net.HardwareAddr(b).String()
+}
+
+// validateServerNetworks 校验网卡与安全组，错误字段带上在请求中的下标
+func validateServerNetworks(c *gin.Context, req ServerNetworks) bool {
+	var errs []FieldError
+
+	if len(req.Networks) == 0 || len(req.Networks) > maxNetworks {
+		errs = append(errs, FieldError{Field: "network", Message: fmt.Sprintf("between 1 and %d networks are required, got %d", maxNetworks, len(req.Networks))})
+	}
+
+	seen := make(map[string]int)
+	for i, network := range req.Networks {
+		if network.Uuid == "" {
+			errs = append(errs, FieldError{Field: fmt.Sprintf("network[%d].uuid", i), Message: "uuid is required"})
+		} else if j, ok := seen[network.Uuid]; ok {
+			errs = append(errs, FieldError{Field: fmt.Sprintf("network[%d].uuid", i), Message: fmt.Sprintf("network %s is already attached by network[%d]", network.Uuid, j)})
+		} else {
+			seen[network.Uuid] = i
+		}
+
+		if network.FixedIp != "" && net.ParseIP(network.FixedIp) == nil {
+			errs = append(errs, FieldError{Field: fmt.Sprintf("network[%d].fixed_ip", i), Message: fmt.Sprintf("%q is not a valid IP address", network.FixedIp)})
+		}
+	}
+
+	if len(req.SecurityGroups) > maxSecurityGroups {
+		errs = append(errs, FieldError{Field: "security_group", Message: fmt.Sprintf("at most %d security groups are allowed, got %d", maxSecurityGroups, len(req.SecurityGroups))})
+	}
+
+	for i, group := range req.SecurityGroups {
+		if group == "" {
+			errs = append(errs, FieldError{Field: fmt.Sprintf("security_group[%d].id", i), Message: "id is required"})
+		}
+	}
+
+	if len(errs) > 0 {
+		abortWithErrors(c, http.StatusUnprocessableEntity, errs...)
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,131 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// TestOpenAPIRoutes 保证 openapi.json 描述的接口与注册的路由一一对应，
+// provider 的资源代码由它生成
+func TestOpenAPIRoutes(t *testing.T) {
+	var spec struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(OpenAPI, &spec); err != nil {
+		t.Fatalf("invalid openapi.json: %s", err)
+	}
+
+	var described []string
+	for path, item := range spec.Paths {
+		// {id} 形式的路径参数对应 gin 的 :id
+		path = strings.NewReplacer("{", ":", "}", "").Replace(path)
+
+		for method := range item {
+			switch strings.ToUpper(method) {
+			case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+				described = append(described, strings.ToUpper(method)+" "+path)
+			}
+		}
+	}
+	sort.Strings(described)
+
+	engine, ok := New(Options{}).(*gin.Engine)
+	if !ok {
+		t.Fatalf("expected New to return a *gin.Engine")
+	}
+
+	var registered []string
+	for _, route := range engine.Routes() {
+		registered = append(registered, route.Method+" "+route.Path)
+	}
+	sort.Strings(registered)
+
+	if strings.Join(described, "\n") != strings.Join(registered, "\n") {
+		t.Errorf("openapi.json describes\n%s\nbut the server registers\n%s", strings.Join(described, "\n"), strings.Join(registered, "\n"))
+	}
+}
+
+// request 向 h 发送请求并返回响应，body 不为空时编码为 JSON 请求体
+func request(t *testing.T, h http.Handler, method, path string, body interface{}, header http.Header) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("encoding request body: %s", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	return w
+}
+
+// decode 解码响应体，code 与期望的状态码不同时测试失败
+func decode[T any](t *testing.T, w *httptest.ResponseRecorder, code int) T {
+	t.Helper()
+
+	var v T
+	if w.Code != code {
+		t.Fatalf("expected status %d, got %d: %s", code, w.Code, w.Body)
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &v); err != nil {
+		t.Fatalf("invalid response body %s: %s", w.Body, err)
+	}
+
+	return v
+}
+
+// waitOperation 轮询异步任务直到任务结束
+func waitOperation(t *testing.T, h http.Handler, header http.Header, id string) Operation {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		op := decode[Operation](t, request(t, h, http.MethodGet, "/operations/"+id, nil, header), http.StatusOK)
+		if op.Status == OperationSucceeded || op.Status == OperationFailed {
+			return op
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("operation %s did not finish: %+v", id, op)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// fieldErrors 返回 422、409 等错误响应中的字段
+func fieldErrors(t *testing.T, w *httptest.ResponseRecorder, code int) []string {
+	t.Helper()
+
+	var fields []string
+	for _, err := range decode[ErrorResponse](t, w, code).Errors {
+		fields = append(fields, err.Field)
+	}
+
+	return fields
+}
@@ -0,0 +1,119 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetNested 对应 provider 中的 example_set_nested 资源（网卡挂载）
+type SetNested struct {
+	Id        string            `json:"id"`
+	SetNested []Nic             `json:"set_nested"`
+	Tags      map[string]string `json:"tags,omitempty"`
+}
+
+type Nic struct {
+	Uuid          string `json:"uuid"`
+	FixedIp       string `json:"fixed_ip,omitempty"`
+	EnableGateway bool   `json:"enable_gateway"`
+}
+
+func (s *service) SetNestedList(c *gin.Context) {
+	c.JSON(http.StatusOK, filterByTags(c, s.storeFor(c).SetNesteds().List(), func(item SetNested) map[string]string {
+		return item.Tags
+	}))
+}
+
+func (s *service) SetNestedCreate(c *gin.Context) {
+	if s.replayOperation(c) {
+		return
+	}
+
+	var req SetNested
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithMessage(c, http.StatusBadRequest, "invalid request body: %s", err)
+		return
+	}
+
+	if !validateSetNested(c, req) {
+		return
+	}
+
+	// 挂载网卡是异步操作
+	req.Id = newID("nic")
+	s.acceptOperation(c, req.Id, func(store Store) {
+		store.SetNesteds().Put(req.Id, req)
+	})
+}
+
+func (s *service) SetNestedDetail(c *gin.Context) {
+	item, ok := s.storeFor(c).SetNesteds().Get(c.Param("id"))
+	if !ok {
+		abortWithMessage(c, http.StatusNotFound, "set_nested %q not found", c.Param("id"))
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+func (s *service) SetNestedUpdate(c *gin.Context) {
+	id := c.Param("id")
+	if _, ok := s.storeFor(c).SetNesteds().Get(id); !ok {
+		abortWithMessage(c, http.StatusNotFound, "set_nested %q not found", id)
+		return
+	}
+
+	var req SetNested
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithMessage(c, http.StatusBadRequest, "invalid request body: %s", err)
+		return
+	}
+
+	if !validateSetNested(c, req) {
+		return
+	}
+
+	req.Id = id
+	s.storeFor(c).SetNesteds().Put(id, req)
+
+	c.JSON(http.StatusOK, req)
+}
+
+func (s *service) SetNestedDelete(c *gin.Context) {
+	if !s.storeFor(c).SetNesteds().Delete(c.Param("id")) {
+		abortWithMessage(c, http.StatusNotFound, "set_nested %q not found", c.Param("id"))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// validateSetNested 逐个校验网卡，错误字段带上网卡在请求中的下标
+func validateSetNested(c *gin.Context, req SetNested) bool {
+	var errs []FieldError
+
+	seen := make(map[string]int)
+	for i, nic := range req.SetNested {
+		if nic.Uuid == "" {
+			errs = append(errs, FieldError{Field: fmt.Sprintf("set_nested[%d].uuid", i), Message: "uuid is required"})
+		} else if j, ok := seen[nic.Uuid]; ok {
+			errs = append(errs, FieldError{Field: fmt.Sprintf("set_nested[%d].uuid", i), Message: fmt.Sprintf("network %s is already attached by set_nested[%d]", nic.Uuid, j)})
+		} else {
+			seen[nic.Uuid] = i
+		}
+
+		if nic.FixedIp != "" && net.ParseIP(nic.FixedIp) == nil {
+			errs = append(errs, FieldError{Field: fmt.Sprintf("set_nested[%d].fixed_ip", i), Message: fmt.Sprintf("%q is not a valid IP address", nic.FixedIp)})
+		}
+	}
+
+	if len(errs) > 0 {
+		abortWithErrors(c, http.StatusUnprocessableEntity, errs...)
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,33 @@
+package server
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestSetNested_Validation(t *testing.T) {
+	h := New(Options{})
+
+	req := SetNested{SetNested: []Nic{
+		{Uuid: "net-1", FixedIp: "10.0.0.1"},
+		{Uuid: "net-1"},
+		{FixedIp: "10.0.0"},
+	}}
+
+	// 错误的字段与 provider 属性的路径一致
+	fields := fieldErrors(t, request(t, h, http.MethodPost, "/set_nested", req, nil), http.StatusUnprocessableEntity)
+	if want := []string{"set_nested[1].uuid", "set_nested[2].uuid", "set_nested[2].fixed_ip"}; !reflect.DeepEqual(fields, want) {
+		t.Errorf("expected errors of %v, got %v", want, fields)
+	}
+
+	op := decode[Operation](t, request(t, h, http.MethodPost, "/set_nested", SetNested{SetNested: req.SetNested[:1]}, nil), http.StatusAccepted)
+	if op = waitOperation(t, h, nil, op.Id); op.Status != OperationSucceeded {
+		t.Fatalf("expected the operation to succeed, got %+v", op)
+	}
+
+	fields = fieldErrors(t, request(t, h, http.MethodPut, "/set_nested/"+op.ResourceId, req, nil), http.StatusUnprocessableEntity)
+	if len(fields) != 3 {
+		t.Errorf("expected the update to be validated, got %v", fields)
+	}
+}
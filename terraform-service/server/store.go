@@ -0,0 +1,184 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sort"
+	"sync"
+)
+
+// Collection 是一类后端对象的存储，实现需要保证并发安全
+type Collection[T any] interface {
+	Get(id string) (T, bool)
+	Put(id string, item T)
+	// Delete 删除对象，对象不存在时返回 false
+	Delete(id string) bool
+	// List 按 ID 排序返回所有对象，保证列表接口输出稳定
+	List() []T
+	// Keys 按排序返回所有对象的 ID
+	Keys() []string
+	// Update 原子地修改对象，对象不存在时返回 false
+	Update(id string, fn func(item *T)) bool
+}
+
+// Store 保存服务的全部状态。默认使用 NewMemoryStore，
+// 需要持久化或者在测试中预置数据时可以替换为其它实现
+type Store interface {
+	Regexes() Collection[Regex]
+	SetNesteds() Collection[SetNested]
+	ServerNetworks() Collection[ServerNetworks]
+	Documents() Collection[Document]
+	Operations() Collection[Operation]
+	// IdempotencyKeys 记录 Idempotency-Key 与异步创建的任务 ID，
+	// 或者同步创建的对象 ID 的对应关系
+	IdempotencyKeys() Collection[string]
+	// Tokens 保存 /oauth/token 签发的访问令牌
+	Tokens() Collection[Token]
+}
+
+type memoryStore struct {
+	regexes         *memoryCollection[Regex]
+	setNesteds      *memoryCollection[SetNested]
+	serverNetworks  *memoryCollection[ServerNetworks]
+	documents       *memoryCollection[Document]
+	operations      *memoryCollection[Operation]
+	idempotencyKeys *memoryCollection[string]
+	tokens          *memoryCollection[Token]
+}
+
+// NewMemoryStore 返回进程内的内存存储，进程退出后数据丢失
+func NewMemoryStore() Store {
+	return &memoryStore{
+		regexes:         newMemoryCollection[Regex](),
+		setNesteds:      newMemoryCollection[SetNested](),
+		serverNetworks:  newMemoryCollection[ServerNetworks](),
+		documents:       newMemoryCollection[Document](),
+		operations:      newMemoryCollection[Operation](),
+		idempotencyKeys: newMemoryCollection[string](),
+		tokens:          newMemoryCollection[Token](),
+	}
+}
+
+func (s *memoryStore) Regexes() Collection[Regex]                 { return s.regexes }
+func (s *memoryStore) SetNesteds() Collection[SetNested]          { return s.setNesteds }
+func (s *memoryStore) ServerNetworks() Collection[ServerNetworks] { return s.serverNetworks }
+func (s *memoryStore) Documents() Collection[Document]            { return s.documents }
+func (s *memoryStore) Operations() Collection[Operation]          { return s.operations }
+func (s *memoryStore) IdempotencyKeys() Collection[string]        { return s.idempotencyKeys }
+func (s *memoryStore) Tokens() Collection[Token]                  { return s.tokens }
+
+// memoryCollection 是按 ID 存放后端对象的内存存储，并发安全
+type memoryCollection[T any] struct {
+	mu    sync.RWMutex
+	items map[string]T
+}
+
+func newMemoryCollection[T any]() *memoryCollection[T] {
+	return &memoryCollection[T]{
+		items: make(map[string]T),
+	}
+}
+
+func (c *memoryCollection[T]) Get(id string) (T, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	item, ok := c.items[id]
+
+	return item, ok
+}
+
+func (c *memoryCollection[T]) Put(id string, item T) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.items[id] = item
+}
+
+func (c *memoryCollection[T]) Delete(id string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, ok := c.items[id]
+	delete(c.items, id)
+
+	return ok
+}
+
+func (c *memoryCollection[T]) List() []T {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	ids := c.keys()
+	items := make([]T, 0, len(ids))
+	for _, id := range ids {
+		items = append(items, c.items[id])
+	}
+
+	return items
+}
+
+func (c *memoryCollection[T]) Keys() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.keys()
+}
+
+// keys 返回排序后的 ID，调用方需要持有锁
+func (c *memoryCollection[T]) keys() []string {
+	ids := make([]string, 0, len(c.items))
+	for id := range c.items {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	return ids
+}
+
+func (c *memoryCollection[T]) Update(id string, fn func(item *T)) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item, ok := c.items[id]
+	if !ok {
+		return false
+	}
+
+	fn(&item)
+	c.items[id] = item
+
+	return true
+}
+
+// snapshot 返回全部对象的副本
+func (c *memoryCollection[T]) snapshot() map[string]T {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	items := make(map[string]T, len(c.items))
+	for id, item := range c.items {
+		items[id] = item
+	}
+
+	return items
+}
+
+// load 用 items 替换全部对象
+func (c *memoryCollection[T]) load(items map[string]T) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.items = make(map[string]T, len(items))
+	for id, item := range items {
+		c.items[id] = item
+	}
+}
+
+// newID 生成形如 "vm-1a2b3c4d5e6f7a8b" 的 ID
+func newID(prefix string) string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+
+	return prefix + "-" + hex.EncodeToString(b)
+}
@@ -0,0 +1,65 @@
+package server
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestMemoryCollection(t *testing.T) {
+	c := newMemoryCollection[string]()
+
+	c.Put("b", "second")
+	c.Put("a", "first")
+
+	if item, ok := c.Get("a"); !ok || item != "first" {
+		t.Errorf("expected first, got %q, %t", item, ok)
+	}
+	if _, ok := c.Get("missing"); ok {
+		t.Errorf("expected no missing item")
+	}
+
+	// List 与 Keys 按 ID 排序
+	if keys := c.Keys(); !reflect.DeepEqual(keys, []string{"a", "b"}) {
+		t.Errorf("expected sorted keys, got %v", keys)
+	}
+	if items := c.List(); !reflect.DeepEqual(items, []string{"first", "second"}) {
+		t.Errorf("expected items sorted by ID, got %v", items)
+	}
+
+	if !c.Update("a", func(item *string) { *item += "!" }) {
+		t.Errorf("expected a to be updated")
+	}
+	if item, _ := c.Get("a"); item != "first!" {
+		t.Errorf("expected first!, got %q", item)
+	}
+	if c.Update("missing", func(item *string) {}) {
+		t.Errorf("expected no update of a missing item")
+	}
+
+	if !c.Delete("a") || c.Delete("a") {
+		t.Errorf("expected a to be deleted once")
+	}
+	if keys := c.Keys(); !reflect.DeepEqual(keys, []string{"b"}) {
+		t.Errorf("expected only b, got %v", keys)
+	}
+}
+
+func TestMemoryCollection_ConcurrentUpdate(t *testing.T) {
+	c := newMemoryCollection[Operation]()
+	c.Put("op", Operation{Id: "op"})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.Update("op", func(op *Operation) { op.Progress++ })
+		}()
+	}
+	wg.Wait()
+
+	if op, _ := c.Get("op"); op.Progress != 100 {
+		t.Errorf("expected 100 updates, got %d", op.Progress)
+	}
+}
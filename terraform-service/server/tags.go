@@ -0,0 +1,35 @@
+package server
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// matchTags 判断对象的标签是否满足列表接口的 tag 查询参数。
+// tag 可以重复出现，"key=value" 要求标签取值相同，"key" 只要求存在该标签，
+// 所有条件都满足才算匹配
+func matchTags(c *gin.Context, tags map[string]string) bool {
+	for _, filter := range c.QueryArray("tag") {
+		key, value, hasValue := strings.Cut(filter, "=")
+
+		v, ok := tags[key]
+		if !ok || (hasValue && v != value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// filterByTags 返回满足 tag 查询参数的对象
+func filterByTags[T any](c *gin.Context, items []T, tags func(item T) map[string]string) []T {
+	filtered := make([]T, 0, len(items))
+	for _, item := range items {
+		if matchTags(c, tags(item)) {
+			filtered = append(filtered, item)
+		}
+	}
+
+	return filtered
+}